@@ -0,0 +1,61 @@
+package webhook
+
+import "strings"
+
+// StripANSI removes terminal escape sequences such as color codes from text. Discord
+// only renders them inside ```ansi code blocks and shows them as garbage anywhere else.
+func StripANSI(text string) string {
+	if !strings.ContainsAny(text, "\x1b\u009b") {
+		return text
+	}
+	var b strings.Builder
+	b.Grow(len(text))
+	for i := 0; i < len(text); {
+		switch {
+		case text[i] == 0x1b && i+1 < len(text) && text[i+1] == '[':
+			i = skipCSI(text, i+2)
+		case strings.HasPrefix(text[i:], "\u009b"):
+			i = skipCSI(text, i+len("\u009b"))
+		case text[i] == 0x1b && i+1 < len(text) && text[i+1] == ']':
+			i = skipOSC(text, i+2)
+		case text[i] == 0x1b:
+			// Two-character sequences such as ESC 7
+			i++
+			if i < len(text) && text[i] < 0x80 {
+				i++
+			}
+		default:
+			b.WriteByte(text[i])
+			i++
+		}
+	}
+	return b.String()
+}
+
+// skipCSI returns the index after the control sequence whose parameters start at i,
+// which ends with a byte in the range @ to ~
+func skipCSI(text string, i int) int {
+	for i < len(text) {
+		c := text[i]
+		i++
+		if c >= 0x40 && c <= 0x7e {
+			break
+		}
+	}
+	return i
+}
+
+// skipOSC returns the index after the operating system command starting at i, such as
+// a hyperlink or window title, which ends with BEL or ESC \
+func skipOSC(text string, i int) int {
+	for i < len(text) {
+		if text[i] == 0x07 {
+			return i + 1
+		}
+		if text[i] == 0x1b && i+1 < len(text) && text[i+1] == '\\' {
+			return i + 2
+		}
+		i++
+	}
+	return i
+}
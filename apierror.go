@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxErrorMessageLength bounds the text of error responses that are not JSON, such as
+// HTML pages of proxies, quoted in errors
+const maxErrorMessageLength = 200
+
+// APIError is returned when Discord answered with an error status. It carries
+// Discord's explanation, such as {"code": 50035, "message": "Invalid Form Body"}.
+type APIError struct {
+	StatusCode int
+	// Code is Discord's JSON error code, if the response had one
+	Code ErrorCode
+	// Message is Discord's error message, or the start of the body if it was not JSON
+	Message string
+	// Errors holds Discord's details of the invalid fields of the payload, if any
+	Errors json.RawMessage
+
+	// RetryAfter is how long Discord asked to wait before retrying, if it did
+	RetryAfter time.Duration
+
+	// webhookInvalid is set when the error shows the webhook was revoked or deleted
+	webhookInvalid bool
+}
+
+func (e *APIError) Error() string {
+	text := fmt.Sprintf("discord webhook returned status %d", e.StatusCode)
+	if e.Message != "" {
+		text += ": " + e.Message
+	}
+	if e.Code != 0 {
+		text += fmt.Sprintf(" (code %d)", int(e.Code))
+	}
+	fieldErrors := e.FieldErrors()
+	paths := make([]string, 0, len(fieldErrors))
+	for path := range fieldErrors {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		text += fmt.Sprintf("; %s: %s", path, strings.Join(fieldErrors[path], " "))
+	}
+	return text
+}
+
+// Is matches the sentinel errors describing the failure, such as ErrRateLimited
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrWebhookNotFound:
+		return e.StatusCode == http.StatusNotFound && (e.Code == ErrorCodeUnknownWebhook || e.webhookInvalid)
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrPayloadTooLarge:
+		return e.StatusCode == http.StatusRequestEntityTooLarge || e.Code == ErrorCodeRequestTooLarge
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest && e.Code == ErrorCodeInvalidFormBody
+	}
+	return false
+}
+
+// Temporary reports whether retrying may succeed, as for rate limits and server errors
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// FieldErrors returns the messages of Discord's details by the path of the invalid
+// field, such as "embeds.0.title": ["Must be 256 or fewer in length."]
+func (e *APIError) FieldErrors() map[string][]string {
+	fieldErrors := make(map[string][]string)
+	var tree map[string]json.RawMessage
+	if len(e.Errors) == 0 || json.Unmarshal(e.Errors, &tree) != nil {
+		return fieldErrors
+	}
+	collectFieldErrors("", tree, fieldErrors)
+	return fieldErrors
+}
+
+// collectFieldErrors walks Discord's nested error details, which list the errors of
+// each field under "_errors"
+func collectFieldErrors(path string, tree map[string]json.RawMessage, fieldErrors map[string][]string) {
+	for key, value := range tree {
+		if key == "_errors" {
+			var details []struct {
+				Message string `json:"message"`
+			}
+			if json.Unmarshal(value, &details) == nil {
+				for _, detail := range details {
+					fieldErrors[path] = append(fieldErrors[path], detail.Message)
+				}
+			}
+			continue
+		}
+		var subtree map[string]json.RawMessage
+		if json.Unmarshal(value, &subtree) != nil {
+			continue
+		}
+		subpath := key
+		if path != "" {
+			subpath = path + "." + key
+		}
+		collectFieldErrors(subpath, subtree, fieldErrors)
+	}
+}
+
+// readAPIError reads the error of a failed response, draining a bounded part of its
+// body so the connection can be reused
+func readAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode, RetryAfter: retryAfter(resp.Header)}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodySize))
+
+	var body struct {
+		Code    ErrorCode       `json:"code"`
+		Message string          `json:"message"`
+		Errors  json.RawMessage `json:"errors"`
+	}
+	if json.Unmarshal(data, &body) == nil {
+		apiErr.Code, apiErr.Message, apiErr.Errors = body.Code, body.Message, body.Errors
+		return apiErr
+	}
+	if text := strings.TrimSpace(string(bytes.ToValidUTF8(data, nil))); text != "" {
+		apiErr.Message = truncateRunes(strings.Join(strings.Fields(text), " "), maxErrorMessageLength)
+	}
+	return apiErr
+}
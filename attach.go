@@ -0,0 +1,230 @@
+package webhook
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Attachment is a file attached to a message. In payloads, it describes an uploaded
+// file by the index of its File, or a file kept from an earlier version of the message.
+type Attachment struct {
+	ID          string `json:"id"`
+	Filename    string `json:"filename,omitempty"`
+	Description string `json:"description,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Size        int    `json:"size,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// File is a file to upload with a message. Its content is streamed from Open, which is
+// called again for every retry, so files of any size are never buffered in memory.
+type File struct {
+	Name string
+	// Description is the alt text of the file
+	Description string
+	Open        func() (io.ReadCloser, error)
+	// Size is the length of the content in bytes, or 0 when it is not known
+	Size int64
+	// ContentType is the media type of the file's part, such as "audio/ogg" or
+	// "text/plain; charset=utf-8". It is detected from the extension, or else from
+	// the content, when empty; set it when that guesses wrong, since Discord decides
+	// by it how to show the file.
+	ContentType string
+}
+
+// FileFromPath uploads the file at path under its base name
+func FileFromPath(path string) File {
+	file := File{
+		Name: filepath.Base(path),
+		Open: func() (io.ReadCloser, error) {
+			return os.Open(path)
+		},
+	}
+	// A missing file is reported when the upload opens it
+	if info, err := os.Stat(path); err == nil {
+		file.Size = info.Size()
+	}
+	return file
+}
+
+// FileFromBytes uploads data under the name
+func FileFromBytes(name string, data []byte) File {
+	return File{
+		Name: name,
+		Size: int64(len(data)),
+		Open: func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data)), nil
+		},
+	}
+}
+
+// FileFromReader uploads what is read from r under the name. The reader can only be
+// read once, so a request that is retried after it started uploading fails.
+func FileFromReader(name string, r io.Reader) File {
+	var once sync.Once
+	return File{
+		Name: name,
+		Open: func() (io.ReadCloser, error) {
+			opened := false
+			once.Do(func() { opened = true })
+			if !opened {
+				return nil, fmt.Errorf("file %s cannot be read again", name)
+			}
+			return io.NopCloser(r), nil
+		},
+	}
+}
+
+// SendFiles sends the payload with the files attached and returns the message, whose
+// attachments link to the uploaded files. The request body is streamed, and payloads
+// with files are never spooled or mirrored.
+func (c *Client) SendFiles(ctx context.Context, webhookPayload Webhook, files ...File) (Message, error) {
+	ctx, cancel := c.operationContext(ctx)
+	defer cancel()
+	files = c.compressFiles(c.downscaleImages(files))
+	webhookPayload = withFileAttachments(c.prepare(c.defaults.apply(webhookPayload)), files)
+	if err := c.checkPayloadLinks(webhookPayload); err != nil {
+		return Message{}, err
+	}
+	var message Message
+	err := c.withBaseURL(ctx, func(webhookURL string) error {
+		requestURL, err := withQueryParam(webhookURL, "wait", "true")
+		if err != nil {
+			return err
+		}
+		if len(webhookPayload.Components) > 0 {
+			if requestURL, err = withQueryParam(requestURL, "with_components", "true"); err != nil {
+				return err
+			}
+		}
+		return c.doMultipart(ctx, http.MethodPost, requestURL, webhookPayload, files, &message)
+	})
+	if err != nil {
+		return Message{}, err
+	}
+	return message, c.verifyMessage(webhookPayload, message)
+}
+
+// withFileAttachments returns a copy of the payload describing the files as attachments,
+// after the attachments it already has. Discord matches them to the files by index.
+func withFileAttachments(webhookPayload Webhook, files []File) Webhook {
+	attachments := make([]Attachment, 0, len(webhookPayload.Attachments)+len(files))
+	attachments = append(attachments, webhookPayload.Attachments...)
+	for i, file := range files {
+		attachments = append(attachments, Attachment{
+			ID:          strconv.Itoa(i),
+			Filename:    file.Name,
+			Description: file.Description,
+		})
+	}
+	webhookPayload.Attachments = attachments
+	return webhookPayload
+}
+
+// doMultipart sends the payload and files as a multipart request, which is written
+// into the request body while it is sent
+func (c *Client) doMultipart(ctx context.Context, method, requestURL string, webhookPayload Webhook, files []File, out any) error {
+	jsonData, err := json.Marshal(webhookPayload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON payload: %v", err)
+	}
+	if jsonData, err = c.applyJSONHooks(jsonData); err != nil {
+		return err
+	}
+	for _, file := range files {
+		if file.ContentType == "" {
+			continue
+		}
+		if _, _, err := mime.ParseMediaType(file.ContentType); err != nil {
+			return fmt.Errorf("file %s has an invalid content type %q: %v", file.Name, file.ContentType, err)
+		}
+	}
+
+	reader, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		writer.CloseWithError(writeMultipart(form, jsonData, files))
+	}()
+	// Closing the reader stops the writer when the request ended before reading the whole body
+	defer reader.Close()
+	return c.roundTrip(ctx, method, requestURL, jsonData, reader, form.FormDataContentType(), out)
+}
+
+// quoteEscaper escapes file names in Content-Disposition headers
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// sniffLength is how much of a file is read to detect its content type
+const sniffLength = 512
+
+// writeMultipart writes the payload_json part and one files[i] part per file
+func writeMultipart(form *multipart.Writer, jsonData []byte, files []File) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="payload_json"`)
+	header.Set("Content-Type", "application/json")
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(jsonData); err != nil {
+		return err
+	}
+
+	for i, file := range files {
+		if err := writeFilePart(form, i, file); err != nil {
+			return err
+		}
+	}
+	return form.Close()
+}
+
+// writeFilePart streams a single file into the form
+func writeFilePart(form *multipart.Writer, i int, file File) error {
+	if file.Open == nil {
+		return fmt.Errorf("file %s has no content", file.Name)
+	}
+	content, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open file %s: %v", file.Name, err)
+	}
+	defer content.Close()
+
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(file.Name))
+	}
+	var reader io.Reader = content
+	if contentType == "" {
+		// Sniff the content, which http.DetectContentType needs at most 512 bytes of
+		buffered := bufio.NewReaderSize(content, sniffLength)
+		head, err := buffered.Peek(sniffLength)
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read file %s: %v", file.Name, err)
+		}
+		contentType = http.DetectContentType(head)
+		reader = buffered
+	}
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="files[%d]"; filename="%s"`, i, quoteEscaper.Replace(file.Name)))
+	header.Set("Content-Type", contentType)
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, reader); err != nil {
+		return fmt.Errorf("failed to read file %s: %v", file.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package webhook
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditRecord describes a single request made to Discord
+type AuditRecord struct {
+	Time   time.Time `json:"time"`
+	Method string    `json:"method"`
+	// URL is the request URL with the webhook token redacted
+	URL string `json:"url"`
+	// PayloadHash is the hex SHA-256 of the JSON body, for matching records to stored payloads
+	PayloadHash string `json:"payload_hash,omitempty"`
+	// Summary describes the payload in a line; see Webhook.Summary
+	Summary string `json:"summary,omitempty"`
+	// Status is the HTTP status of the response, or 0 when Discord could not be reached
+	Status    int    `json:"status,omitempty"`
+	MessageID string `json:"message_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+	// Duration is how long the request took, in milliseconds
+	Duration int64 `json:"duration_ms"`
+}
+
+// AuditSink receives a record of every request, such as for compliance review of
+// outbound notifications. Implementations must be safe for concurrent use and
+// should not block for long, since the request waits for them.
+type AuditSink interface {
+	Record(record AuditRecord)
+}
+
+// WithAuditSink records every request the client makes, including retries, edits and deletes
+func WithAuditSink(sink AuditSink) ClientOption {
+	return func(c *Client) {
+		c.audit = sink
+	}
+}
+
+// recordAudit passes the outcome of a request to the audit sink
+func (c *Client) recordAudit(start time.Time, method, requestURL string, body []byte, status int, out any, err error) {
+	record := AuditRecord{
+		Time:     start.UTC(),
+		Method:   method,
+		URL:      RedactWebhookURL(requestURL),
+		Status:   status,
+		Duration: time.Since(start).Milliseconds(),
+	}
+	if body != nil {
+		sum := sha256.Sum256(body)
+		record.PayloadHash = hex.EncodeToString(sum[:])
+		var payload Webhook
+		if json.Unmarshal(body, &payload) == nil {
+			record.Summary = payload.Summary()
+		}
+	}
+	if message, ok := out.(*Message); ok && err == nil {
+		record.MessageID = message.ID
+	} else if u, parseErr := url.Parse(requestURL); parseErr == nil {
+		if i := strings.LastIndex(u.Path, "/messages/"); i >= 0 {
+			record.MessageID = u.Path[i+len("/messages/"):]
+		}
+	}
+	if err != nil {
+		record.Error = RedactError(err).Error()
+	}
+	c.audit.Record(record)
+}
+
+// FileAuditSink appends audit records to a file as JSON lines
+type FileAuditSink struct {
+	// OnError is called when a record could not be written
+	OnError func(err error)
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileAuditSink opens the file at path for appending, creating it readable only by the owner
+func NewFileAuditSink(path string) (*FileAuditSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %v", err)
+	}
+	return &FileAuditSink{file: file}, nil
+}
+
+// Record appends the record as a single line
+func (s *FileAuditSink) Record(record AuditRecord) {
+	data, err := json.Marshal(record)
+	if err == nil {
+		s.mu.Lock()
+		_, err = s.file.Write(append(data, '\n'))
+		s.mu.Unlock()
+	}
+	if err != nil && s.OnError != nil {
+		s.OnError(fmt.Errorf("failed to write audit record: %v", err))
+	}
+}
+
+// Close closes the file
+func (s *FileAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// sqlIdentifier matches table names that are safe to use unquoted
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLAuditSink inserts audit records into a database table, such as one in SQLite.
+// The database driver is left to the application; statements use ? placeholders,
+// as SQLite and MySQL do.
+type SQLAuditSink struct {
+	db     *sql.DB
+	insert string
+
+	// OnError is called when a record could not be inserted
+	OnError func(err error)
+}
+
+// NewSQLAuditSink records into the table, creating it if it does not exist. Tables
+// created by versions without the summary column need it added first:
+//
+//	ALTER TABLE webhook_audit ADD COLUMN summary TEXT
+func NewSQLAuditSink(db *sql.DB, table string) (*SQLAuditSink, error) {
+	if !sqlIdentifier.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+	create := "CREATE TABLE IF NOT EXISTS " + table + ` (
+	time TEXT NOT NULL,
+	method TEXT NOT NULL,
+	url TEXT NOT NULL,
+	payload_hash TEXT,
+	summary TEXT,
+	status INTEGER,
+	message_id TEXT,
+	error TEXT,
+	duration_ms INTEGER
+)`
+	if _, err := db.Exec(create); err != nil {
+		return nil, fmt.Errorf("failed to create audit table: %v", err)
+	}
+	return &SQLAuditSink{
+		db: db,
+		insert: "INSERT INTO " + table +
+			" (time, method, url, payload_hash, summary, status, message_id, error, duration_ms) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+	}, nil
+}
+
+// Record inserts the record as a row
+func (s *SQLAuditSink) Record(record AuditRecord) {
+	_, err := s.db.Exec(s.insert, record.Time.Format(time.RFC3339Nano), record.Method, record.URL,
+		record.PayloadHash, record.Summary, record.Status, record.MessageID, record.Error, record.Duration)
+	if err != nil && s.OnError != nil {
+		s.OnError(fmt.Errorf("failed to insert audit record: %v", err))
+	}
+}
@@ -0,0 +1,85 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/webhooktest"
+)
+
+func TestSQLAuditSinkRecordsSummary(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	db, err := openFakeDB(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	sink, err := webhook.NewSQLAuditSink(db, "webhook_audit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink.OnError = func(err error) { t.Errorf("audit: %v", err) }
+
+	embed, _ := webhook.CreateEmbed("Deploy failed", "", "", 0)
+	client := webhook.NewClient(server.URL, webhook.WithAuditSink(sink))
+	if err := client.Send(context.Background(), webhook.Webhook{Embeds: []webhook.Embed{embed}}); err != nil {
+		t.Fatal(err)
+	}
+
+	var summary, url string
+	if err := db.QueryRow("SELECT summary, url FROM webhook_audit").Scan(&summary, &url); err != nil {
+		t.Fatal(err)
+	}
+	if summary != "embed 'Deploy failed'" {
+		t.Errorf("summary = %q, want %q", summary, "embed 'Deploy failed'")
+	}
+	if strings.Contains(url, webhooktest.WebhookToken) {
+		t.Errorf("url leaks the webhook token: %q", url)
+	}
+}
+
+func TestAuditRedactsNetworkErrors(t *testing.T) {
+	var records []webhook.AuditRecord
+	sink := auditFunc(func(record webhook.AuditRecord) { records = append(records, record) })
+	client := webhook.NewClient(unreachableURL(), webhook.WithAuditSink(sink))
+	if err := client.Send(context.Background(), webhook.Webhook{Content: "hello"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(records) != 1 {
+		t.Fatalf("recorded %d requests, want 1", len(records))
+	}
+	if records[0].Error == "" || strings.Contains(records[0].Error, "SECRETTOKEN") || strings.Contains(records[0].URL, "SECRETTOKEN") {
+		t.Errorf("record leaks the webhook token or lacks the error: %+v", records[0])
+	}
+}
+
+func TestAuditRedactsRedirectedURLs(t *testing.T) {
+	target := strings.Replace(unreachableURL(), "123/SECRETTOKEN", "456/OTHERTOKEN", 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target, http.StatusSeeOther)
+	}))
+	defer server.Close()
+
+	var records []webhook.AuditRecord
+	sink := auditFunc(func(record webhook.AuditRecord) { records = append(records, record) })
+	client := webhook.NewClient(server.URL+"/api/webhooks/123/SECRETTOKEN", webhook.WithMaxRetries(0), webhook.WithAuditSink(sink))
+	if err := client.Send(context.Background(), webhook.Webhook{Content: "hello"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(records) != 1 {
+		t.Fatalf("recorded %d requests, want 1", len(records))
+	}
+	if records[0].Error == "" || strings.Contains(records[0].Error, "OTHERTOKEN") || strings.Contains(records[0].Error, "SECRETTOKEN") {
+		t.Errorf("record leaks a webhook token or lacks the error: %+v", records[0])
+	}
+}
+
+// auditFunc adapts a function to an AuditSink
+type auditFunc func(record webhook.AuditRecord)
+
+func (f auditFunc) Record(record webhook.AuditRecord) { f(record) }
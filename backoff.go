@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Backoff decides how long a queued message waits before it is retried after a server
+// or network error. Rate limited messages always wait as long as Discord asks.
+type Backoff interface {
+	// Delay returns the wait before retry number attempt, counted from 0. previous is
+	// the wait before the last retry, or 0 before the first one.
+	Delay(attempt int, previous time.Duration) time.Duration
+}
+
+// BackoffFunc adapts a function to the Backoff interface
+type BackoffFunc func(attempt int, previous time.Duration) time.Duration
+
+// Delay calls f
+func (f BackoffFunc) Delay(attempt int, previous time.Duration) time.Duration {
+	return f(attempt, previous)
+}
+
+// Jitter randomizes retry delays, so that many clients failing at the same time, such as
+// during an outage, do not all retry at the same time as well
+type Jitter int
+
+const (
+	// JitterNone waits exactly the exponential delay
+	JitterNone Jitter = iota
+	// JitterFull waits a random time between 0 and the exponential delay
+	JitterFull
+	// JitterEqual waits half the exponential delay and a random time up to the other half
+	JitterEqual
+	// JitterDecorrelated waits a random time between the initial delay and three times
+	// the previous wait, up to the maximum
+	JitterDecorrelated
+)
+
+// ExponentialBackoff doubles the delay with every retry, from Initial up to Max, randomized
+// by Jitter. Zero durations default to 1s and 30s, which without jitter is the default
+// backoff of clients.
+type ExponentialBackoff struct {
+	Initial time.Duration
+	Max     time.Duration
+	Jitter  Jitter
+}
+
+// Delay returns the wait before retry number attempt
+func (b ExponentialBackoff) Delay(attempt int, previous time.Duration) time.Duration {
+	initial, maxDelay := b.Initial, b.Max
+	if initial <= 0 {
+		initial = initialRetryDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = maxRetryDelay
+	}
+
+	if b.Jitter == JitterDecorrelated {
+		if previous < initial {
+			previous = initial
+		}
+		delay := initial + randomDuration(3*previous-initial)
+		if delay > maxDelay || delay <= 0 {
+			delay = maxDelay
+		}
+		return delay
+	}
+
+	delay := maxDelay
+	if attempt < 63 && initial <= maxDelay>>attempt {
+		delay = initial << attempt
+	}
+	switch b.Jitter {
+	case JitterFull:
+		return randomDuration(delay)
+	case JitterEqual:
+		return delay/2 + randomDuration(delay-delay/2)
+	}
+	return delay
+}
+
+// WithBackoff sets how long queued messages wait before they are retried, such as
+// ExponentialBackoff{Jitter: JitterFull} for a fleet of clients sharing a webhook.
+// It defaults to ExponentialBackoff{}.
+func WithBackoff(backoff Backoff) ClientOption {
+	return func(c *Client) {
+		c.backoff = backoff
+	}
+}
+
+var (
+	// jitterMu guards jitterRand, which is seeded on its own so that processes started
+	// together do not draw the same delays
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// randomDuration returns a random duration in [0, n), or 0 when n is not positive
+func randomDuration(n time.Duration) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	jitterMu.Lock()
+	defer jitterMu.Unlock()
+	return time.Duration(jitterRand.Int63n(int64(n)))
+}
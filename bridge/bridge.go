@@ -0,0 +1,170 @@
+// Package bridge turns the webhook library into a small notification bridge.
+// It accepts arbitrary JSON on configurable HTTP routes, renders it through Go templates
+// into Discord webhook payloads and forwards the result to the webhook mapped to the route.
+package bridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/templates"
+)
+
+// maxBodySize limits the size of incoming JSON documents
+const maxBodySize = 1 << 20
+
+// Route maps an HTTP path to a payload template and a destination webhook.
+// The template must render a JSON webhook payload; the decoded request body is its data.
+type Route struct {
+	Path         string `json:"path"`
+	Template     string `json:"template,omitempty"`
+	TemplateFile string `json:"template_file,omitempty"`
+	WebhookURL   string `json:"webhook_url"`
+}
+
+// Config is the configuration of a bridge server
+type Config struct {
+	Addr   string  `json:"addr,omitempty"`
+	Routes []Route `json:"routes"`
+}
+
+// Server is an http.Handler that forwards JSON documents to Discord webhooks
+type Server struct {
+	mux *http.ServeMux
+
+	// Send delivers rendered payloads. It defaults to webhook.SendWebhook.
+	Send func(webhookURL string, payload webhook.Webhook) error
+
+	// ErrorLog receives the details of failed requests, with webhook tokens redacted.
+	// It defaults to the standard logger.
+	ErrorLog *log.Logger
+}
+
+// route is a Route with its parsed template
+type route struct {
+	webhookURL string
+	template   *templates.Template
+}
+
+// LoadConfig reads a JSON configuration file. Environment variables referenced
+// as $VAR or ${VAR} in webhook URLs are expanded, so secrets can stay out of the file.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read config: %v", err)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("failed to parse config: %v", err)
+	}
+
+	for i := range config.Routes {
+		config.Routes[i].WebhookURL = os.ExpandEnv(config.Routes[i].WebhookURL)
+	}
+	return config, nil
+}
+
+// NewServer creates a bridge server handling the given routes
+func NewServer(routes []Route) (*Server, error) {
+	s := &Server{
+		mux:  http.NewServeMux(),
+		Send: webhook.SendWebhook,
+	}
+
+	for _, r := range routes {
+		if r.Path == "" {
+			return nil, fmt.Errorf("route path cannot be empty")
+		}
+		if r.WebhookURL == "" {
+			return nil, fmt.Errorf("route %s has no webhook URL", r.Path)
+		}
+
+		text := r.Template
+		if r.TemplateFile != "" {
+			data, err := os.ReadFile(r.TemplateFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read template for route %s: %v", r.Path, err)
+			}
+			text = string(data)
+		}
+		if text == "" {
+			return nil, fmt.Errorf("route %s has no template", r.Path)
+		}
+
+		tmpl, err := templates.Parse(r.Path, text)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %v", r.Path, err)
+		}
+
+		rt := &route{webhookURL: r.WebhookURL, template: tmpl}
+		s.mux.HandleFunc(r.Path, func(w http.ResponseWriter, req *http.Request) {
+			s.handle(w, req, rt)
+		})
+	}
+
+	return s, nil
+}
+
+// ServeHTTP dispatches the request to the matching route
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handle renders the request body through the route template and forwards the payload
+func (s *Server) handle(w http.ResponseWriter, r *http.Request, rt *route) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	payload, status, err := rt.render(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		err = webhook.RedactError(err)
+		s.logf("bridge: %s: %v", r.URL.Path, err)
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	// The error may quote the webhook URL, so callers only learn that delivery failed
+	if err := s.Send(rt.webhookURL, payload); err != nil {
+		s.logf("bridge: %s: delivery failed: %v", r.URL.Path, webhook.RedactError(err))
+		http.Error(w, "delivery failed", http.StatusBadGateway)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// logf writes to the error log
+func (s *Server) logf(format string, args ...any) {
+	if s.ErrorLog != nil {
+		s.ErrorLog.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+// render decodes the request body and executes the route template against it.
+// The returned status code describes the failure when err is not nil.
+func (rt *route) render(body io.Reader) (webhook.Webhook, int, error) {
+	decoder := json.NewDecoder(body)
+	decoder.UseNumber()
+
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return webhook.Webhook{}, http.StatusBadRequest, fmt.Errorf("invalid JSON body: %v", err)
+	}
+
+	payload, err := rt.template.Execute(data)
+	if err != nil {
+		return webhook.Webhook{}, http.StatusUnprocessableEntity, err
+	}
+	return payload, http.StatusOK, nil
+}
@@ -0,0 +1,54 @@
+package bridge
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServerDoesNotLeakWebhookToken(t *testing.T) {
+	discord := httptest.NewServer(http.NotFoundHandler())
+	webhookURL := discord.URL + "/api/webhooks/123/SECRETTOKEN"
+	// Closing the server makes every send fail with a network error quoting the URL
+	discord.Close()
+
+	s, err := NewServer([]Route{{Path: "/deploy", Template: `{"content": {{json .version}}}`, WebhookURL: webhookURL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var logged bytes.Buffer
+	s.ErrorLog = log.New(&logged, "", 0)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/deploy", strings.NewReader(`{"version": "1.2.3"}`)))
+
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadGateway)
+	}
+	if strings.Contains(rec.Body.String(), "SECRETTOKEN") {
+		t.Errorf("response leaks the webhook token: %q", rec.Body.String())
+	}
+	if !strings.Contains(logged.String(), "delivery failed") {
+		t.Errorf("failure was not logged: %q", logged.String())
+	}
+	if strings.Contains(logged.String(), "SECRETTOKEN") {
+		t.Errorf("log leaks the webhook token: %q", logged.String())
+	}
+}
+
+func TestServerRejectsInvalidBody(t *testing.T) {
+	s, err := NewServer([]Route{{Path: "/deploy", Template: `{"content": {{json .version}}}`, WebhookURL: "https://discord.com/api/webhooks/123/token"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.ErrorLog = log.New(&bytes.Buffer{}, "", 0)
+
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/deploy", strings.NewReader(`{`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
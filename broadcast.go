@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DestinationResult is the outcome of a message for one webhook of a Router
+type DestinationResult struct {
+	// URL is the webhook URL with its token redacted
+	URL string
+	// Status is the HTTP status of Discord's last response, or 0 when none arrived
+	Status int
+	// Retries is how often the message was sent again after the first attempt
+	Retries int
+	// Err is the error of the webhook, with the tokens of webhook URLs in its text redacted
+	Err error
+}
+
+// BroadcastError reports the webhooks a Router failed to send to. On Go 1.20 and
+// later, errors.Is and errors.As look into the error of every failed webhook.
+type BroadcastError struct {
+	// Results holds the results of every webhook the message was sent to, including the successful ones
+	Results []DestinationResult
+}
+
+// Error lists the failed webhooks and their errors
+func (e *BroadcastError) Error() string {
+	failed := e.Failed()
+	parts := make([]string, len(failed))
+	for i, result := range failed {
+		parts[i] = fmt.Sprintf("%s: %v", result.URL, result.Err)
+	}
+	return fmt.Sprintf("failed to send to %d of %d webhooks: %s", len(failed), len(e.Results), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the errors of the failed webhooks
+func (e *BroadcastError) Unwrap() []error {
+	var errs []error
+	for _, result := range e.Results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+	return errs
+}
+
+// Failed returns the results of the webhooks that failed
+func (e *BroadcastError) Failed() []DestinationResult {
+	var failed []DestinationResult
+	for _, result := range e.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+// Broadcast sends the payload to the webhooks of the matching routes at the same
+// time, retrying failures like queued messages are, and returns the result of every
+// webhook in the order of their routes. The error is a *BroadcastError when any of
+// them failed.
+func (r *Router) Broadcast(ctx context.Context, webhookPayload Webhook, options ...SendOption) ([]DestinationResult, error) {
+	urls := r.Match(webhookPayload, options...)
+	results := make([]DestinationResult, len(urls))
+	var wg sync.WaitGroup
+	for i, webhookURL := range urls {
+		wg.Add(1)
+		go func(i int, webhookURL string) {
+			defer wg.Done()
+			client := r.client(webhookURL)
+			recorder := &statusRecorder{client: client}
+			attempts, err := client.deliver(withStatusRecorder(ctx, recorder), &queuedMessage{payload: webhookPayload})
+			results[i] = DestinationResult{
+				URL:     RedactWebhookURL(webhookURL),
+				Status:  int(atomic.LoadInt32(&recorder.status)),
+				Retries: attempts - 1,
+				Err:     RedactError(err),
+			}
+		}(i, webhookURL)
+	}
+	wg.Wait()
+	return results, broadcastError(results)
+}
+
+// broadcastError returns a *BroadcastError when any of the results failed
+func broadcastError(results []DestinationResult) error {
+	for _, result := range results {
+		if result.Err != nil {
+			return &BroadcastError{Results: results}
+		}
+	}
+	return nil
+}
+
+// statusRecorder keeps the status of the last response a client received
+type statusRecorder struct {
+	client *Client
+	status int32
+}
+
+// statusRecorderKey is the context key of a statusRecorder
+type statusRecorderKey struct{}
+
+// withStatusRecorder returns a context whose requests report their status to the recorder
+func withStatusRecorder(ctx context.Context, recorder *statusRecorder) context.Context {
+	return context.WithValue(ctx, statusRecorderKey{}, recorder)
+}
+
+// recordStatus reports the status of a response to the context's recorder. Only the
+// recorder's own client counts, not mirrors sending with the same context.
+func (c *Client) recordStatus(ctx context.Context, status int) {
+	if recorder, ok := ctx.Value(statusRecorderKey{}).(*statusRecorder); ok && recorder.client == c {
+		atomic.StoreInt32(&recorder.status, int32(status))
+	}
+}
@@ -0,0 +1,67 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/webhooktest"
+)
+
+// unreachableURL returns a webhook URL whose server is gone, so sends fail with network errors
+func unreachableURL() string {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	return server.URL + "/api/webhooks/123/SECRETTOKEN"
+}
+
+func TestBroadcastRedactsErrors(t *testing.T) {
+	ok := webhooktest.NewServer()
+	defer ok.Close()
+	unreachable := unreachableURL()
+
+	router := webhook.NewRouter(webhook.WithMaxRetries(0))
+	router.Add(webhook.Route{URL: ok.URL})
+	router.Add(webhook.Route{URL: unreachable})
+
+	results, err := router.Broadcast(context.Background(), webhook.Webhook{Content: "deploy finished"})
+	var broadcastErr *webhook.BroadcastError
+	if !errors.As(err, &broadcastErr) {
+		t.Fatalf("error = %v, want a *BroadcastError", err)
+	}
+	if len(broadcastErr.Failed()) != 1 {
+		t.Fatalf("failed = %d webhooks, want 1", len(broadcastErr.Failed()))
+	}
+	if strings.Contains(err.Error(), "SECRETTOKEN") {
+		t.Errorf("error leaks the webhook token: %v", err)
+	}
+	for _, result := range results {
+		if strings.Contains(result.URL, "SECRETTOKEN") || result.Err != nil && strings.Contains(result.Err.Error(), "SECRETTOKEN") {
+			t.Errorf("result leaks the webhook token: %+v", result)
+		}
+	}
+	var netErr *webhook.NetworkError
+	if !errors.As(results[1].Err, &netErr) {
+		t.Errorf("result error %v does not unwrap to a *NetworkError", results[1].Err)
+	}
+	if results[0].Err != nil || results[0].Status != http.StatusNoContent {
+		t.Errorf("first webhook: status %d, error %v", results[0].Status, results[0].Err)
+	}
+}
+
+func TestRouterSendRedactsErrors(t *testing.T) {
+	router := webhook.NewRouter()
+	router.Add(webhook.Route{URL: unreachableURL()})
+
+	err := router.Send(context.Background(), webhook.Webhook{Content: "deploy finished"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if strings.Contains(err.Error(), "SECRETTOKEN") {
+		t.Errorf("error leaks the webhook token: %v", err)
+	}
+}
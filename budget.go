@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RetryBudget limits the share of requests that may be retries, so a failing
+// webhook is not hit with ever more traffic
+type RetryBudget struct {
+	// Ratio is the largest share of retries among the requests within Window, such as 0.2
+	Ratio  float64
+	Window time.Duration
+
+	// MinRetries are allowed within Window regardless of the ratio, so that a client
+	// sending rarely can still retry. It defaults to 10.
+	MinRetries int
+
+	// OnExhausted, when set, is called when a retry is first refused, and again
+	// after the budget recovered and ran out once more
+	OnExhausted func()
+}
+
+// retryBudget tracks the requests and retries of the queue within the window
+type retryBudget struct {
+	enabled bool
+	policy  RetryBudget
+
+	mu        sync.Mutex
+	requests  []time.Time
+	retries   []time.Time
+	exhausted bool
+}
+
+// WithRetryBudget caps the retries of queued messages. Messages whose retry is
+// refused fail with their last error.
+func WithRetryBudget(budget RetryBudget) ClientOption {
+	return func(c *Client) {
+		if budget.MinRetries == 0 {
+			budget.MinRetries = 10
+		}
+		c.budget.enabled = true
+		c.budget.policy = budget
+	}
+}
+
+// request records a delivery attempt
+func (b *retryBudget) request() {
+	if !b.enabled {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.requests = append(withinWindow(b.requests, now, b.policy.Window), now)
+}
+
+// allowRetry reports whether a retry fits the budget, recording it if it does
+func (b *retryBudget) allowRetry(stats *clientStats) bool {
+	if !b.enabled {
+		return true
+	}
+	b.mu.Lock()
+	now := time.Now()
+	b.requests = withinWindow(b.requests, now, b.policy.Window)
+	b.retries = withinWindow(b.retries, now, b.policy.Window)
+
+	allowed := float64(len(b.requests)) * b.policy.Ratio
+	if allowed < float64(b.policy.MinRetries) {
+		allowed = float64(b.policy.MinRetries)
+	}
+	if float64(len(b.retries)+1) <= allowed {
+		b.retries = append(b.retries, now)
+		b.exhausted = false
+		b.mu.Unlock()
+		return true
+	}
+
+	notify := !b.exhausted
+	b.exhausted = true
+	b.mu.Unlock()
+
+	atomic.AddInt64(&stats.refusedRetries, 1)
+	if notify && b.policy.OnExhausted != nil {
+		b.policy.OnExhausted()
+	}
+	return false
+}
+
+// withinWindow drops the times older than the window, which are sorted oldest first
+func withinWindow(times []time.Time, now time.Time, window time.Duration) []time.Time {
+	i := 0
+	for i < len(times) && now.Sub(times[i]) > window {
+		i++
+	}
+	return times[i:]
+}
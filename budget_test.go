@@ -0,0 +1,56 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/webhooktest"
+)
+
+func TestRetryBudgetRefusesRetries(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	failure := webhooktest.Error(http.StatusServiceUnavailable, 0, "503: Service Unavailable")
+	for i := 0; i < 20; i++ {
+		server.Respond(failure)
+	}
+
+	var exhausted int32
+	client := webhook.NewClient(server.URL, fastBackoff, webhook.WithMaxRetries(5), webhook.WithRetryBudget(webhook.RetryBudget{
+		Ratio:       0.1,
+		Window:      time.Minute,
+		MinRetries:  2,
+		OnExhausted: func() { atomic.AddInt32(&exhausted, 1) },
+	}))
+
+	first, err := client.Enqueue(webhook.Webhook{Content: "first"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.Enqueue(webhook.Webhook{Content: "second"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Two retries fit the budget, the third is refused
+	if result := result(t, first); result.Err == nil || result.Attempts != 3 {
+		t.Errorf("first message result = %+v, want a failure after 3 attempts", result)
+	}
+	if result := result(t, second); result.Err == nil || result.Attempts != 1 {
+		t.Errorf("second message result = %+v, want a failure without retries", result)
+	}
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := client.Stats()
+	if stats.Retried != 2 || stats.RetriesRefused != 2 {
+		t.Errorf("Retried = %d, RetriesRefused = %d, want 2 and 2", stats.Retried, stats.RetriesRefused)
+	}
+	if n := atomic.LoadInt32(&exhausted); n != 1 {
+		t.Errorf("OnExhausted called %d times, want once", n)
+	}
+}
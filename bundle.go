@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// bundleManifestName is the name of the manifest inside bundles
+const bundleManifestName = "manifest.json"
+
+// BundleEntry describes a file of a bundle in its manifest
+type BundleEntry struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// BundleManifest lists the files of a bundle. It is the last file of the archive.
+type BundleManifest struct {
+	Created time.Time     `json:"created"`
+	Files   []BundleEntry `json:"files"`
+}
+
+// Bundle packs the files into a single archive attachment named name, since a message
+// can only carry 10 files. Names ending in .zip create a zip archive, and names ending
+// in .tar.gz or .tgz a gzipped tar archive. The archive ends with a manifest.json
+// listing the size and SHA-256 of every file. It is written while it is uploaded;
+// only files of unknown size are held in memory, since tar needs their size upfront.
+func Bundle(name string, files ...File) (File, error) {
+	lower := strings.ToLower(name)
+	var write func(io.Writer, []File) error
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		write = writeZipBundle
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		write = writeTarBundle
+	default:
+		return File{}, fmt.Errorf("bundle %s: name must end in .zip, .tar.gz or .tgz", name)
+	}
+	files = append([]File(nil), files...)
+	return File{
+		Name: name,
+		Open: func() (io.ReadCloser, error) {
+			reader, writer := io.Pipe()
+			go func() {
+				writer.CloseWithError(write(writer, files))
+			}()
+			return reader, nil
+		},
+	}, nil
+}
+
+// writeZipBundle writes the files and their manifest as a zip archive
+func writeZipBundle(w io.Writer, files []File) error {
+	archive := zip.NewWriter(w)
+	manifest := BundleManifest{Created: time.Now().UTC()}
+	for _, file := range files {
+		entry, err := archive.CreateHeader(&zip.FileHeader{Name: file.Name, Method: zip.Deflate, Modified: manifest.Created})
+		if err != nil {
+			return err
+		}
+		content, err := openBundleFile(file)
+		if err != nil {
+			return err
+		}
+		written, err := copyBundleFile(entry, content, file.Name)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, written)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	entry, err := archive.CreateHeader(&zip.FileHeader{Name: bundleManifestName, Method: zip.Deflate, Modified: manifest.Created})
+	if err != nil {
+		return err
+	}
+	if _, err := entry.Write(data); err != nil {
+		return err
+	}
+	return archive.Close()
+}
+
+// writeTarBundle writes the files and their manifest as a gzipped tar archive
+func writeTarBundle(w io.Writer, files []File) error {
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	manifest := BundleManifest{Created: time.Now().UTC()}
+	for _, file := range files {
+		content, err := openBundleFile(file)
+		if err != nil {
+			return err
+		}
+		size := file.Size
+		if size == 0 {
+			data, err := io.ReadAll(content)
+			content.Close()
+			if err != nil {
+				return fmt.Errorf("failed to read file %s: %v", file.Name, err)
+			}
+			content, size = io.NopCloser(bytes.NewReader(data)), int64(len(data))
+		}
+		header := &tar.Header{Name: file.Name, Mode: 0o644, Size: size, ModTime: manifest.Created, Typeflag: tar.TypeReg}
+		if err := archive.WriteHeader(header); err != nil {
+			content.Close()
+			return err
+		}
+		written, err := copyBundleFile(archive, content, file.Name)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, written)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	header := &tar.Header{Name: bundleManifestName, Mode: 0o644, Size: int64(len(data)), ModTime: manifest.Created, Typeflag: tar.TypeReg}
+	if err := archive.WriteHeader(header); err != nil {
+		return err
+	}
+	if _, err := archive.Write(data); err != nil {
+		return err
+	}
+	if err := archive.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// openBundleFile opens a file of a bundle
+func openBundleFile(file File) (io.ReadCloser, error) {
+	if file.Open == nil {
+		return nil, fmt.Errorf("file %s has no content", file.Name)
+	}
+	content, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file %s: %v", file.Name, err)
+	}
+	return content, nil
+}
+
+// copyBundleFile copies the content into the archive and closes it, returning its manifest entry
+func copyBundleFile(w io.Writer, content io.ReadCloser, name string) (BundleEntry, error) {
+	defer content.Close()
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(w, hash), content)
+	if err != nil {
+		return BundleEntry{}, fmt.Errorf("failed to add file %s to the bundle: %v", name, err)
+	}
+	return BundleEntry{Name: name, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
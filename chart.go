@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// sparkBlocks are the levels of a sparkline, from lowest to highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// barEighths are the partial blocks ending a bar, from one to seven eighths of a character
+var barEighths = []rune("▏▎▍▌▋▊▉")
+
+// Sparkline renders the values as a line of block characters such as ▁▂▅▇, scaled
+// between the smallest and the largest value, for trends in fields or descriptions.
+// NaN values are rendered as spaces.
+func Sparkline(values []float64) string {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			low, high = math.Min(low, v), math.Max(high, v)
+		}
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			b.WriteRune(' ')
+		case high == low:
+			b.WriteRune(sparkBlocks[0])
+		default:
+			level := int((v - low) / (high - low) * float64(len(sparkBlocks)-1))
+			b.WriteRune(sparkBlocks[level])
+		}
+	}
+	return b.String()
+}
+
+// BarChart renders one horizontal bar per label, scaled so the largest value is width
+// characters long, followed by the value:
+//
+//	api    ████████████ 120
+//	worker ████▌ 45
+//
+// Wrap it in a code block, since the labels only line up in a monospace font.
+// Negative values are drawn as empty bars.
+func BarChart(labels []string, values []float64, width int) string {
+	labelWidth, high := 0, 0.0
+	for i, label := range labels {
+		if n := utf8.RuneCountInString(label); n > labelWidth {
+			labelWidth = n
+		}
+		if i < len(values) && values[i] > high {
+			high = values[i]
+		}
+	}
+
+	lines := make([]string, len(labels))
+	for i, label := range labels {
+		value := 0.0
+		if i < len(values) {
+			value = values[i]
+		}
+		line := label + strings.Repeat(" ", labelWidth-utf8.RuneCountInString(label)) + " "
+		if high > 0 && value > 0 {
+			// Small values still get a sliver, so they are told apart from zero
+			eighths := int(math.Max(1, math.Round(value/high*float64(width)*8)))
+			line += strings.Repeat("█", eighths/8)
+			if eighths%8 > 0 {
+				line += string(barEighths[eighths%8-1])
+			}
+			line += " "
+		}
+		lines[i] = line + strconv.FormatFloat(value, 'g', -1, 64)
+	}
+	return strings.Join(lines, "\n")
+}
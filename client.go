@@ -0,0 +1,409 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Client sends messages to a single webhook. Unlike the package-level functions,
+// it takes a context for every request and can be configured with options,
+// such as a custom HTTP client for proxies, timeouts or recording transports.
+type Client struct {
+	webhookURL string
+	httpClient *http.Client
+	// tlsConfig and redirectPolicy customize httpClient; see WithTLSConfig and
+	// WithRedirectPolicy. configErr reports why they could not be applied.
+	tlsConfig      *tls.Config
+	redirectPolicy func(req *http.Request, via []*http.Request) error
+	configErr      error
+
+	// provider, when set, resolves webhookURL lazily and again after auth failures
+	provider WebhookURLProvider
+	urlMu    sync.Mutex
+
+	// options are kept to create clients for other webhooks with the same configuration
+	options []ClientOption
+
+	// spoolDir receives payloads that could not be delivered; see WithSpool
+	spoolDir    string
+	spoolAlways bool
+
+	// queue delivers messages in the background; see Enqueue
+	queue    asyncQueue
+	throttle throttler
+	quiet    quietKeeper
+	budget   retryBudget
+	pause    pauser
+
+	// messages remembers the messages posted by Upsert; see WithMessageStore
+	messages MessageStore
+	upsertMu sync.Mutex
+	upserted map[string]upserted
+
+	// audit records every request; see WithAuditSink
+	audit AuditSink
+	// mirrors receive copies of new messages; see WithMirror
+	mirrors []*mirrorTarget
+	// invalid is 1 once Discord reported the webhook revoked or deleted; see WithOnWebhookInvalid
+	invalid   int32
+	onInvalid func(webhookURL string, err error)
+
+	// defaults fill in new messages; see WithDefaults
+	defaults *MessageDefaults
+	// sanitize strips invisible Unicode from outgoing text; see WithSanitizer
+	sanitize bool
+	// noLinkPreviews wraps bare URLs in the content; see WithoutLinkPreviews
+	noLinkPreviews bool
+	// checkLinks refuses unsafe masked links; see WithLinkChecking
+	checkLinks    bool
+	onLinkWarning func(webhookPayload Webhook, warnings []Violation)
+	// verify compares posted messages with their payloads; see WithStrictVerification
+	verify        bool
+	onDiscrepancy func(webhookPayload Webhook, message Message, discrepancies []Violation)
+	// degrade repairs payloads Discord rejected and retries them; see WithFormBodyDegradation
+	degrade   bool
+	onDegrade func(webhookPayload Webhook, changes []string)
+	// plainText flattens embeds into the content; see WithPlainTextFallback
+	plainText     bool
+	plainTextMode PlainTextMode
+	// gzipThreshold is the size above which text files are compressed; see WithAttachmentCompression
+	gzipThreshold int64
+	// images bounds attached images; see WithImageDownscaling
+	images *ImageOptions
+
+	// transforms and jsonHooks change outgoing payloads; see WithPayloadTransform and WithJSONHook
+	transforms []func(webhookPayload *Webhook)
+	jsonHooks  []func(data []byte) ([]byte, error)
+
+	// backoff spaces out the retries of queued messages; see WithBackoff
+	backoff Backoff
+
+	// attemptTimeout bounds single requests and operationTimeout whole operations with
+	// their retries; see WithAttemptTimeout and WithOperationTimeout
+	attemptTimeout   time.Duration
+	operationTimeout time.Duration
+
+	// debug dumps outgoing payloads; see WithDebugDump
+	debug debugDumper
+
+	// expvarName is the name the client's statistics are published under; see WithExpvar
+	expvarName string
+
+	// stats is allocated separately, which keeps its 64-bit counters aligned on 32-bit platforms
+	stats *clientStats
+}
+
+// ClientOption configures a Client
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests. It defaults to http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// NewClient creates a client for the webhook URL
+func NewClient(webhookURL string, options ...ClientOption) *Client {
+	c := newClient(webhookURL, options)
+	if c.expvarName != "" {
+		publishExpvar(c.expvarName, c)
+	}
+	return c
+}
+
+// newClient creates a client without publishing it, as for other webhooks of a client
+func newClient(webhookURL string, options []ClientOption) *Client {
+	c := &Client{
+		webhookURL: webhookURL,
+		httpClient: http.DefaultClient,
+		options:    options,
+		messages:   NewMemoryStore(),
+		upserted:   make(map[string]upserted),
+		stats:      &clientStats{},
+	}
+	c.queue.init()
+	for _, option := range options {
+		option(c)
+	}
+	c.configErr = c.configureHTTPClient()
+	return c
+}
+
+// URL returns the webhook URL of the client. With a URL provider, it is empty until
+// the URL was first resolved.
+func (c *Client) URL() string {
+	c.urlMu.Lock()
+	defer c.urlMu.Unlock()
+	return c.webhookURL
+}
+
+// withURL creates a client for another webhook, configured with the same options
+func (c *Client) withURL(webhookURL string) *Client {
+	return newClient(webhookURL, c.options)
+}
+
+// Send sends the webhook payload
+func (c *Client) Send(ctx context.Context, webhookPayload Webhook) error {
+	_, err := c.execute(ctx, webhookPayload, false)
+	return err
+}
+
+// SendAndWait sends the payload and returns the message Discord created, whose ID
+// can be kept to edit or delete it later. With a spool, the message is empty when
+// the payload was spooled instead of sent.
+func (c *Client) SendAndWait(ctx context.Context, webhookPayload Webhook) (Message, error) {
+	return c.execute(ctx, webhookPayload, true)
+}
+
+// execute sends the payload, spooling it when configured to. With wait, Discord
+// confirms the message and returns it; the message is empty when it was spooled.
+func (c *Client) execute(ctx context.Context, webhookPayload Webhook, wait bool) (Message, error) {
+	ctx, cancel := c.operationContext(ctx)
+	defer cancel()
+	webhookPayload = c.prepare(c.defaults.apply(webhookPayload))
+	if err := c.checkPayloadLinks(webhookPayload); err != nil {
+		return Message{}, err
+	}
+
+	if c.spoolAlways {
+		webhookURL, err := c.baseURL(ctx)
+		if err != nil {
+			return Message{}, err
+		}
+		return Message{}, spoolPayload(c.spoolDir, webhookURL, webhookPayload)
+	}
+
+	var message Message
+	var webhookURL string
+	err := c.withBaseURL(ctx, func(base string) error {
+		var err error
+		webhookURL = base
+		message, err = c.post(ctx, base, webhookPayload, wait)
+		if apiErr, ok := c.degradable(err); ok {
+			degraded, changes := degradePayload(webhookPayload, apiErr.FieldErrors())
+			if len(changes) > 0 {
+				if c.onDegrade != nil {
+					c.onDegrade(webhookPayload, changes)
+				}
+				message, err = c.post(ctx, base, degraded, wait)
+			}
+		}
+		if c.plainText && embedsRejected(err) {
+			message, err = c.post(ctx, base, plainTextPayload(webhookPayload), wait)
+		}
+		return err
+	})
+	var netErr *NetworkError
+	if c.spoolDir != "" && errors.As(err, &netErr) && ctx.Err() == nil {
+		return Message{}, spoolPayload(c.spoolDir, webhookURL, webhookPayload)
+	}
+	// Copies follow the posted message once, not every attempt or spooled payload
+	var verifyErr *VerificationError
+	if len(c.mirrors) > 0 && (err == nil || errors.As(err, &verifyErr)) {
+		c.mirror(ctx, webhookPayload)()
+	}
+	return message, err
+}
+
+// prepare applies the client's transformations to an outgoing payload
+func (c *Client) prepare(webhookPayload Webhook) Webhook {
+	if c.sanitize {
+		webhookPayload = SanitizeWebhook(webhookPayload)
+	}
+	if c.noLinkPreviews {
+		webhookPayload.Content = WrapURLsNoPreview(webhookPayload.Content)
+	}
+	if c.plainText && c.plainTextMode == PlainTextAlways {
+		webhookPayload = plainTextPayload(webhookPayload)
+	}
+	return c.applyTransforms(webhookPayload)
+}
+
+// post posts the payload to the webhook
+func (c *Client) post(ctx context.Context, webhookURL string, webhookPayload Webhook, wait bool) (Message, error) {
+	requestURL := webhookURL
+	var err error
+
+	// Webhooks not owned by an application must opt in to sending components
+	if len(webhookPayload.Components) > 0 {
+		if requestURL, err = withQueryParam(requestURL, "with_components", "true"); err != nil {
+			return Message{}, err
+		}
+	}
+	if !wait && !c.verify {
+		return Message{}, explainComponentError(c.do(ctx, http.MethodPost, requestURL, webhookPayload, nil), webhookPayload)
+	}
+
+	if requestURL, err = withQueryParam(requestURL, "wait", "true"); err != nil {
+		return Message{}, err
+	}
+	var message Message
+	if err := c.do(ctx, http.MethodPost, requestURL, webhookPayload, &message); err != nil {
+		return Message{}, explainComponentError(err, webhookPayload)
+	}
+	return message, c.verifyMessage(webhookPayload, message)
+}
+
+// Edit edits a message previously sent by the webhook. Options edit it in a thread,
+// upload files and keep or remove the message's attachments.
+func (c *Client) Edit(ctx context.Context, messageID string, webhookPayload Webhook, options ...EditOption) error {
+	var o editOptions
+	for _, option := range options {
+		option(&o)
+	}
+	ctx, cancel := c.operationContext(ctx)
+	defer cancel()
+	webhookPayload = c.prepare(webhookPayload)
+	if err := c.checkPayloadLinks(webhookPayload); err != nil {
+		return err
+	}
+	files := c.compressFiles(c.downscaleImages(o.files))
+	return c.withBaseURL(ctx, func(webhookURL string) error {
+		requestURL, err := o.editURL(webhookURL, messageID, webhookPayload)
+		if err != nil {
+			return err
+		}
+		payload := webhookPayload
+		if o.changesAttachments(webhookPayload) {
+			attachments, err := c.retainedAttachments(ctx, requestURL, &o)
+			if err != nil {
+				return err
+			}
+			if len(files) == 0 {
+				payload = withRetainedAttachments(payload, attachments)
+			} else {
+				payload.Attachments = attachments
+			}
+		}
+		if len(files) == 0 {
+			return explainComponentError(c.do(ctx, http.MethodPatch, requestURL, payload, nil), payload)
+		}
+		payload = withFileAttachments(payload, files)
+		return explainComponentError(c.doMultipart(ctx, http.MethodPatch, requestURL, payload, files, nil), payload)
+	})
+}
+
+// Delete deletes a message previously sent by the webhook
+func (c *Client) Delete(ctx context.Context, messageID string) error {
+	return c.withBaseURL(ctx, func(webhookURL string) error {
+		requestURL, err := messageURL(webhookURL, messageID)
+		if err != nil {
+			return err
+		}
+		return c.do(ctx, http.MethodDelete, requestURL, nil, nil)
+	})
+}
+
+// Info fetches the webhook's name, channel and guild
+func (c *Client) Info(ctx context.Context) (WebhookInfo, error) {
+	var info WebhookInfo
+	err := c.withBaseURL(ctx, func(webhookURL string) error {
+		return c.do(ctx, http.MethodGet, webhookURL, nil, &info)
+	})
+	if err != nil {
+		return WebhookInfo{}, err
+	}
+	return info, nil
+}
+
+// isNotFound reports whether the error is Discord answering 404, such as for a deleted message
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// retryAfter reads the delay requested by a rate limited response
+func retryAfter(header http.Header) time.Duration {
+	for _, key := range []string{"X-RateLimit-Reset-After", "Retry-After"} {
+		if seconds, err := strconv.ParseFloat(header.Get(key), 64); err == nil && seconds > 0 {
+			return time.Duration(seconds * float64(time.Second))
+		}
+	}
+	return 0
+}
+
+// do sends a JSON request to Discord and decodes the response into out when it is not nil
+func (c *Client) do(ctx context.Context, method, requestURL string, body any, out any) error {
+	if body == nil {
+		return c.roundTrip(ctx, method, requestURL, nil, nil, "", out)
+	}
+	encoded, err := encodeJSON(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON payload: %v", err)
+	}
+	defer encoded.release()
+	if len(c.jsonHooks) > 0 {
+		// The hooks' JSON is sent instead, so the transport never holds the pooled buffer
+		encoded.Close()
+		data, err := c.applyJSONHooks(encoded.data)
+		if err != nil {
+			return err
+		}
+		return c.roundTrip(ctx, method, requestURL, data, bytes.NewReader(data), "application/json", out)
+	}
+	return c.roundTrip(ctx, method, requestURL, encoded.data, encoded, "application/json", out)
+}
+
+// roundTrip sends a request with the body read from reader and decodes the response into out
+// when it is not nil. jsonData is the JSON payload of the body, which is recorded for audits.
+func (c *Client) roundTrip(ctx context.Context, method, requestURL string, jsonData []byte, reader io.Reader, contentType string, out any) (err error) {
+	if c.configErr != nil {
+		return c.configErr
+	}
+	ctx, cancel := c.attemptContext(ctx)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	// Bodies other than bytes.Reader and friends would otherwise be sent chunked
+	if sized, ok := reader.(interface{ Len() int }); ok && req.ContentLength == 0 {
+		req.ContentLength = int64(sized.Len())
+	}
+	c.debug.dump(method, requestURL, jsonData)
+
+	status := 0
+	var header http.Header
+	start := time.Now()
+	defer func() {
+		c.stats.record(header, time.Since(start), status, err)
+		c.recordStatus(ctx, status)
+		if c.audit != nil {
+			c.recordAudit(start, method, requestURL, jsonData, status, out, err)
+		}
+	}()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &NetworkError{Err: err}
+	}
+	defer resp.Body.Close()
+	status, header = resp.StatusCode, resp.Header
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := readAPIError(resp)
+		apiErr.webhookInvalid = invalidWebhook(apiErr, requestURL)
+		return apiErr
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode Discord response: %v", err)
+		}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,53 @@
+// Command discord-webhook-bridge runs a notification bridge that accepts JSON on
+// configured routes and forwards it to Discord webhooks.
+//
+// Usage:
+//
+//	discord-webhook-bridge -config bridge.json [-addr :8080]
+//
+// The configuration file looks like:
+//
+//	{
+//	  "addr": ":8080",
+//	  "routes": [
+//	    {
+//	      "path": "/alertmanager",
+//	      "template_file": "alertmanager.tmpl",
+//	      "webhook_url": "${DISCORD_ALERTS_URL}"
+//	    }
+//	  ]
+//	}
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/dozerokz/discord-webhook-go/bridge"
+)
+
+func main() {
+	configPath := flag.String("config", "bridge.json", "path to the bridge configuration file")
+	addr := flag.String("addr", "", "listen address (overrides the configuration file)")
+	flag.Parse()
+
+	config, err := bridge.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Error loading configuration: %v", err)
+	}
+	if *addr != "" {
+		config.Addr = *addr
+	}
+	if config.Addr == "" {
+		config.Addr = ":8080"
+	}
+
+	server, err := bridge.NewServer(config.Routes)
+	if err != nil {
+		log.Fatalf("Error creating bridge: %v", err)
+	}
+
+	log.Printf("Listening on %s with %d routes", config.Addr, len(config.Routes))
+	log.Fatal(http.ListenAndServe(config.Addr, server))
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+// pollInterval is how often a followed file is checked for new data
+const pollInterval = 250 * time.Millisecond
+
+// followOptions holds the flags of the follow mode
+type followOptions struct {
+	path          string
+	batchInterval time.Duration
+	minInterval   time.Duration
+
+	// ansi keeps terminal colors, set from the -ansi message flag
+	ansi bool
+}
+
+// register adds the follow flags to the flag set
+func (o *followOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.path, "follow", "", "tail a file (\"-\" for stdin) and post new lines as code blocks")
+	fs.DurationVar(&o.batchInterval, "batch-interval", 2*time.Second, "how long to collect lines before posting them in follow mode")
+	fs.DurationVar(&o.minInterval, "min-interval", time.Second, "minimum time between two messages in follow mode")
+}
+
+// follower batches lines and posts them as code-block messages
+type follower struct {
+	webhookURL string
+	base       webhook.Webhook
+	options    followOptions
+	stderr     io.Writer
+	lastSend   time.Time
+}
+
+// runFollow tails the configured file or stdin until it ends or ctx is cancelled
+func runFollow(ctx context.Context, webhookURL string, base webhook.Webhook, options followOptions, stdin io.Reader, stderr io.Writer) error {
+	lines := make(chan string)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(lines)
+		if options.path == "-" {
+			errs <- readLines(ctx, stdin, lines)
+		} else {
+			errs <- tailFile(ctx, options.path, lines)
+		}
+	}()
+
+	f := &follower{
+		webhookURL: webhookURL,
+		base:       base,
+		options:    options,
+		stderr:     stderr,
+	}
+	f.run(ctx, lines)
+
+	if err := <-errs; err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
+
+// run collects lines into batches that fit into one code block. A batch is posted when
+// the next line would not fit or when the batch interval has passed since its first line.
+func (f *follower) run(ctx context.Context, lines <-chan string) {
+	var batch strings.Builder
+	var timer <-chan time.Time
+
+	flush := func() {
+		if batch.Len() == 0 {
+			return
+		}
+		f.post(batch.String())
+		batch.Reset()
+		timer = nil
+	}
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				flush()
+				return
+			}
+			if !f.options.ansi {
+				line = webhook.StripANSI(line)
+			}
+			for _, chunk := range splitLine(escapeFences(line)) {
+				if batch.Len() > 0 && batch.Len()+1+len(chunk) > codeBlockBudget {
+					flush()
+				}
+				if batch.Len() > 0 {
+					batch.WriteByte('\n')
+				}
+				batch.WriteString(chunk)
+				if timer == nil {
+					timer = time.After(f.options.batchInterval)
+				}
+			}
+		case <-timer:
+			flush()
+		case <-ctx.Done():
+			flush()
+			return
+		}
+	}
+}
+
+// post sends a batch, waiting first if the previous message was sent too recently.
+// Failures are reported but do not stop following.
+func (f *follower) post(text string) {
+	if wait := f.options.minInterval - time.Since(f.lastSend); wait > 0 {
+		time.Sleep(wait)
+	}
+	f.lastSend = time.Now()
+
+	payload := f.base
+	payload.Content = wrapCodeBlock(text, f.fenceLanguage())
+	if err := webhook.SendWebhook(f.webhookURL, payload); err != nil {
+		fmt.Fprintf(f.stderr, "discord-webhook: %v\n", err)
+	}
+}
+
+// fenceLanguage returns the language of the code blocks, which is ansi when colors are kept
+func (f *follower) fenceLanguage() string {
+	if f.options.ansi {
+		return "ansi"
+	}
+	return ""
+}
+
+// splitLine splits a line that does not fit into a single code block
+func splitLine(line string) []string {
+	var chunks []string
+	for len(line) > codeBlockBudget {
+		cut := codeBlockBudget
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		chunks = append(chunks, line[:cut])
+		line = line[cut:]
+	}
+	return append(chunks, line)
+}
+
+// readLines sends every line of r until it ends
+func readLines(ctx context.Context, r io.Reader, lines chan<- string) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		select {
+		case lines <- strings.TrimRight(scanner.Text(), "\r"):
+		case <-ctx.Done():
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// tailFile sends lines appended to the file until ctx is cancelled. Like tail -F,
+// it starts at the end of the file and reopens it when it is truncated or replaced.
+func tailFile(ctx context.Context, path string, lines chan<- string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { file.Close() }()
+
+	offset, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+
+	var partial string
+	buf := make([]byte, 32*1024)
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		n, err := file.Read(buf)
+		if n > 0 {
+			offset += int64(n)
+			data := partial + string(buf[:n])
+			parts := strings.Split(data, "\n")
+			partial = parts[len(parts)-1]
+			for _, line := range parts[:len(parts)-1] {
+				select {
+				case lines <- strings.TrimRight(line, "\r"):
+				case <-ctx.Done():
+					return nil
+				}
+			}
+			continue
+		}
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		// Reopen the file after rotation, or start over after truncation
+		if info, err := os.Stat(path); err == nil {
+			current, statErr := file.Stat()
+			if statErr == nil && !os.SameFile(info, current) {
+				if replacement, err := os.Open(path); err == nil {
+					file.Close()
+					file, offset, partial = replacement, 0, ""
+				}
+			} else if info.Size() < offset {
+				if _, err := file.Seek(0, io.SeekStart); err == nil {
+					offset, partial = 0, ""
+				}
+			}
+		}
+	}
+}
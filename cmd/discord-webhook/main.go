@@ -0,0 +1,181 @@
+// Command discord-webhook sends Discord webhook messages from the command line,
+// so shell scripts and cron jobs can post notifications without a Go program.
+//
+// Usage:
+//
+//	discord-webhook [flags]                      send a message
+//	discord-webhook -template deploy.tmpl -data deploy.json -preview
+//	discord-webhook edit -message-id ID [flags]  edit a message sent by the webhook
+//	discord-webhook delete -message-id ID        delete a message sent by the webhook
+//	discord-webhook info                         show the webhook's name, channel and guild
+//	discord-webhook validate FILE...             check JSON payload files against the schema and Discord's limits
+//	discord-webhook schema                       print the JSON Schema of payload files
+//	discord-webhook replay DIR                   deliver messages spooled with -spool
+//
+// The webhook URL is read from -url or the DISCORD_WEBHOOK_URL environment variable.
+//
+// Examples:
+//
+//	discord-webhook -content "Backup finished"
+//	discord-webhook -title "Deploy" -description "v1.2.3 is live" -color "#2ecc71" \
+//		-field "Environment=production" -field "Duration=42s"
+//	make test 2>&1 | discord-webhook -code-block
+//	discord-webhook -json < payload.json
+//	discord-webhook -template deploy.tmpl -data deploy.json
+//	discord-webhook -follow /var/log/app.log -batch-interval 5s
+//	discord-webhook -template deploy.tmpl -data deploy.json -preview
+//	discord-webhook edit -message-id 1234567890 -content "Status: all systems operational"
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/preview"
+)
+
+// urlEnvVar is the environment variable holding the default webhook URL
+const urlEnvVar = "DISCORD_WEBHOOK_URL"
+
+// errUsage is returned when flag parsing failed; the flag package has already reported why
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+		os.Exit(0)
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "discord-webhook: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run dispatches to the subcommand named by the first argument, sending a message by default
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "edit":
+			return runEdit(args[1:], stdin, stderr)
+		case "delete":
+			return runDelete(args[1:], stderr)
+		case "info":
+			return runInfo(args[1:], stdout, stderr)
+		case "validate":
+			return runValidate(args[1:], stdout, stderr)
+		case "schema":
+			return runSchema(args[1:], stdout, stderr)
+		case "replay":
+			return runReplay(args[1:], stdout, stderr)
+		}
+	}
+	return runSend(args, stdin, stdout, stderr)
+}
+
+// runSend sends a message built from the flags, stdin or a template
+func runSend(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("discord-webhook", stderr)
+	webhookURL := registerURL(fs)
+	showPreview := fs.Bool("preview", false, "print a terminal mock-up of the message instead of sending it")
+	spoolDir := fs.String("spool", "", "write the message to this directory when Discord is unreachable, for a later replay")
+
+	var opts messageOptions
+	var follow followOptions
+	opts.register(fs)
+	follow.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if *showPreview {
+		payload, err := opts.payloadWithStdin(stdin)
+		if err != nil {
+			return err
+		}
+		return preview.RenderTerminal(stdout, payload, preview.TerminalOptions{NoColor: noColor(stdout)})
+	}
+
+	url, err := resolveURL(*webhookURL)
+	if err != nil {
+		return err
+	}
+
+	if follow.path != "" {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		base := webhook.Webhook{Username: opts.username, AvatarURL: opts.avatarURL}
+		follow.ansi = opts.ansi
+		return runFollow(ctx, url, base, follow, stdin, stderr)
+	}
+
+	payload, err := opts.payloadWithStdin(stdin)
+	if err != nil {
+		return err
+	}
+
+	if *spoolDir != "" {
+		return webhook.NewClient(url, webhook.WithSpool(*spoolDir)).Send(context.Background(), payload)
+	}
+	return webhook.SendWebhook(url, payload)
+}
+
+// newFlagSet creates a flag set that reports errors instead of exiting
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+// parseFlags parses the arguments and rejects positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	return nil
+}
+
+// registerURL adds the -url flag to the flag set
+func registerURL(fs *flag.FlagSet) *string {
+	return fs.String("url", "", "webhook URL (defaults to $"+urlEnvVar+")")
+}
+
+// resolveURL returns the URL from the flag or the environment
+func resolveURL(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if url := os.Getenv(urlEnvVar); url != "" {
+		return url, nil
+	}
+	return "", fmt.Errorf("no webhook URL: use -url or set %s", urlEnvVar)
+}
+
+// noColor reports whether output to w should not use ANSI colors,
+// following the NO_COLOR convention and disabling colors when w is not a terminal
+func noColor(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return true
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return true
+	}
+	info, err := f.Stat()
+	return err != nil || info.Mode()&os.ModeCharDevice == 0
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+// runEdit replaces the content of a message previously sent by the webhook
+func runEdit(args []string, stdin io.Reader, stderr io.Writer) error {
+	fs := newFlagSet("discord-webhook edit", stderr)
+	webhookURL := registerURL(fs)
+	messageID := fs.String("message-id", "", "ID of the message to edit")
+	threadID := fs.String("thread-id", "", "ID of the thread the message was posted in")
+
+	var opts messageOptions
+	opts.register(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *messageID == "" {
+		return fmt.Errorf("-message-id is required")
+	}
+
+	url, err := resolveURL(*webhookURL)
+	if err != nil {
+		return err
+	}
+
+	payload, err := opts.payloadWithStdin(stdin)
+	if err != nil {
+		return err
+	}
+
+	var options []webhook.EditOption
+	if *threadID != "" {
+		options = append(options, webhook.EditInThread(*threadID))
+	}
+	return webhook.EditMessage(url, *messageID, payload, options...)
+}
+
+// runDelete deletes a message previously sent by the webhook
+func runDelete(args []string, stderr io.Writer) error {
+	fs := newFlagSet("discord-webhook delete", stderr)
+	webhookURL := registerURL(fs)
+	messageID := fs.String("message-id", "", "ID of the message to delete")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *messageID == "" {
+		return fmt.Errorf("-message-id is required")
+	}
+
+	url, err := resolveURL(*webhookURL)
+	if err != nil {
+		return err
+	}
+
+	return webhook.DeleteMessage(url, *messageID)
+}
+
+// runInfo prints the webhook's name, channel and guild
+func runInfo(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("discord-webhook info", stderr)
+	webhookURL := registerURL(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	url, err := resolveURL(*webhookURL)
+	if err != nil {
+		return err
+	}
+
+	info, err := webhook.GetWebhookInfo(url)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "ID:      %s\n", info.ID)
+	fmt.Fprintf(stdout, "Name:    %s\n", info.Name)
+	fmt.Fprintf(stdout, "Channel: %s\n", info.ChannelID)
+	if info.GuildID != "" {
+		fmt.Fprintf(stdout, "Guild:   %s\n", info.GuildID)
+	}
+	if info.ApplicationID != "" {
+		fmt.Fprintf(stdout, "App:     %s\n", info.ApplicationID)
+	}
+	return nil
+}
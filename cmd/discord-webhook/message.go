@@ -0,0 +1,276 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/templates"
+)
+
+// messageOptions holds the flags describing a message
+type messageOptions struct {
+	content     string
+	username    string
+	avatarURL   string
+	title       string
+	description string
+	embedURL    string
+	color       string
+	fields      fieldList
+	inline      bool
+	codeBlock   bool
+	ansi        bool
+	language    string
+	json        bool
+	template    string
+	data        string
+}
+
+// maxContentLength is the maximum length of message content accepted by Discord
+const maxContentLength = 2000
+
+// register adds the message flags to the flag set
+func (o *messageOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.content, "content", "", "message content (\"-\" reads it from stdin)")
+	fs.StringVar(&o.username, "username", "", "override the webhook username")
+	fs.StringVar(&o.avatarURL, "avatar", "", "override the webhook avatar URL")
+	fs.StringVar(&o.title, "title", "", "embed title")
+	fs.StringVar(&o.description, "description", "", "embed description")
+	fs.StringVar(&o.embedURL, "embed-url", "", "embed title URL")
+	fs.StringVar(&o.color, "color", "", "embed color as hex (#ff5733) or decimal")
+	fs.Var(&o.fields, "field", "embed field as name=value (repeatable)")
+	fs.BoolVar(&o.inline, "inline", false, "display embed fields inline")
+	fs.BoolVar(&o.codeBlock, "code-block", false, "wrap the content in a code block")
+	fs.StringVar(&o.language, "language", "", "highlight -code-block content as this language (\"auto\" detects JSON, YAML, Go, SQL and diffs)")
+	fs.BoolVar(&o.ansi, "ansi", false, "keep terminal colors in code blocks, posting them as ```ansi blocks")
+	fs.BoolVar(&o.json, "json", false, "read a complete JSON payload from stdin")
+	fs.StringVar(&o.template, "template", "", "render the payload from a Go template file")
+	fs.StringVar(&o.data, "data", "", "JSON file with the template data (\"-\" reads it from stdin)")
+}
+
+// hasEmbed reports whether any embed flag was set
+func (o *messageOptions) hasEmbed() bool {
+	return o.title != "" || o.description != "" || o.embedURL != "" || o.color != "" || len(o.fields) > 0
+}
+
+// payloadWithStdin builds the payload, reading the content or the whole payload from stdin
+// when requested. Content is read from stdin when -content is "-", or when no content or
+// embed flags are given and stdin is not a terminal.
+func (o *messageOptions) payloadWithStdin(stdin io.Reader) (webhook.Webhook, error) {
+	if o.json {
+		return o.jsonPayload(stdin)
+	}
+	if o.template != "" {
+		return o.templatePayload(stdin)
+	}
+
+	if o.content == "-" || (o.content == "" && !o.hasEmbed() && isPiped(stdin)) {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return webhook.Webhook{}, fmt.Errorf("failed to read stdin: %v", err)
+		}
+		o.content = strings.TrimRight(string(data), "\r\n")
+	}
+
+	return o.payload()
+}
+
+// jsonPayload decodes a payload from stdin
+func (o *messageOptions) jsonPayload(stdin io.Reader) (webhook.Webhook, error) {
+	var payload webhook.Webhook
+	if err := json.NewDecoder(stdin).Decode(&payload); err != nil {
+		return webhook.Webhook{}, fmt.Errorf("failed to decode JSON payload: %v", err)
+	}
+	o.applyOverrides(&payload)
+	return payload, nil
+}
+
+// applyOverrides applies the username and avatar flags to a payload that was not built from flags
+func (o *messageOptions) applyOverrides(payload *webhook.Webhook) {
+	if o.username != "" {
+		payload.Username = o.username
+	}
+	if o.avatarURL != "" {
+		payload.AvatarURL = o.avatarURL
+	}
+}
+
+// templatePayload renders the template file against the data file
+func (o *messageOptions) templatePayload(stdin io.Reader) (webhook.Webhook, error) {
+	tmpl, err := templates.ParseFile(o.template)
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+
+	var data any
+	if o.data != "" {
+		r := stdin
+		if o.data != "-" {
+			file, err := os.Open(o.data)
+			if err != nil {
+				return webhook.Webhook{}, fmt.Errorf("failed to open template data: %v", err)
+			}
+			defer file.Close()
+			r = file
+		}
+
+		decoder := json.NewDecoder(r)
+		decoder.UseNumber()
+		if err := decoder.Decode(&data); err != nil {
+			return webhook.Webhook{}, fmt.Errorf("failed to decode template data: %v", err)
+		}
+	}
+
+	payload, err := tmpl.Execute(data)
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+	o.applyOverrides(&payload)
+	return payload, nil
+}
+
+// payload builds the webhook payload from the flags
+func (o *messageOptions) payload() (webhook.Webhook, error) {
+	content := o.content
+	if o.codeBlock && content != "" {
+		content = codeBlock(content, o.ansi, o.language)
+	}
+
+	payload, err := webhook.CreateWebhook(content, o.username, o.avatarURL)
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+
+	if o.hasEmbed() {
+		embed, err := o.embed()
+		if err != nil {
+			return webhook.Webhook{}, err
+		}
+		payload.AddEmbed(embed)
+	}
+
+	if payload.Content == "" && len(payload.Embeds) == 0 {
+		return webhook.Webhook{}, fmt.Errorf("nothing to send: set -content or an embed flag")
+	}
+	return payload, nil
+}
+
+// embed builds the embed from the flags
+func (o *messageOptions) embed() (webhook.Embed, error) {
+	var embed webhook.Embed
+	var err error
+
+	switch {
+	case o.color == "":
+		embed, err = webhook.CreateEmbed(o.title, o.description, o.embedURL, 0)
+	case strings.HasPrefix(o.color, "#"):
+		embed, err = webhook.CreateEmbed(o.title, o.description, o.embedURL, o.color)
+	default:
+		value, convErr := strconv.Atoi(o.color)
+		if convErr != nil {
+			return webhook.Embed{}, fmt.Errorf("invalid color %q: use #rrggbb or a decimal number", o.color)
+		}
+		embed, err = webhook.CreateEmbed(o.title, o.description, o.embedURL, value)
+	}
+	if err != nil {
+		return webhook.Embed{}, err
+	}
+
+	for _, f := range o.fields {
+		embed.AddField(webhook.CreateField(f.name, f.value, o.inline))
+	}
+	return embed, nil
+}
+
+// Code block fences and the room they leave for text within the content limit
+// when highlighted as ansi, the longest language followed mode uses
+const (
+	closeFence      = "\n```"
+	codeBlockBudget = maxContentLength - len("```ansi\n") - len(closeFence)
+)
+
+// wrapCodeBlock wraps text that fits into the content limit in a code block highlighted as language
+func wrapCodeBlock(text, language string) string {
+	return "```" + language + "\n" + text + closeFence
+}
+
+// codeBlock wraps text in a code block that fits into the content limit. Terminal
+// colors are stripped, since Discord renders them only in ```ansi blocks, unless
+// ansi is set. Output that is too long is truncated from the start, since the end
+// of command output usually holds the interesting part.
+func codeBlock(text string, ansi bool, language string) string {
+	switch {
+	case ansi:
+		language = "ansi"
+	case language == webhook.LanguageAuto:
+		text = webhook.StripANSI(text)
+		language = webhook.DetectLanguage(text)
+	default:
+		text = webhook.StripANSI(text)
+	}
+	text = escapeFences(text)
+
+	const marker = "…\n"
+	// CreateWebhook measures content in bytes, so the budget is in bytes as well
+	budget := maxContentLength - len(wrapCodeBlock("", language))
+	if len(text) > budget {
+		cut := len(text) - (budget - len(marker))
+		for cut < len(text) && !utf8.RuneStart(text[cut]) {
+			cut++
+		}
+		text = marker + text[cut:]
+	}
+	return wrapCodeBlock(text, language)
+}
+
+// escapeFences breaks code fences inside text so they cannot close a code block early
+func escapeFences(text string) string {
+	return strings.ReplaceAll(text, "```", "`\u200b``")
+}
+
+// isPiped reports whether the reader is a file that is not a terminal
+func isPiped(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice == 0
+}
+
+// field is a name/value pair given with -field
+type field struct {
+	name, value string
+}
+
+// fieldList collects repeated -field flags
+type fieldList []field
+
+// String returns the fields in flag form
+func (l *fieldList) String() string {
+	parts := make([]string, len(*l))
+	for i, f := range *l {
+		parts[i] = f.name + "=" + f.value
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Set parses a name=value field
+func (l *fieldList) Set(value string) error {
+	name, val, found := strings.Cut(value, "=")
+	if !found || name == "" {
+		return fmt.Errorf("field must be name=value")
+	}
+	*l = append(*l, field{name: name, value: val})
+	return nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+// runReplay delivers the messages spooled in a directory while Discord was unreachable
+func runReplay(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("discord-webhook replay", stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("replay needs the spool directory")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sent, err := webhook.Replay(ctx, fs.Arg(0))
+	fmt.Fprintf(stdout, "delivered %d spooled messages\n", sent)
+	return err
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+// runValidate reports every Discord limit violated by the payload files without sending them
+func runValidate(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("discord-webhook validate", stderr)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("validate needs at least one payload file")
+	}
+
+	invalid := 0
+	for _, path := range fs.Args() {
+		problems := validateFile(path)
+		if len(problems) == 0 {
+			fmt.Fprintf(stdout, "%s: ok\n", path)
+			continue
+		}
+		invalid++
+		for _, problem := range problems {
+			fmt.Fprintf(stdout, "%s: %s\n", path, problem)
+		}
+	}
+
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d payloads are invalid", invalid, fs.NArg())
+	}
+	return nil
+}
+
+// validateFile returns the problems found in a payload file
+func validateFile(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	err = webhook.ValidateWebhookJSON(data)
+	var validationErr *webhook.ValidationError
+	if errors.As(err, &validationErr) {
+		problems := make([]string, len(validationErr.Violations))
+		for i, violation := range validationErr.Violations {
+			problems[i] = violation.String()
+		}
+		return problems
+	}
+	if err != nil {
+		return []string{err.Error()}
+	}
+	return nil
+}
+
+// runSchema prints the JSON Schema of payload files, for editor completion and validation
+func runSchema(args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("discord-webhook schema", stderr)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	_, err := stdout.Write(webhook.JSONSchema())
+	return err
+}
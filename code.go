@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// LanguageAuto makes CodeBlock detect the language of the code
+const LanguageAuto = "auto"
+
+// CodeBlock wraps code in a code block highlighted as language, such as "go" or "json",
+// or without highlighting when language is empty. With LanguageAuto, the language is
+// detected from the code; see DetectLanguage. Fences inside the code are broken up so
+// they cannot end the block early.
+func CodeBlock(code, language string) string {
+	if language == LanguageAuto {
+		language = DetectLanguage(code)
+	}
+	code = strings.ReplaceAll(strings.Trim(code, "\n"), "```", "`\u200b``")
+	return "```" + language + "\n" + code + "\n```"
+}
+
+var (
+	diffLine     = regexp.MustCompile(`(?m)^(diff --git |--- |\+\+\+ |@@ -\d)`)
+	goDecl       = regexp.MustCompile(`(?m)^(package \w+$|func (\(\w+ \*?\w+\) )?\w+\(|import \(|type \w+ (struct|interface) \{)`)
+	sqlStatement = regexp.MustCompile(`(?is)^(select\s.+\sfrom\s|insert\s+into\s|update\s.+\sset\s|delete\s+from\s|create\s+(table|index|view)\s|alter\s+table\s|drop\s+(table|index|view)\s|with\s.+\sas\s*\()`)
+	yamlLine     = regexp.MustCompile(`^\s*(- )?[\w.-]+:(\s|$)`)
+)
+
+// DetectLanguage guesses whether code is JSON, YAML, Go, SQL or a diff from
+// simple heuristics and returns the code block language, or an empty string
+// when the code looks like none of them
+func DetectLanguage(code string) string {
+	trimmed := strings.TrimSpace(code)
+	switch {
+	case trimmed == "":
+		return ""
+	case (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid([]byte(trimmed)):
+		return "json"
+	case len(diffLine.FindAllString(trimmed, 2)) == 2:
+		return "diff"
+	case goDecl.MatchString(trimmed):
+		return "go"
+	case sqlStatement.MatchString(trimmed):
+		return "sql"
+	case looksLikeYAML(trimmed):
+		return "yaml"
+	}
+	return ""
+}
+
+// looksLikeYAML reports whether most lines of the text are YAML keys or list items
+func looksLikeYAML(text string) bool {
+	keys, lines := 0, 0
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "---" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		lines++
+		if yamlLine.MatchString(line) {
+			keys++
+		} else if !strings.HasPrefix(trimmed, "- ") && !strings.HasPrefix(line, " ") {
+			// Continuation lines are indented; anything else is not YAML
+			return false
+		}
+	}
+	return keys >= 2 && keys*2 >= lines
+}
@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// duplicateCollapser edits the previous message instead of resending identical
+// consecutive payloads. Its delivery state is only used by the queue worker.
+type duplicateCollapser struct {
+	enabled bool
+	window  time.Duration
+
+	lastHash      string
+	lastMessageID string
+	lastPayload   Webhook
+	firstSent     time.Time
+	seen          int
+}
+
+// WithDuplicateCollapsing collapses identical payloads queued one after another into
+// a single message, which is edited with a "seen N times" note instead of being sent
+// again, so alert storms do not scroll the channel. Duplicates arriving more than
+// window after the original message was sent start a new message; 0 means no limit.
+// It only applies to queued messages.
+func WithDuplicateCollapsing(window time.Duration) ClientOption {
+	return func(c *Client) {
+		c.queue.collapse.enabled = true
+		c.queue.collapse.window = window
+	}
+}
+
+// deliver sends the message, or edits the previous one if the message repeats it
+func (d *duplicateCollapser) deliver(ctx context.Context, c *Client, message *queuedMessage) error {
+	now := time.Now()
+	duplicate := message.hash == d.lastHash && d.lastMessageID != "" &&
+		(d.window <= 0 || now.Sub(d.firstSent) <= d.window)
+
+	if duplicate {
+		seen := d.seen + 1 + message.repeats
+		err := c.Edit(ctx, d.lastMessageID, withSeenNote(d.lastPayload, seen, now))
+		if err == nil {
+			d.seen = seen
+			return nil
+		}
+		if !isNotFound(err) {
+			return err
+		}
+		// The original message was deleted, start over with a new one
+		d.lastMessageID = ""
+	}
+
+	payload := message.payload
+	seen := 1 + message.repeats
+	if seen > 1 {
+		payload = withSeenNote(payload, seen, now)
+	}
+	sent, err := c.execute(ctx, payload, true)
+	if err != nil {
+		return err
+	}
+	d.lastHash = message.hash
+	d.lastMessageID = sent.ID
+	d.lastPayload = message.payload
+	d.firstSent = now
+	d.seen = seen
+	return nil
+}
+
+// withSeenNote appends the repeat count to the content as Discord subtext
+func withSeenNote(webhookPayload Webhook, seen int, last time.Time) Webhook {
+	return withNote(webhookPayload, fmt.Sprintf("-# seen %d times, last <t:%d:R>", seen, last.Unix()))
+}
+
+// withNote appends a line to the content, shortening the content if needed to stay within the limit
+func withNote(webhookPayload Webhook, note string) Webhook {
+	content := webhookPayload.Content
+	if budget := maxContentLength - utf8.RuneCountInString(note) - 1; utf8.RuneCountInString(content) > budget {
+		content = truncateRunes(content, budget)
+	}
+	if content != "" {
+		content += "\n"
+	}
+	webhookPayload.Content = content + note
+	return webhookPayload
+}
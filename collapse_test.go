@@ -0,0 +1,111 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/webhooktest"
+)
+
+func TestDuplicateCollapsingEditsPreviousMessage(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	client := webhook.NewClient(server.URL, webhook.WithDuplicateCollapsing(0))
+
+	alert := webhook.Webhook{Content: "database down"}
+	for i := 0; i < 3; i++ {
+		delivery, err := client.Enqueue(alert)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Settle each one, so none are merged while queued
+		if result := result(t, delivery); result.Err != nil {
+			t.Fatal(result.Err)
+		}
+	}
+	if _, err := client.Enqueue(webhook.Webhook{Content: "database up"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 4 {
+		t.Fatalf("got %d requests, want 4", len(requests))
+	}
+	if requests[0].Method != http.MethodPost || requests[3].Method != http.MethodPost {
+		t.Errorf("first and last requests are %s and %s, want POST", requests[0].Method, requests[3].Method)
+	}
+	for i, want := range []string{"seen 2 times", "seen 3 times"} {
+		request := requests[i+1]
+		if request.Method != http.MethodPatch || !strings.HasSuffix(request.Path, "/messages/1") {
+			t.Errorf("request %d is %s %s, want an edit of message 1", i+1, request.Method, request.Path)
+		}
+		if !strings.HasPrefix(request.Payload.Content, "database down\n-# "+want) {
+			t.Errorf("edit %d content = %q, want %q", i+1, request.Payload.Content, want)
+		}
+	}
+}
+
+func TestDuplicateCollapsingStartsOverAfterWindow(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	client := webhook.NewClient(server.URL, webhook.WithDuplicateCollapsing(50*time.Millisecond))
+	defer client.Close(context.Background())
+
+	alert := webhook.Webhook{Content: "database down"}
+	for i := 0; i < 2; i++ {
+		delivery, err := client.Enqueue(alert)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result := result(t, delivery); result.Err != nil {
+			t.Fatal(result.Err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	server.AssertRequestCount(t, 2)
+	for _, request := range server.Requests() {
+		if request.Method != http.MethodPost || request.Payload.Content != "database down" {
+			t.Errorf("got %s with content %q, want a new message", request.Method, request.Payload.Content)
+		}
+	}
+}
+
+func TestDuplicateCollapsingResendsDeletedMessage(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	client := webhook.NewClient(server.URL, webhook.WithDuplicateCollapsing(0))
+	defer client.Close(context.Background())
+
+	alert := webhook.Webhook{Content: "database down"}
+	first, err := client.Enqueue(alert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := result(t, first); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+	server.Respond(webhooktest.Error(http.StatusNotFound, webhook.ErrorCodeUnknownMessage, "Unknown Message"))
+	second, err := client.Enqueue(alert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := result(t, second); result.Err != nil {
+		t.Fatal(result.Err)
+	}
+
+	requests := server.Requests()
+	if len(requests) != 3 || requests[1].Method != http.MethodPatch || requests[2].Method != http.MethodPost {
+		t.Fatalf("got %d requests, want the failed edit followed by a new message", len(requests))
+	}
+	if got := requests[2].Payload.Content; got != "database down" {
+		t.Errorf("new message content = %q, want the payload without a note", got)
+	}
+}
@@ -0,0 +1,196 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Component types supported by Discord. Select menus, like buttons other than link
+// buttons, are interactive and can only be sent by webhooks owned by an application.
+const (
+	ComponentTypeActionRow         = 1
+	ComponentTypeButton            = 2
+	ComponentTypeStringSelect      = 3
+	ComponentTypeUserSelect        = 5
+	ComponentTypeRoleSelect        = 6
+	ComponentTypeMentionableSelect = 7
+	ComponentTypeChannelSelect     = 8
+)
+
+// Button styles supported by Discord
+const (
+	ButtonStylePrimary   = 1
+	ButtonStyleSecondary = 2
+	ButtonStyleSuccess   = 3
+	ButtonStyleDanger    = 4
+	ButtonStyleLink      = 5
+)
+
+const (
+	maxActionRows        = 5
+	maxButtonsPerRow     = 5
+	maxButtonLabelRunes  = 80
+	maxCustomIDRunes     = 100
+	maxPlaceholderRunes  = 150
+	maxSelectOptions     = 25
+	maxSelectOptionRunes = 100
+	maxSelectValuesLimit = 25
+)
+
+// Component represents a message component such as an action row, a button or a select menu
+type Component struct {
+	Type  int    `json:"type"`
+	Style int    `json:"style,omitempty"`
+	Label string `json:"label,omitempty"`
+	URL   string `json:"url,omitempty"`
+	// CustomID identifies interactive components in the interactions the application receives
+	CustomID string `json:"custom_id,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+
+	// Placeholder, Options, MinValues and MaxValues configure select menus. Zero
+	// MinValues and MaxValues use Discord's default of 1.
+	Placeholder string         `json:"placeholder,omitempty"`
+	Options     []SelectOption `json:"options,omitempty"`
+	MinValues   int            `json:"min_values,omitempty"`
+	MaxValues   int            `json:"max_values,omitempty"`
+
+	Components []Component `json:"components,omitempty"`
+}
+
+// SelectOption is a choice of a string select menu
+type SelectOption struct {
+	Label       string `json:"label"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Default     bool   `json:"default,omitempty"`
+}
+
+// AddComponent adds a top-level component (usually an action row) to the webhook
+func (w *Webhook) AddComponent(component Component) {
+	w.Components = append(w.Components, component)
+}
+
+// CreateActionRow creates an action row holding the given components
+func CreateActionRow(components ...Component) Component {
+	return Component{
+		Type:       ComponentTypeActionRow,
+		Components: components,
+	}
+}
+
+// CreateLinkButton creates a button that opens the given URL.
+// Link buttons are the only buttons that can be sent by webhooks not owned by an application.
+func CreateLinkButton(label string, url string) Component {
+	return Component{
+		Type:  ComponentTypeButton,
+		Style: ButtonStyleLink,
+		Label: label,
+		URL:   url,
+	}
+}
+
+// CreateButton creates an interactive button, which sends an interaction with the custom
+// ID to the application owning the webhook when clicked
+func CreateButton(style int, label string, customID string) Component {
+	return Component{
+		Type:     ComponentTypeButton,
+		Style:    style,
+		Label:    label,
+		CustomID: customID,
+	}
+}
+
+// CreateStringSelect creates a select menu offering the options. A select menu takes
+// up a whole action row.
+func CreateStringSelect(customID string, placeholder string, options ...SelectOption) Component {
+	return Component{
+		Type:        ComponentTypeStringSelect,
+		CustomID:    customID,
+		Placeholder: placeholder,
+		Options:     options,
+	}
+}
+
+// CreateSelectOption creates an option of a string select menu
+func CreateSelectOption(label string, value string, description string) SelectOption {
+	return SelectOption{
+		Label:       label,
+		Value:       value,
+		Description: description,
+	}
+}
+
+// isSelectMenu reports whether the component type is a select menu
+func isSelectMenu(componentType int) bool {
+	switch componentType {
+	case ComponentTypeStringSelect, ComponentTypeUserSelect, ComponentTypeRoleSelect,
+		ComponentTypeMentionableSelect, ComponentTypeChannelSelect:
+		return true
+	}
+	return false
+}
+
+// HasInteractiveComponents reports whether the message has buttons other than link
+// buttons or select menus, which only webhooks owned by an application can send
+func (w Webhook) HasInteractiveComponents() bool {
+	for _, component := range flattenComponents(w.Components) {
+		if isSelectMenu(component.Type) || component.Type == ComponentTypeButton && component.Style != ButtonStyleLink {
+			return true
+		}
+	}
+	return false
+}
+
+// explainComponentError adds why Discord may have rejected the interactive components
+// of a payload to its error
+func explainComponentError(err error, webhookPayload Webhook) error {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || !webhookPayload.HasInteractiveComponents() {
+		return err
+	}
+	for path := range apiErr.FieldErrors() {
+		if path == "components" || strings.HasPrefix(path, "components.") {
+			return fmt.Errorf("%w (%s)", err, errInteractiveComponents)
+		}
+	}
+	return err
+}
+
+// linkButtonRows lays out link buttons into as many action rows as Discord allows.
+// Buttons that do not fit are dropped.
+func linkButtonRows(buttons []Component) []Component {
+	var rows []Component
+	for len(buttons) > 0 && len(rows) < maxActionRows {
+		n := len(buttons)
+		if n > maxButtonsPerRow {
+			n = maxButtonsPerRow
+		}
+		rows = append(rows, CreateActionRow(buttons[:n]...))
+		buttons = buttons[n:]
+	}
+	return rows
+}
+
+// truncateRunes shortens s to at most limit runes
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
+
+// withQueryParam returns the webhook URL with the query parameter set
+func withQueryParam(webhookURL, key, value string) (string, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid webhook URL: %v", err)
+	}
+	query := u.Query()
+	query.Set(key, value)
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"compress/gzip"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+)
+
+// textExtensions are text file types that mime.TypeByExtension does not know on every system
+var textExtensions = map[string]bool{
+	".txt": true, ".log": true, ".out": true, ".csv": true, ".tsv": true, ".md": true,
+	".json": true, ".jsonl": true, ".ndjson": true, ".yaml": true, ".yml": true, ".xml": true,
+	".html": true, ".toml": true, ".ini": true, ".sql": true, ".diff": true, ".patch": true,
+}
+
+// WithAttachmentCompression gzips text files larger than threshold bytes before they
+// are uploaded by SendFiles, renaming log.txt to log.txt.gz, so large logs fit under
+// Discord's upload limit. Files of unknown size, such as from FileFromReader, are sent as they are.
+func WithAttachmentCompression(threshold int64) ClientOption {
+	return func(c *Client) {
+		c.gzipThreshold = threshold
+	}
+}
+
+// compressFiles returns the files with large text files replaced by gzipped ones
+func (c *Client) compressFiles(files []File) []File {
+	if c.gzipThreshold <= 0 {
+		return files
+	}
+	compressed := make([]File, len(files))
+	for i, file := range files {
+		if file.Size > c.gzipThreshold && isTextFile(file.Name) {
+			file = gzipFile(file)
+		}
+		compressed[i] = file
+	}
+	return compressed
+}
+
+// isTextFile reports whether the file name has the extension of a text format
+func isTextFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	if textExtensions[ext] {
+		return true
+	}
+	mediaType, _, _ := mime.ParseMediaType(mime.TypeByExtension(ext))
+	return strings.HasPrefix(mediaType, "text/")
+}
+
+// gzipFile returns the file compressed while it is read, named with a .gz suffix
+func gzipFile(file File) File {
+	open := file.Open
+	file.Name += ".gz"
+	file.Size = 0
+	file.ContentType = "application/gzip"
+	file.Open = func() (io.ReadCloser, error) {
+		content, err := open()
+		if err != nil {
+			return nil, err
+		}
+		reader, writer := io.Pipe()
+		go func() {
+			defer content.Close()
+			gz := gzip.NewWriter(writer)
+			if _, err := io.Copy(gz, content); err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+			writer.CloseWithError(gz.Close())
+		}()
+		return reader, nil
+	}
+	return file
+}
@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Contentf sets the content to the formatted text. When it is longer than Discord's
+// 2000 character limit, the content is set anyway and an error reports by how much;
+// SendSplit sends such a payload as several messages.
+func (w *Webhook) Contentf(format string, args ...any) error {
+	w.Content = fmt.Sprintf(format, args...)
+	return w.checkContentLength()
+}
+
+// AppendContent appends text to the content on a new line, reporting like Contentf
+// when the content grows beyond the limit
+func (w *Webhook) AppendContent(text string) error {
+	if w.Content != "" {
+		w.Content += "\n"
+	}
+	w.Content += text
+	return w.checkContentLength()
+}
+
+// ContentRemaining returns how many characters can still be added to the content
+// before it reaches the limit, or a negative number when it is over
+func (w *Webhook) ContentRemaining() int {
+	return maxContentLength - utf8.RuneCountInString(w.Content)
+}
+
+// checkContentLength reports content beyond the limit
+func (w *Webhook) checkContentLength() error {
+	if remaining := w.ContentRemaining(); remaining < 0 {
+		return fmt.Errorf("content length %d exceeds the limit of %d characters by %d; send it with SendSplit",
+			maxContentLength-remaining, maxContentLength, -remaining)
+	}
+	return nil
+}
+
+// splitContent splits text into chunks within the limit, preferably at line breaks and then at spaces
+func splitContent(text string, limit int) []string {
+	var chunks []string
+	for utf8.RuneCountInString(text) > limit {
+		head := truncateRunes(text, limit)
+		cut := strings.LastIndex(head, "\n")
+		if cut <= 0 {
+			cut = strings.LastIndex(head, " ")
+		}
+		if cut <= 0 {
+			chunks = append(chunks, head)
+			text = text[len(head):]
+			continue
+		}
+		// The line break or space the text is split at is dropped
+		chunks = append(chunks, head[:cut])
+		text = text[cut+1:]
+	}
+	return append(chunks, text)
+}
@@ -0,0 +1,336 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// cronSchedule is a parsed five-field cron expression. Each field is a bit set of allowed values.
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny record unrestricted fields, which change how day of month and day of week combine
+	domAny, dowAny bool
+}
+
+// cronField describes the range of a cron field
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute = cronField{name: "minute", min: 0, max: 59}
+	cronHour   = cronField{name: "hour", min: 0, max: 23}
+	cronDom    = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth  = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	cronDow = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// cronMacros are the shorthand schedules accepted in place of five fields
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// parseCron parses a standard cron expression such as "0 9 * * MON-FRI" or a macro such as "@daily"
+func parseCron(spec string) (*cronSchedule, error) {
+	if macro, ok := cronMacros[strings.ToLower(strings.TrimSpace(spec))]; ok {
+		spec = macro
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", spec, len(fields))
+	}
+
+	var s cronSchedule
+	var err error
+	if s.minute, err = cronMinute.parse(fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %v", spec, err)
+	}
+	if s.hour, err = cronHour.parse(fields[1]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %v", spec, err)
+	}
+	if s.dom, err = cronDom.parse(fields[2]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %v", spec, err)
+	}
+	if s.month, err = cronMonth.parse(fields[3]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %v", spec, err)
+	}
+	if s.dow, err = cronDow.parse(fields[4]); err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %v", spec, err)
+	}
+	// Both 0 and 7 mean Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domAny = fields[2] == "*" || fields[2] == "?"
+	s.dowAny = fields[4] == "*" || fields[4] == "?"
+	return &s, nil
+}
+
+// parse parses a comma separated list of values, ranges and steps into a bit set
+func (f cronField) parse(field string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %s field %q", f.name, part)
+			}
+			step = n
+			part = part[:i]
+		}
+
+		low, high := f.min, f.max
+		if part != "*" && part != "?" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if low, err = f.value(bounds[0]); err != nil {
+				return 0, err
+			}
+			high = low
+			if len(bounds) == 2 {
+				if high, err = f.value(bounds[1]); err != nil {
+					return 0, err
+				}
+			} else if step > 1 {
+				// "5/15" means every 15 starting at 5
+				high = f.max
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range in %s field %q", f.name, part)
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value parses a single number or name of the field
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q (expected %d-%d)", f.name, s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// matchesDay reports whether the schedule runs on the day of t.
+// Like cron, restricting both day fields runs on days matching either.
+func (s *cronSchedule) matchesDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	}
+	return dom || dow
+}
+
+// next returns the first time after t at which the schedule runs, in t's location
+func (s *cronSchedule) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// Give up after five years, which only happens for impossible dates such as 30 February
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = advanceTo(t, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location()), time.Hour)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = advanceTo(t, time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location()), time.Hour)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = advanceTo(t, time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location()),
+				time.Duration(60-t.Minute())*time.Minute)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// advanceTo returns next, the start of the following hour, day or month. When a clock
+// change skips that time, as at 02:00 when clocks spring forward, time.Date may return
+// a time at or before t; t is then moved ahead by step instead, so next never stalls.
+func advanceTo(t, next time.Time, step time.Duration) time.Time {
+	if next.After(t) {
+		return next
+	}
+	return t.Add(step)
+}
+
+// Cron sends messages produced by functions on cron schedules, so periodic reports
+// can live inside the notifier process:
+//
+//	cron := webhook.NewCron(client, webhook.WithJitter(time.Minute))
+//	cron.Add("0 9 * * MON-FRI", dailySummary)
+//	err := cron.Run(ctx)
+type Cron struct {
+	client   *Client
+	jitter   time.Duration
+	location *time.Location
+	onError  func(spec string, err error)
+	jobs     []*cronJob
+}
+
+// cronJob is a payload function with its schedule
+type cronJob struct {
+	spec     string
+	schedule *cronSchedule
+	produce  func(ctx context.Context) (Webhook, error)
+
+	// running is 1 while a run is in progress, so slow runs are never overlapped
+	running int32
+}
+
+// CronOption configures a Cron
+type CronOption func(*Cron)
+
+// WithJitter delays every run by a random duration of up to maxDelay, so many
+// processes sharing a schedule do not hit the webhook at the same moment
+func WithJitter(maxDelay time.Duration) CronOption {
+	return func(c *Cron) {
+		c.jitter = maxDelay
+	}
+}
+
+// WithLocation sets the time zone schedules are evaluated in. It defaults to time.Local.
+func WithLocation(location *time.Location) CronOption {
+	return func(c *Cron) {
+		c.location = location
+	}
+}
+
+// WithCronErrorHandler sets a function called when a run fails to produce or send its message
+func WithCronErrorHandler(handler func(spec string, err error)) CronOption {
+	return func(c *Cron) {
+		c.onError = handler
+	}
+}
+
+// NewCron creates a scheduler sending through the client
+func NewCron(client *Client, options ...CronOption) *Cron {
+	c := &Cron{client: client, location: time.Local}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// Add registers a function producing a payload on a cron schedule. The expression
+// has the five standard fields (minute, hour, day of month, month, day of week)
+// with lists, ranges, steps and names, or is one of @hourly, @daily, @weekly,
+// @monthly and @yearly. Returning a payload without content, embeds or components
+// skips the run. A run is skipped as well while the previous one is still in progress.
+func (c *Cron) Add(spec string, produce func(ctx context.Context) (Webhook, error)) error {
+	schedule, err := parseCron(spec)
+	if err != nil {
+		return err
+	}
+	c.jobs = append(c.jobs, &cronJob{spec: spec, schedule: schedule, produce: produce})
+	return nil
+}
+
+// Run runs the schedules until ctx is cancelled, then waits for runs in progress to finish
+func (c *Cron) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, job := range c.jobs {
+		wg.Add(1)
+		go func(job *cronJob) {
+			defer wg.Done()
+			c.schedule(ctx, job, &wg)
+		}(job)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// schedule starts the job every time its schedule comes due
+func (c *Cron) schedule(ctx context.Context, job *cronJob, wg *sync.WaitGroup) {
+	for {
+		next := job.schedule.next(time.Now().In(c.location))
+		if next.IsZero() {
+			c.reportError(job.spec, fmt.Errorf("schedule %q never runs", job.spec))
+			return
+		}
+		if c.jitter > 0 {
+			next = next.Add(time.Duration(rand.Int63n(int64(c.jitter))))
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if !atomic.CompareAndSwapInt32(&job.running, 0, 1) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer atomic.StoreInt32(&job.running, 0)
+			c.run(ctx, job)
+		}()
+	}
+}
+
+// run produces and sends a single message
+func (c *Cron) run(ctx context.Context, job *cronJob) {
+	payload, err := job.produce(ctx)
+	if err != nil {
+		c.reportError(job.spec, err)
+		return
+	}
+	if payload.Content == "" && len(payload.Embeds) == 0 && len(payload.Components) == 0 && payload.Poll == nil {
+		return
+	}
+	if err := c.client.Send(ctx, payload); err != nil {
+		c.reportError(job.spec, err)
+	}
+}
+
+// reportError passes a failed run to the error handler, if there is one
+func (c *Cron) reportError(spec string, err error) {
+	if c.onError != nil {
+		c.onError(spec, err)
+	}
+}
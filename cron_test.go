@@ -0,0 +1,91 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronRejectsInvalidExpressions(t *testing.T) {
+	for _, spec := range []string{
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * * FOO",
+	} {
+		if _, err := parseCron(spec); err == nil {
+			t.Errorf("parseCron(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	utc := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"weekdays skip the weekend", "0 9 * * MON-FRI", utc(2024, time.March, 8, 9, 0), utc(2024, time.March, 11, 9, 0)},
+		{"runs strictly after from", "0 9 * * *", utc(2024, time.March, 8, 9, 0), utc(2024, time.March, 9, 9, 0)},
+		{"seconds are ignored", "*/15 * * * *", time.Date(2024, time.March, 8, 10, 59, 30, 0, time.UTC), utc(2024, time.March, 8, 11, 0)},
+		{"step with a start", "5/20 * * * *", utc(2024, time.March, 8, 10, 45), utc(2024, time.March, 8, 11, 5)},
+		{"year rollover", "59 23 31 12 *", utc(2024, time.December, 31, 23, 59), utc(2025, time.December, 31, 23, 59)},
+		{"short months are skipped", "0 0 31 * *", utc(2024, time.January, 31, 0, 0), utc(2024, time.March, 31, 0, 0)},
+		{"leap day", "0 0 29 2 *", utc(2023, time.March, 1, 0, 0), utc(2024, time.February, 29, 0, 0)},
+		{"impossible date", "0 0 30 2 *", utc(2024, time.January, 1, 0, 0), time.Time{}},
+		{"both day fields match either", "0 0 1 * MON", utc(2024, time.March, 1, 0, 0), utc(2024, time.March, 4, 0, 0)},
+		{"7 is Sunday", "0 12 * * 7", utc(2024, time.March, 8, 0, 0), utc(2024, time.March, 10, 12, 0)},
+		{"macro", "@monthly", utc(2024, time.March, 8, 0, 0), utc(2024, time.April, 1, 0, 0)},
+		// 02:30 does not exist on the day clocks spring forward, so that day is skipped
+		{"spring forward", "30 2 * * *", time.Date(2024, time.March, 10, 0, 0, 0, 0, newYork), time.Date(2024, time.March, 11, 2, 30, 0, 0, newYork)},
+		{"daily across spring forward", "0 9 * * *", time.Date(2024, time.March, 10, 0, 0, 0, 0, newYork), time.Date(2024, time.March, 10, 9, 0, 0, 0, newYork)},
+		{"after spring forward", "0 3 * * *", time.Date(2024, time.March, 10, 0, 0, 0, 0, newYork), time.Date(2024, time.March, 10, 3, 0, 0, 0, newYork)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := parseCron(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronNextFallBack(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	s, err := parseCron("30 1 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 01:30 happens twice on the day clocks fall back; every run must still move forward
+	from := time.Date(2024, time.November, 3, 0, 0, 0, 0, newYork)
+	for i := 0; i < 3; i++ {
+		next := s.next(from)
+		if !next.After(from) {
+			t.Fatalf("next(%v) = %v, which is not after it", from, next)
+		}
+		if next.Hour() != 1 || next.Minute() != 30 {
+			t.Errorf("next(%v) = %v, want 01:30", from, next)
+		}
+		from = next
+	}
+}
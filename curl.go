@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ToCurl returns a curl command that sends the webhook to webhookURL, for debugging and
+// sharing reproductions. The webhook token is redacted so the command can be posted in
+// issues; use ToCurlWithToken to get a command that runs as is.
+func (w *Webhook) ToCurl(webhookURL string) (string, error) {
+	return w.curl(RedactWebhookURL(webhookURL))
+}
+
+// ToCurlWithToken returns a curl command that sends the webhook to webhookURL, token included
+func (w *Webhook) ToCurlWithToken(webhookURL string) (string, error) {
+	return w.curl(webhookURL)
+}
+
+// curl builds the curl command for the URL
+func (w *Webhook) curl(webhookURL string) (string, error) {
+	jsonData, err := json.Marshal(w)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JSON payload: %v", err)
+	}
+
+	if len(w.Components) > 0 {
+		if webhookURL, err = withQueryParam(webhookURL, "with_components", "true"); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("curl -X POST -H 'Content-Type: application/json' --data-raw %s %s",
+		shellQuote(string(jsonData)), shellQuote(webhookURL)), nil
+}
+
+// shellQuote quotes s for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"context"
+	"time"
+)
+
+// WithAttemptTimeout bounds every request to Discord, so a hanging connection fails
+// and can be retried instead of taking up the whole operation. Unlike the Timeout of
+// an http.Client, it applies to requests only, not to the waits between retries.
+func WithAttemptTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.attemptTimeout = timeout
+	}
+}
+
+// WithOperationTimeout bounds a whole operation: a Send, Edit or SendFiles call with
+// its repeats, or the delivery of a queued message including all retries and the
+// waits for rate limits. Retries whose wait would end after the deadline are not
+// made, so the last error is reported in time. Combine it with WithAttemptTimeout to
+// keep one slow attempt from using up the deadline.
+func WithOperationTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		c.operationTimeout = timeout
+	}
+}
+
+// operationContext returns the context of an operation, bounded by the operation timeout
+func (c *Client) operationContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.operationTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.operationTimeout)
+}
+
+// attemptContext returns the context of a single request, bounded by the attempt timeout
+func (c *Client) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.attemptTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.attemptTimeout)
+}
+
+// fitsDeadline reports whether waiting for the delay ends before the context's deadline
+func fitsDeadline(ctx context.Context, delay time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > delay
+}
@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+	"sync/atomic"
+)
+
+// webhookURLPattern matches Discord webhook URLs in dumped payloads, whose tokens are redacted
+var webhookURLPattern = regexp.MustCompile(`https?://[^\s"]+/api/(?:v\d+/)?webhooks/\d+/[\w-]+`)
+
+// debugDumper writes the payloads of requests for debugging
+type debugDumper struct {
+	// enabled is 1 while payloads are dumped
+	enabled int32
+
+	mu      sync.Mutex
+	out     io.Writer
+	secrets []*regexp.Regexp
+}
+
+// WithDebugDump writes the rendered JSON payload of every request to out, or stderr when
+// out is nil, before it is sent, to diagnose payloads Discord rejects. Webhook tokens
+// are redacted, and so is text matching any of the secret patterns, such as API keys
+// quoted in alerts. Dumping can be turned off and on with SetDebugDump.
+func WithDebugDump(out io.Writer, secrets ...*regexp.Regexp) ClientOption {
+	return func(c *Client) {
+		if out == nil {
+			out = os.Stderr
+		}
+		c.debug.out = out
+		c.debug.secrets = append([]*regexp.Regexp(nil), secrets...)
+		atomic.StoreInt32(&c.debug.enabled, 1)
+	}
+}
+
+// SetDebugDump turns dumping payloads on or off at runtime, such as from a signal
+// handler or an admin endpoint. Without WithDebugDump, payloads are dumped to stderr.
+func (c *Client) SetDebugDump(enabled bool) {
+	c.debug.mu.Lock()
+	if c.debug.out == nil {
+		c.debug.out = os.Stderr
+	}
+	c.debug.mu.Unlock()
+
+	var value int32
+	if enabled {
+		value = 1
+	}
+	atomic.StoreInt32(&c.debug.enabled, value)
+}
+
+// dump writes the request with its JSON payload, if dumping is enabled
+func (d *debugDumper) dump(method, requestURL string, jsonData []byte) {
+	if atomic.LoadInt32(&d.enabled) == 0 {
+		return
+	}
+	var body bytes.Buffer
+	if len(jsonData) > 0 {
+		if err := json.Indent(&body, jsonData, "", "  "); err != nil {
+			body.Reset()
+			body.Write(jsonData)
+		}
+		body.WriteByte('\n')
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	text := webhookURLPattern.ReplaceAllStringFunc(body.String(), RedactWebhookURL)
+	for _, secret := range d.secrets {
+		text = secret.ReplaceAllString(text, redactedToken)
+	}
+	fmt.Fprintf(d.out, "discord-webhook: %s %s\n%s", method, RedactWebhookURL(requestURL), text)
+}
@@ -0,0 +1,48 @@
+package webhook
+
+// MessageFlags change how Discord delivers or displays a message
+type MessageFlags int
+
+const (
+	// FlagSuppressEmbeds hides the link previews of URLs in the content
+	FlagSuppressEmbeds MessageFlags = 1 << 2
+	// FlagSuppressNotifications posts the message without push and desktop notifications
+	FlagSuppressNotifications MessageFlags = 1 << 12
+)
+
+// MessageDefaults are applied to new messages of a client that do not set them; see WithDefaults
+type MessageDefaults struct {
+	Username        string
+	AvatarURL       string
+	AllowedMentions *AllowedMentions
+	Flags           MessageFlags
+}
+
+// WithDefaults sets the username, avatar, allowed mentions and flags of new messages
+// that do not set their own, so they need not be repeated for every payload. Edits
+// are left alone, since Discord cannot change the author of a message.
+func WithDefaults(defaults MessageDefaults) ClientOption {
+	return func(c *Client) {
+		c.defaults = &defaults
+	}
+}
+
+// apply fills in the defaults the payload does not set
+func (d *MessageDefaults) apply(webhookPayload Webhook) Webhook {
+	if d == nil {
+		return webhookPayload
+	}
+	if webhookPayload.Username == "" {
+		webhookPayload.Username = d.Username
+	}
+	if webhookPayload.AvatarURL == "" {
+		webhookPayload.AvatarURL = d.AvatarURL
+	}
+	if webhookPayload.AllowedMentions == nil {
+		webhookPayload.AllowedMentions = d.AllowedMentions
+	}
+	if webhookPayload.Flags == 0 {
+		webhookPayload.Flags = d.Flags
+	}
+	return webhookPayload
+}
@@ -0,0 +1,137 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WithFormBodyDegradation makes the client repair payloads Discord rejects as an
+// invalid form body and retry them once: text fields named in Discord's error are
+// truncated to their limit, and embeds that cannot be repaired that way are dropped.
+// The report function, which may be nil, is called with the changes before the
+// retry, such as "truncated embeds.0.title to 256 characters", for logging.
+func WithFormBodyDegradation(report func(webhookPayload Webhook, changes []string)) ClientOption {
+	return func(c *Client) {
+		c.degrade = true
+		c.onDegrade = report
+	}
+}
+
+// degradable reports whether a failed request should be retried with a degraded payload
+func (c *Client) degradable(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if !c.degrade || !errors.As(err, &apiErr) || !errors.Is(apiErr, ErrValidation) {
+		return nil, false
+	}
+	return apiErr, true
+}
+
+// degradePayload repairs the fields named by Discord's error details, returning the
+// repaired payload and a description of each change
+func degradePayload(webhookPayload Webhook, fieldErrors map[string][]string) (Webhook, []string) {
+	paths := make([]string, 0, len(fieldErrors))
+	for path := range fieldErrors {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	webhookPayload.Embeds = append([]Embed(nil), webhookPayload.Embeds...)
+	var changes []string
+	drop := make(map[int]bool)
+	for _, path := range paths {
+		parts := strings.Split(path, ".")
+		if parts[0] != "embeds" {
+			if change, ok := truncateField(&webhookPayload, path); ok {
+				changes = append(changes, change)
+			}
+			continue
+		}
+
+		// Errors of the embeds as a whole, such as their total length, drop the last one
+		i := len(webhookPayload.Embeds) - 1
+		if len(parts) > 1 {
+			var err error
+			if i, err = strconv.Atoi(parts[1]); err != nil || i >= len(webhookPayload.Embeds) {
+				continue
+			}
+		}
+		if i < 0 {
+			continue
+		}
+		if len(parts) < 3 {
+			drop[i] = true
+		} else if change, ok := truncateEmbedField(&webhookPayload.Embeds[i], path, parts[2:]); ok {
+			changes = append(changes, change)
+		} else {
+			drop[i] = true
+		}
+	}
+
+	if len(drop) > 0 {
+		embeds := webhookPayload.Embeds[:0]
+		for i, embed := range webhookPayload.Embeds {
+			if drop[i] {
+				changes = append(changes, fmt.Sprintf("dropped embeds.%d", i))
+				continue
+			}
+			embeds = append(embeds, embed)
+		}
+		webhookPayload.Embeds = embeds
+	}
+	return webhookPayload, changes
+}
+
+// truncateField truncates a top-level text field of the payload to its limit
+func truncateField(webhookPayload *Webhook, path string) (string, bool) {
+	switch path {
+	case "content":
+		return truncateText(&webhookPayload.Content, path, maxContentLength)
+	case "username":
+		return truncateText(&webhookPayload.Username, path, maxUsernameLength)
+	case "thread_name":
+		return truncateText(&webhookPayload.ThreadName, path, maxThreadNameLength)
+	}
+	return "", false
+}
+
+// truncateEmbedField truncates the text field of an embed at the path below it
+func truncateEmbedField(embed *Embed, path string, parts []string) (string, bool) {
+	switch strings.Join(parts, ".") {
+	case "title":
+		return truncateText(&embed.Title, path, maxEmbedTitleLength)
+	case "description":
+		return truncateText(&embed.Description, path, maxEmbedDescriptionLength)
+	case "footer.text":
+		return truncateText(&embed.Footer.Text, path, maxFooterTextLength)
+	case "author.name":
+		return truncateText(&embed.Author.Name, path, maxAuthorNameLength)
+	}
+	if len(parts) != 3 || parts[0] != "fields" {
+		return "", false
+	}
+	j, err := strconv.Atoi(parts[1])
+	if err != nil || j >= len(embed.Fields) {
+		return "", false
+	}
+	embed.Fields = append([]Field(nil), embed.Fields...)
+	switch parts[2] {
+	case "name":
+		return truncateText(&embed.Fields[j].Name, path, maxFieldNameLength)
+	case "value":
+		return truncateText(&embed.Fields[j].Value, path, maxFieldValueLength)
+	}
+	return "", false
+}
+
+// truncateText shortens the text to the limit, ending it with an ellipsis. It fails
+// when the text is within the limit, as then its length is not what Discord rejected.
+func truncateText(text *string, path string, limit int) (string, bool) {
+	if len([]rune(*text)) <= limit {
+		return "", false
+	}
+	*text = truncateRunes(*text, limit-1) + "…"
+	return fmt.Sprintf("truncated %s to %d characters", path, limit), true
+}
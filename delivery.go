@@ -0,0 +1,111 @@
+package webhook
+
+import "sync"
+
+// SendResult is the outcome of a queued message
+type SendResult struct {
+	// Err is why the message could not be delivered, after any retries
+	Err error
+	// Attempts counts the delivery attempts, including retries
+	Attempts int
+	// Suppressed is set when the message was dropped without being sent, such as by a
+	// throttle, quiet hours or a pause
+	Suppressed bool
+}
+
+// Delivery tracks a queued message until it was delivered, failed or was suppressed.
+// Messages merged into another one, such as into a digest of quiet hours, share its result.
+type Delivery struct {
+	done chan SendResult
+
+	mu        sync.Mutex
+	finished  bool
+	result    SendResult
+	onSuccess []func(SendResult)
+	onFailure []func(SendResult)
+}
+
+// newDelivery creates the handle of a message that has not been delivered yet
+func newDelivery() *Delivery {
+	return &Delivery{done: make(chan SendResult, 1)}
+}
+
+// Done returns a channel receiving the result once the message is settled. The
+// channel is closed after the result, so later receives get a zero SendResult;
+// use Result to read it again.
+func (d *Delivery) Done() <-chan SendResult {
+	return d.done
+}
+
+// Result returns the result of the message, and whether it is settled yet
+func (d *Delivery) Result() (SendResult, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.result, d.finished
+}
+
+// OnSuccess registers a function called when the message was delivered. Callbacks
+// run on their own goroutine, right away if the message was already delivered.
+func (d *Delivery) OnSuccess(callback func(SendResult)) *Delivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.finished {
+		d.onSuccess = append(d.onSuccess, callback)
+	} else if d.result.Err == nil && !d.result.Suppressed {
+		go callback(d.result)
+	}
+	return d
+}
+
+// OnFailure registers a function called when the message could not be delivered.
+// Callbacks run on their own goroutine, right away if the message already failed.
+func (d *Delivery) OnFailure(callback func(SendResult)) *Delivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.finished {
+		d.onFailure = append(d.onFailure, callback)
+	} else if d.result.Err != nil {
+		go callback(d.result)
+	}
+	return d
+}
+
+// finish settles the message; only the first result counts
+func (d *Delivery) finish(result SendResult) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.finished {
+		return
+	}
+	d.finished, d.result = true, result
+	d.done <- result
+	close(d.done)
+
+	var callbacks []func(SendResult)
+	switch {
+	case result.Err != nil:
+		callbacks = d.onFailure
+	case !result.Suppressed:
+		callbacks = d.onSuccess
+	}
+	d.onSuccess, d.onFailure = nil, nil
+	if len(callbacks) > 0 {
+		go func() {
+			for _, callback := range callbacks {
+				callback(result)
+			}
+		}()
+	}
+}
+
+// finish settles the deliveries of the message
+func (m *queuedMessage) finish(result SendResult) {
+	for _, delivery := range m.deliveries {
+		delivery.finish(result)
+	}
+}
+
+// absorb makes the message settle the deliveries of another message merged into it
+func (m *queuedMessage) absorb(other *queuedMessage) {
+	m.deliveries = append(m.deliveries, other.deliveries...)
+}
@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Equal reports whether the payloads send the same message. Nil and empty lists are
+// equal, and so are the IDs of allowed mentions in any order.
+func (w Webhook) Equal(other Webhook) bool {
+	return Diff(w, other) == ""
+}
+
+// Diff describes how payload b differs from a, one line per changed value, named by
+// its JSON path, such as:
+//
+//	embeds[0].fields[2].value: "12 GB" -> "14 GB"
+//	embeds[1]: added {"title":"Disk"}
+//
+// It returns an empty string when the payloads are equal.
+func Diff(a, b Webhook) string {
+	var lines []string
+	diffValues("", reflect.ValueOf(a.normalized()), reflect.ValueOf(b.normalized()), &lines)
+	return strings.Join(lines, "\n")
+}
+
+// diffValues appends the differences between two values of the same type
+func diffValues(path string, a, b reflect.Value, lines *[]string) {
+	switch a.Kind() {
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			field := a.Type().Field(i)
+			if field.Name == "Extra" {
+				diffExtra(path, a.Field(i).Interface().(map[string]json.RawMessage), b.Field(i).Interface().(map[string]json.RawMessage), lines)
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if !field.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+			diffValues(name, a.Field(i), b.Field(i), lines)
+		}
+	case reflect.Slice:
+		for i := 0; i < a.Len() || i < b.Len(); i++ {
+			elementPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= a.Len():
+				*lines = append(*lines, elementPath+": added "+diffJSON(b.Index(i)))
+			case i >= b.Len():
+				*lines = append(*lines, elementPath+": removed "+diffJSON(a.Index(i)))
+			default:
+				diffValues(elementPath, a.Index(i), b.Index(i), lines)
+			}
+		}
+	case reflect.Ptr:
+		switch {
+		case a.IsNil() && b.IsNil():
+		case a.IsNil() || b.IsNil():
+			*lines = append(*lines, path+": "+diffJSON(a)+" -> "+diffJSON(b))
+		default:
+			diffValues(path, a.Elem(), b.Elem(), lines)
+		}
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*lines = append(*lines, path+": "+diffJSON(a)+" -> "+diffJSON(b))
+		}
+	}
+}
+
+// diffExtra appends the differences between the extra fields of two objects, which are
+// named like the fields they are merged with
+func diffExtra(path string, a, b map[string]json.RawMessage, lines *[]string) {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name := key
+		if path != "" {
+			name = path + "." + key
+		}
+		before, after := compactJSON(a[key]), compactJSON(b[key])
+		if before != after {
+			*lines = append(*lines, name+": "+before+" -> "+after)
+		}
+	}
+}
+
+// compactJSON renders raw JSON compactly, or "<none>" when it is missing
+func compactJSON(raw json.RawMessage) string {
+	if raw == nil {
+		return "<none>"
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return string(raw)
+	}
+	return buf.String()
+}
+
+// diffJSON renders a value of a diff as compact JSON
+func diffJSON(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return "<none>"
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprintf("%v", v.Interface())
+	}
+	return string(data)
+}
@@ -0,0 +1,189 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	digestColor          = 0xE67E22
+	digestSamplesPerKey  = 3
+	digestMaxSampleRunes = 200
+)
+
+// digestGroup collects the events submitted under one key
+type digestGroup struct {
+	key     string
+	count   int
+	first   time.Time
+	last    time.Time
+	samples []string
+}
+
+// Digest buffers events and periodically posts a single summary, such as
+// "37 errors from 4 services in the last 10m", grouped by key, instead of one
+// message per event
+type Digest struct {
+	client   *Client
+	interval time.Duration
+
+	// Event and Group are the singular nouns used in the summary. They default to
+	// "event" and "source"; plurals are formed by adding an "s".
+	Event string
+	Group string
+
+	// OnError is called when a summary could not be delivered
+	OnError func(err error)
+
+	mu     sync.Mutex
+	groups map[string]*digestGroup
+	total  int
+	since  time.Time
+}
+
+// NewDigest creates a digest posting a summary every interval
+func NewDigest(client *Client, interval time.Duration) *Digest {
+	return &Digest{
+		client:   client,
+		interval: interval,
+		Event:    "event",
+		Group:    "source",
+		groups:   make(map[string]*digestGroup),
+		since:    time.Now(),
+	}
+}
+
+// Add records an event under the key, such as the name of the service that logged an error
+func (d *Digest) Add(key, message string) {
+	now := time.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	group, ok := d.groups[key]
+	if !ok {
+		group = &digestGroup{key: key, first: now}
+		d.groups[key] = group
+	}
+	group.count++
+	group.last = now
+	d.total++
+
+	// Keep the most recent distinct messages as samples
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return
+	}
+	for i, sample := range group.samples {
+		if sample == message {
+			group.samples = append(group.samples[:i], group.samples[i+1:]...)
+			break
+		}
+	}
+	group.samples = append(group.samples, message)
+	if len(group.samples) > digestSamplesPerKey {
+		group.samples = group.samples[1:]
+	}
+}
+
+// Run posts a summary every interval until ctx is cancelled, then posts the remaining events
+func (d *Digest) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			d.report(d.Flush(flushCtx))
+			cancel()
+			return ctx.Err()
+		case <-ticker.C:
+			d.report(d.Flush(ctx))
+		}
+	}
+}
+
+// Flush posts a summary of the buffered events and starts a new period. Nothing is
+// posted when no events were added.
+func (d *Digest) Flush(ctx context.Context) error {
+	d.mu.Lock()
+	groups := make([]*digestGroup, 0, len(d.groups))
+	for _, group := range d.groups {
+		groups = append(groups, group)
+	}
+	total, since := d.total, d.since
+	d.groups = make(map[string]*digestGroup)
+	d.total = 0
+	d.since = time.Now()
+	d.mu.Unlock()
+
+	if total == 0 {
+		return nil
+	}
+	return d.client.Send(ctx, d.summary(groups, total, time.Since(since)))
+}
+
+// summary builds the digest message, with one field per key, busiest first
+func (d *Digest) summary(groups []*digestGroup, total int, period time.Duration) Webhook {
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].count != groups[j].count {
+			return groups[i].count > groups[j].count
+		}
+		return groups[i].key < groups[j].key
+	})
+
+	embed := Embed{
+		Title: fmt.Sprintf("%s from %s in the last %s",
+			pluralize(total, d.Event), pluralize(len(groups), d.Group), formatDuration(period)),
+		Color:     digestColor,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+
+	for i, group := range groups {
+		if i == maxEmbedFields-1 && len(groups) > maxEmbedFields {
+			rest := 0
+			for _, g := range groups[i:] {
+				rest += g.count
+			}
+			embed.Fields = append(embed.Fields, Field{
+				Name:  fmt.Sprintf("%d more", len(groups)-i),
+				Value: pluralize(rest, d.Event),
+			})
+			break
+		}
+
+		var value strings.Builder
+		for _, sample := range group.samples {
+			value.WriteString("• " + truncateRunes(sample, digestMaxSampleRunes) + "\n")
+		}
+		fmt.Fprintf(&value, "First <t:%d:R>, last <t:%d:R>", group.first.Unix(), group.last.Unix())
+
+		name := group.key
+		if name == "" {
+			name = "(none)"
+		}
+		embed.Fields = append(embed.Fields, Field{
+			Name:  truncateRunes(fmt.Sprintf("%s (%d)", name, group.count), maxFieldNameLength),
+			Value: truncateRunes(value.String(), maxFieldValueLength),
+		})
+	}
+	return Webhook{Embeds: []Embed{embed}}
+}
+
+// report passes a failed flush to the error handler, if there is one
+func (d *Digest) report(err error) {
+	if err != nil && d.OnError != nil {
+		d.OnError(err)
+	}
+}
+
+// pluralize formats a count with a noun, adding an "s" unless the count is one
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,142 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// editOptions are the settings of a single edit
+type editOptions struct {
+	threadID       string
+	withComponents bool
+	files          []File
+
+	// keep lists the attachments to keep when keepOnly is set; remove those to drop
+	keep     []string
+	keepOnly bool
+	remove   []string
+}
+
+// EditOption configures a single edit
+type EditOption func(*editOptions)
+
+// EditInThread edits a message the webhook posted in a thread, like editing it through
+// a client returned by InThread
+func EditInThread(threadID string) EditOption {
+	return func(o *editOptions) {
+		o.threadID = threadID
+	}
+}
+
+// EditWithComponents makes Discord accept components from a webhook not owned by an
+// application even when the payload has none of its own, such as when they are set
+// through Extra. Payloads with components set it on their own.
+func EditWithComponents() EditOption {
+	return func(o *editOptions) {
+		o.withComponents = true
+	}
+}
+
+// EditAddFiles uploads the files with the edit, attached after the attachments the
+// message keeps. Discord drops the attachments an edit does not list, so unless
+// EditKeepAttachments, EditRemoveAttachments or EditReplaceAttachments say otherwise,
+// or the payload lists its Attachments itself, the message is fetched first and keeps
+// all of them.
+func EditAddFiles(files ...File) EditOption {
+	return func(o *editOptions) {
+		o.files = append(o.files, files...)
+	}
+}
+
+// EditKeepAttachments keeps only the listed attachments of the message, by ID, and
+// drops all others
+func EditKeepAttachments(ids ...string) EditOption {
+	return func(o *editOptions) {
+		o.keep = append(o.keep, ids...)
+		o.keepOnly = true
+	}
+}
+
+// EditReplaceAttachments drops all attachments of the message, leaving only the files
+// uploaded with the edit, if any
+func EditReplaceAttachments() EditOption {
+	return func(o *editOptions) {
+		o.keep = nil
+		o.keepOnly = true
+	}
+}
+
+// EditRemoveAttachments drops the listed attachments of the message, by ID, and keeps
+// all others. The message is fetched first to find them.
+func EditRemoveAttachments(ids ...string) EditOption {
+	return func(o *editOptions) {
+		o.remove = append(o.remove, ids...)
+	}
+}
+
+// editURL returns the URL of the edited message with the query parameters the edit needs
+func (o *editOptions) editURL(webhookURL, messageID string, webhookPayload Webhook) (string, error) {
+	requestURL, err := messageURL(webhookURL, messageID)
+	if err != nil {
+		return "", err
+	}
+	if o.threadID != "" {
+		if requestURL, err = withQueryParam(requestURL, "thread_id", o.threadID); err != nil {
+			return "", err
+		}
+	}
+	// Webhooks not owned by an application must opt in to sending components
+	if o.withComponents || len(webhookPayload.Components) > 0 {
+		if requestURL, err = withQueryParam(requestURL, "with_components", "true"); err != nil {
+			return "", err
+		}
+	}
+	return requestURL, nil
+}
+
+// changesAttachments reports whether the edit has to list the attachments the message
+// keeps, which uploading files without listing any would drop
+func (o *editOptions) changesAttachments(webhookPayload Webhook) bool {
+	return o.keepOnly || len(o.remove) > 0 || len(o.files) > 0 && len(webhookPayload.Attachments) == 0
+}
+
+// retainedAttachments returns the attachments the message keeps, fetching the
+// message from requestURL unless only the listed ones are kept
+func (c *Client) retainedAttachments(ctx context.Context, requestURL string, o *editOptions) ([]Attachment, error) {
+	ids := o.keep
+	if !o.keepOnly {
+		var message Message
+		if err := c.do(ctx, http.MethodGet, requestURL, nil, &message); err != nil {
+			return nil, err
+		}
+		ids = message.AttachmentIDs()
+	}
+
+	removed := make(map[string]bool, len(o.remove))
+	for _, id := range o.remove {
+		removed[id] = true
+	}
+	var attachments []Attachment
+	for _, id := range ids {
+		if !removed[id] {
+			attachments = append(attachments, Attachment{ID: id})
+		}
+	}
+	return attachments, nil
+}
+
+// withRetainedAttachments returns a copy of the payload keeping only the attachments.
+// Without any, the empty list is set through Extra, which omitempty would drop.
+func withRetainedAttachments(webhookPayload Webhook, attachments []Attachment) Webhook {
+	webhookPayload.Attachments = attachments
+	if len(attachments) == 0 {
+		extra := make(map[string]json.RawMessage, len(webhookPayload.Extra)+1)
+		for key, value := range webhookPayload.Extra {
+			extra[key] = value
+		}
+		extra["attachments"] = json.RawMessage("[]")
+		webhookPayload.Extra = extra
+	}
+	return webhookPayload
+}
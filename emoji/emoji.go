@@ -0,0 +1,136 @@
+// Package emoji provides named constants for the Unicode emoji commonly used in
+// notifications, so templates and alert code read emoji.Warning instead of a raw
+// multi-byte literal that is hard to review in diffs:
+//
+//	payload.Content = emoji.Siren + " Database is down"
+//
+// The constants are spelled with escapes, so this file stays readable as well.
+package emoji
+
+import (
+	"strings"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+// Status
+const (
+	CheckMark    = "\u2705"       // ✅
+	CrossMark    = "\u274C"       // ❌
+	Warning      = "\u26A0\uFE0F" // ⚠️
+	Info         = "\u2139\uFE0F" // ℹ️
+	Question     = "\u2753"       // ❓
+	Exclamation  = "\u2757"       // ❗
+	NoEntry      = "\u26D4"       // ⛔
+	Stop         = "\U0001F6D1"   // 🛑
+	Siren        = "\U0001F6A8"   // 🚨
+	Bell         = "\U0001F514"   // 🔔
+	Construction = "\U0001F6A7"   // 🚧
+	Eyes         = "\U0001F440"   // 👀
+	ThumbsUp     = "\U0001F44D"   // 👍
+	ThumbsDown   = "\U0001F44E"   // 👎
+	Tada         = "\U0001F389"   // 🎉
+	Sparkles     = "\u2728"       // ✨
+	Fire         = "\U0001F525"   // 🔥
+	Boom         = "\U0001F4A5"   // 💥
+	Zap          = "\u26A1"       // ⚡
+	Skull        = "\U0001F480"   // 💀
+)
+
+// Colored circles, for status indicators
+const (
+	RedCircle    = "\U0001F534" // 🔴
+	OrangeCircle = "\U0001F7E0" // 🟠
+	YellowCircle = "\U0001F7E1" // 🟡
+	GreenCircle  = "\U0001F7E2" // 🟢
+	BlueCircle   = "\U0001F535" // 🔵
+	WhiteCircle  = "\u26AA"     // ⚪
+	BlackCircle  = "\u26AB"     // ⚫
+)
+
+// Operations
+const (
+	Rocket    = "\U0001F680"       // 🚀
+	Package   = "\U0001F4E6"       // 📦
+	Gear      = "\u2699\uFE0F"     // ⚙️
+	Wrench    = "\U0001F527"       // 🔧
+	Hammer    = "\U0001F528"       // 🔨
+	Bug       = "\U0001F41B"       // 🐛
+	Lock      = "\U0001F512"       // 🔒
+	Unlock    = "\U0001F513"       // 🔓
+	Key       = "\U0001F511"       // 🔑
+	Shield    = "\U0001F6E1\uFE0F" // 🛡️
+	Database  = "\U0001F5C4\uFE0F" // 🗄️
+	Cloud     = "\u2601\uFE0F"     // ☁️
+	Computer  = "\U0001F4BB"       // 💻
+	Globe     = "\U0001F310"       // 🌐
+	Robot     = "\U0001F916"       // 🤖
+	Pager     = "\U0001F4DF"       // 📟
+	Recycle   = "\u267B\uFE0F"     // ♻️
+	Repeat    = "\U0001F501"       // 🔁
+	Magnifier = "\U0001F50D"       // 🔍
+	Link      = "\U0001F517"       // 🔗
+	Pin       = "\U0001F4CC"       // 📌
+)
+
+// Reports and time
+const (
+	ChartUp       = "\U0001F4C8"   // 📈
+	ChartDown     = "\U0001F4C9"   // 📉
+	BarChart      = "\U0001F4CA"   // 📊
+	Clipboard     = "\U0001F4CB"   // 📋
+	Memo          = "\U0001F4DD"   // 📝
+	Calendar      = "\U0001F4C5"   // 📅
+	Clock         = "\U0001F552"   // 🕒
+	Hourglass     = "\u23F3"       // ⏳
+	HourglassDone = "\u231B"       // ⌛
+	Stopwatch     = "\u23F1\uFE0F" // ⏱️
+	ArrowUp       = "\u2B06\uFE0F" // ⬆️
+	ArrowDown     = "\u2B07\uFE0F" // ⬇️
+)
+
+// names maps lowercase names to emoji for Lookup
+var names = map[string]string{
+	"checkmark": CheckMark, "crossmark": CrossMark, "warning": Warning, "info": Info,
+	"question": Question, "exclamation": Exclamation, "noentry": NoEntry, "stop": Stop,
+	"siren": Siren, "bell": Bell, "construction": Construction, "eyes": Eyes,
+	"thumbsup": ThumbsUp, "thumbsdown": ThumbsDown, "tada": Tada, "sparkles": Sparkles,
+	"fire": Fire, "boom": Boom, "zap": Zap, "skull": Skull,
+
+	"redcircle": RedCircle, "orangecircle": OrangeCircle, "yellowcircle": YellowCircle,
+	"greencircle": GreenCircle, "bluecircle": BlueCircle, "whitecircle": WhiteCircle,
+	"blackcircle": BlackCircle,
+
+	"rocket": Rocket, "package": Package, "gear": Gear, "wrench": Wrench, "hammer": Hammer,
+	"bug": Bug, "lock": Lock, "unlock": Unlock, "key": Key, "shield": Shield,
+	"database": Database, "cloud": Cloud, "computer": Computer, "globe": Globe,
+	"robot": Robot, "pager": Pager, "recycle": Recycle, "repeat": Repeat,
+	"magnifier": Magnifier, "link": Link, "pin": Pin,
+
+	"chartup": ChartUp, "chartdown": ChartDown, "barchart": BarChart, "clipboard": Clipboard,
+	"memo": Memo, "calendar": Calendar, "clock": Clock, "hourglass": Hourglass,
+	"hourglassdone": HourglassDone, "stopwatch": Stopwatch, "arrowup": ArrowUp, "arrowdown": ArrowDown,
+}
+
+// Lookup returns the emoji named like its constant, ignoring case, dashes and
+// underscores, so "check-mark" and "CheckMark" both find CheckMark
+func Lookup(name string) (string, bool) {
+	name = strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(name))
+	e, ok := names[name]
+	return e, ok
+}
+
+// ForSeverity returns the emoji marking messages of the severity
+func ForSeverity(severity webhook.Severity) string {
+	switch severity {
+	case webhook.SeverityDebug:
+		return Magnifier
+	case webhook.SeverityWarning:
+		return Warning
+	case webhook.SeverityError:
+		return CrossMark
+	case webhook.SeverityCritical:
+		return Siren
+	}
+	return Info
+}
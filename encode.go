@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+)
+
+// maxPooledBufferSize is the capacity above which encoding buffers are dropped instead
+// of pooled, so one huge payload does not pin its memory forever
+const maxPooledBufferSize = 256 << 10
+
+// bufferPool holds the buffers JSON payloads are encoded into
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// pooledBody is a request body encoded into a pooled buffer. The buffer goes back to
+// the pool once both the transport closed the body and the request finished, since
+// the transport may still read the body after the response arrived, and the JSON
+// is used for audits afterwards.
+type pooledBody struct {
+	*bytes.Reader
+	buf  *bytes.Buffer
+	data []byte
+
+	refs      int32
+	closeOnce sync.Once
+}
+
+// encodeJSON encodes the value into a pooled request body, which the caller must release
+func encodeJSON(v any) (*pooledBody, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		bufferPool.Put(buf)
+		return nil, err
+	}
+	// Encode terminates the JSON with a newline, which Marshal does not
+	data := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	return &pooledBody{Reader: bytes.NewReader(data), buf: buf, data: data, refs: 2}, nil
+}
+
+// Close releases the transport's reference to the buffer
+func (b *pooledBody) Close() error {
+	b.closeOnce.Do(b.release)
+	return nil
+}
+
+// release drops a reference to the buffer, returning it to the pool after the last one
+func (b *pooledBody) release() {
+	if atomic.AddInt32(&b.refs, -1) != 0 {
+		return
+	}
+	if b.buf.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(b.buf)
+	}
+}
@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+// benchmarkPayload is a typical alert with an embed of several fields
+func benchmarkPayload() Webhook {
+	embed := Embed{
+		Title:       "Deploy failed",
+		Description: strings.Repeat("The deploy of service api to production failed. ", 10),
+		Color:       0xe74c3c,
+		Timestamp:   "2024-01-02T15:04:05Z",
+		Footer:      Footer{Text: "ci"},
+	}
+	for _, name := range []string{"Service", "Environment", "Commit", "Author", "Duration"} {
+		embed.AddField(Field{Name: name, Value: strings.Repeat("x", 40), Inline: true})
+	}
+	return Webhook{Content: "Deploy failed", Username: "CI", Embeds: []Embed{embed}}
+}
+
+func TestEncodeJSONMatchesMarshal(t *testing.T) {
+	payload := benchmarkPayload()
+	want, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := encodeJSON(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := io.ReadAll(body)
+	if !bytes.Equal(got, want) {
+		t.Errorf("encodeJSON = %s, want %s", got, want)
+	}
+	body.Close()
+	body.release()
+}
+
+// BenchmarkEncodePooled encodes request bodies the way requests do
+func BenchmarkEncodePooled(b *testing.B) {
+	payload := benchmarkPayload()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		body, err := encodeJSON(payload)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.Copy(io.Discard, body); err != nil {
+			b.Fatal(err)
+		}
+		body.Close()
+		body.release()
+	}
+}
+
+// BenchmarkEncodeMarshal encodes request bodies the way requests did before pooling
+func BenchmarkEncodeMarshal(b *testing.B) {
+	payload := benchmarkPayload()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.Copy(io.Discard, bytes.NewBuffer(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package webhook
+
+import "strconv"
+
+// ErrorCode is one of Discord's JSON error codes, which explain failed requests in
+// more detail than their status; see APIError
+type ErrorCode int
+
+// Discord's error codes of failures webhooks run into
+const (
+	ErrorCodeUnknownChannel          ErrorCode = 10003
+	ErrorCodeUnknownMessage          ErrorCode = 10008
+	ErrorCodeUnknownWebhook          ErrorCode = 10015
+	ErrorCodeMaxWebhooks             ErrorCode = 30007
+	ErrorCodeRequestTooLarge         ErrorCode = 40005
+	ErrorCodeTagRequired             ErrorCode = 40067
+	ErrorCodeMissingAccess           ErrorCode = 50001
+	ErrorCodeEmptyMessage            ErrorCode = 50006
+	ErrorCodeMissingPermissions      ErrorCode = 50013
+	ErrorCodeInvalidWebhookToken     ErrorCode = 50027
+	ErrorCodeInvalidFormBody         ErrorCode = 50035
+	ErrorCodeFileTooLarge            ErrorCode = 50045
+	ErrorCodeInvalidJSON             ErrorCode = 50109
+	ErrorCodeThreadLocked            ErrorCode = 160005
+	ErrorCodeForumThreadRequired     ErrorCode = 220001
+	ErrorCodeThreadNameAndID         ErrorCode = 220002
+	ErrorCodeThreadsOnlyInForums     ErrorCode = 220003
+	ErrorCodeWebhookServicesInForums ErrorCode = 220004
+)
+
+// errorCodeDescriptions are Discord's explanations of the error codes
+var errorCodeDescriptions = map[ErrorCode]string{
+	ErrorCodeUnknownChannel:          "unknown channel",
+	ErrorCodeUnknownMessage:          "unknown message",
+	ErrorCodeUnknownWebhook:          "unknown webhook",
+	ErrorCodeMaxWebhooks:             "maximum number of webhooks reached",
+	ErrorCodeRequestTooLarge:         "request entity too large",
+	ErrorCodeTagRequired:             "a tag is required to create a forum post in this channel",
+	ErrorCodeMissingAccess:           "missing access",
+	ErrorCodeEmptyMessage:            "cannot send an empty message",
+	ErrorCodeMissingPermissions:      "missing permissions",
+	ErrorCodeInvalidWebhookToken:     "invalid webhook token",
+	ErrorCodeInvalidFormBody:         "invalid form body",
+	ErrorCodeFileTooLarge:            "file uploaded exceeds the maximum size",
+	ErrorCodeInvalidJSON:             "the request body contains invalid JSON",
+	ErrorCodeThreadLocked:            "thread is locked",
+	ErrorCodeForumThreadRequired:     "webhooks posted to forum channels must have a thread_name or thread_id",
+	ErrorCodeThreadNameAndID:         "webhooks can only have one of thread_name and thread_id",
+	ErrorCodeThreadsOnlyInForums:     "webhooks can only create threads in forum channels",
+	ErrorCodeWebhookServicesInForums: "webhook services cannot be used in forum channels",
+}
+
+// String describes the error code, such as "unknown webhook", or returns the number
+// of codes not listed here
+func (c ErrorCode) String() string {
+	if description, ok := errorCodeDescriptions[c]; ok {
+		return description
+	}
+	return strconv.Itoa(int(c))
+}
@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+)
+
+// Sentinel errors of common failures, for use with errors.Is. The errors returned by
+// requests match them while carrying the details, such as an *APIError.
+var (
+	// ErrRateLimited matches rate limited requests; the *APIError tells how long Discord
+	// asked to wait in RetryAfter
+	ErrRateLimited = errors.New("rate limited by Discord")
+	// ErrWebhookNotFound matches requests to a webhook that was deleted
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrUnauthorized matches requests whose webhook token was revoked or is wrong
+	ErrUnauthorized = errors.New("webhook token rejected")
+	// ErrPayloadTooLarge matches requests whose body, such as its attachments, was too large
+	ErrPayloadTooLarge = errors.New("payload too large")
+	// ErrValidation matches payloads breaking Discord's limits, found by ValidateWebhook
+	// or rejected by Discord
+	ErrValidation = errors.New("invalid webhook payload")
+	// ErrClosed is returned when queueing on a closed client
+	ErrClosed = errors.New("client is closed")
+)
+
+// NetworkError is returned when Discord could not be reached at all
+type NetworkError struct {
+	Err error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("failed to send request to Discord: %v", e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// Temporary reports whether retrying may succeed: DNS failures, timeouts and refused,
+// reset or dropped connections are temporary, while invalid URLs, certificate errors
+// and canceled requests are not
+func (e *NetworkError) Temporary() bool {
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCert x509.CertificateInvalidError
+	switch {
+	case errors.Is(e.Err, context.Canceled),
+		errors.As(e.Err, &unknownAuthority),
+		errors.As(e.Err, &hostnameErr), errors.As(e.Err, &invalidCert):
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	switch {
+	case errors.As(e.Err, &dnsErr):
+		return true
+	case errors.As(e.Err, &netErr) && netErr.Timeout():
+		return true
+	case errors.Is(e.Err, io.EOF), errors.Is(e.Err, io.ErrUnexpectedEOF):
+		return true
+	}
+	for _, errno := range temporaryErrnos {
+		if errors.Is(e.Err, errno) {
+			return true
+		}
+	}
+	return false
+}
+
+// temporaryErrnos are the socket errors of connections that may succeed when retried
+var temporaryErrnos = []syscall.Errno{
+	syscall.ECONNREFUSED,
+	syscall.ECONNRESET,
+	syscall.ECONNABORTED,
+	syscall.EPIPE,
+	syscall.ETIMEDOUT,
+	syscall.EHOSTUNREACH,
+	syscall.ENETUNREACH,
+}
+
+// IsTemporary reports whether the error of a request is temporary, so retrying it
+// later may succeed, such as a rate limit, a server error or a dropped connection
+func IsTemporary(err error) bool {
+	var temporary interface{ Temporary() bool }
+	return errors.As(err, &temporary) && temporary.Temporary()
+}
@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// escalatedColor is the embed color of escalated alerts
+const escalatedColor = 0xED4245
+
+// EscalationPolicy decides when repeated alerts for the same key become louder
+type EscalationPolicy struct {
+	// Threshold is the number of alerts within Window that triggers escalation
+	Threshold int
+	Window    time.Duration
+
+	// RoleID is mentioned in escalated alerts, the only mention they allow
+	RoleID string
+
+	// WebhookURL, when set, receives escalated alerts instead of the regular webhook,
+	// such as an on-call channel
+	WebhookURL string
+
+	// Template, when set, replaces the default escalation, which colors the embeds
+	// red and mentions RoleID. It receives the number of alerts within the window.
+	Template func(webhookPayload Webhook, count int) Webhook
+}
+
+// Escalator sends alerts through a client and escalates keys that keep alerting:
+// once a key reaches Threshold alerts within Window, its alerts use the louder
+// template until the key goes quiet for a whole window or is reset
+type Escalator struct {
+	client    *Client
+	escalated *Client
+	policy    EscalationPolicy
+
+	mu     sync.Mutex
+	alerts map[string][]time.Time
+}
+
+// NewEscalator creates an escalator sending regular alerts through the client.
+// Escalated alerts to a different webhook use the client's options.
+func NewEscalator(client *Client, policy EscalationPolicy) *Escalator {
+	e := &Escalator{
+		client:    client,
+		escalated: client,
+		policy:    policy,
+		alerts:    make(map[string][]time.Time),
+	}
+	if policy.WebhookURL != "" {
+		e.escalated = client.withURL(policy.WebhookURL)
+	}
+	return e
+}
+
+// Send records an alert for the key and sends it, escalated if the key crossed the threshold
+func (e *Escalator) Send(ctx context.Context, key string, webhookPayload Webhook) error {
+	client, payload := e.prepare(key, webhookPayload)
+	return client.Send(ctx, payload)
+}
+
+// Enqueue records an alert for the key and queues it like Client.Enqueue,
+// escalated if the key crossed the threshold
+func (e *Escalator) Enqueue(key string, webhookPayload Webhook, options ...SendOption) (*Delivery, error) {
+	client, payload := e.prepare(key, webhookPayload)
+	return client.Enqueue(payload, append([]SendOption{WithKey(key)}, options...)...)
+}
+
+// Escalated reports whether the key is currently escalated
+func (e *Escalator) Escalated(key string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.policy.Threshold > 0 && len(e.recent(key, time.Now())) >= e.policy.Threshold
+}
+
+// Reset forgets the alerts of a key, such as when the underlying problem was resolved
+func (e *Escalator) Reset(key string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.alerts, key)
+}
+
+// Close flushes the queue of the escalation webhook, if it has its own.
+// The regular client is left to its owner.
+func (e *Escalator) Close(ctx context.Context) error {
+	if e.escalated == e.client {
+		return nil
+	}
+	return e.escalated.Close(ctx)
+}
+
+// prepare records the alert and returns the client and payload to use for it
+func (e *Escalator) prepare(key string, webhookPayload Webhook) (*Client, Webhook) {
+	now := time.Now()
+	e.mu.Lock()
+	alerts := append(e.recent(key, now), now)
+	e.alerts[key] = alerts
+	count := len(alerts)
+	e.mu.Unlock()
+
+	if e.policy.Threshold <= 0 || count < e.policy.Threshold {
+		return e.client, webhookPayload
+	}
+	if e.policy.Template != nil {
+		return e.escalated, e.policy.Template(webhookPayload, count)
+	}
+	return e.escalated, e.escalate(webhookPayload, count)
+}
+
+// recent returns the alerts of the key within the window. The caller holds the lock.
+func (e *Escalator) recent(key string, now time.Time) []time.Time {
+	alerts := e.alerts[key]
+	i := 0
+	for i < len(alerts) && now.Sub(alerts[i]) > e.policy.Window {
+		i++
+	}
+	return alerts[i:]
+}
+
+// escalate applies the default louder template
+func (e *Escalator) escalate(webhookPayload Webhook, count int) Webhook {
+	embeds := make([]Embed, len(webhookPayload.Embeds))
+	for i, embed := range webhookPayload.Embeds {
+		embed.Color = escalatedColor
+		embeds[i] = embed
+	}
+	webhookPayload.Embeds = embeds
+
+	note := fmt.Sprintf("**Escalated:** %d alerts in the last %s", count, formatDuration(e.policy.Window))
+	if e.policy.RoleID != "" {
+		note = "<@&" + e.policy.RoleID + "> " + note
+		webhookPayload.AllowedMentions = MentionRole(e.policy.RoleID)
+	}
+	if webhookPayload.Content != "" {
+		note += "\n" + webhookPayload.Content
+	}
+	webhookPayload.Content = truncateRunes(note, maxContentLength)
+	return webhookPayload
+}
@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"expvar"
+	"regexp"
+	"sync"
+)
+
+// ExpvarMapName is the expvar variable the statistics of clients are published in
+const ExpvarMapName = "discord_webhook"
+
+// expvarClients is the expvar map holding the statistics of clients created with WithExpvar
+var (
+	expvarClients     *expvar.Map
+	expvarClientsOnce sync.Once
+)
+
+// WithExpvar publishes the client's Stats, such as its counters and queue depth, in
+// the expvar map ExpvarMapName under name, so they are served at /debug/vars
+// when the program serves expvar's handler. A later client with the same name replaces
+// the earlier one. Given to a Router or Profiles, each webhook's client is published
+// under name, a slash and the webhook ID or destination name, such as "app/prod-alerts".
+// Clients created for threads, mirrors and spool replays are not published.
+func WithExpvar(name string) ClientOption {
+	return func(c *Client) {
+		c.expvarName = name
+	}
+}
+
+// publishExpvar publishes the statistics of the client under name
+func publishExpvar(name string, c *Client) {
+	expvarClientsOnce.Do(func() {
+		expvarClients = expvar.NewMap(ExpvarMapName)
+	})
+	expvarClients.Set(name, expvar.Func(func() any {
+		return c.Stats()
+	}))
+}
+
+// webhookIDPattern finds the webhook ID in a webhook URL
+var webhookIDPattern = regexp.MustCompile(`/webhooks/(\d+)/`)
+
+// publishExpvarOf publishes a client created for one of several webhooks, such as
+// those of a router, under its WithExpvar name extended by the suffix, so the
+// clients do not replace each other
+func publishExpvarOf(c *Client, suffix string) {
+	if c.expvarName == "" {
+		return
+	}
+	c.expvarName += "/" + suffix
+	publishExpvar(c.expvarName, c)
+}
+
+// webhookIDOf returns the ID of the webhook, or the redacted URL when it has none
+func webhookIDOf(webhookURL string) string {
+	if m := webhookIDPattern.FindStringSubmatch(webhookURL); m != nil {
+		return m[1]
+	}
+	return RedactWebhookURL(webhookURL)
+}
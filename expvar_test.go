@@ -0,0 +1,81 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+// publishedSent returns the Sent counter of the client published under name
+func publishedSent(t *testing.T, name string) int64 {
+	t.Helper()
+	published := expvar.Get(webhook.ExpvarMapName).(*expvar.Map).Get(name)
+	if published == nil {
+		t.Fatalf("no statistics published under %q", name)
+	}
+	var stats webhook.Stats
+	if err := json.Unmarshal([]byte(published.String()), &stats); err != nil {
+		t.Fatal(err)
+	}
+	return stats.Sent
+}
+
+func TestExpvarPublishesEveryRouterWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	router := webhook.NewRouter(webhook.WithExpvar("router-test"))
+	router.Add(webhook.Route{URL: server.URL + "/api/webhooks/1/first", MaxSeverity: webhook.SeverityWarning})
+	router.Add(webhook.Route{URL: server.URL + "/api/webhooks/2/second"})
+	ctx := context.Background()
+	if err := router.Send(ctx, webhook.Webhook{Content: "deploy finished"}, webhook.WithSeverity(webhook.SeverityInfo)); err != nil {
+		t.Fatal(err)
+	}
+	if err := router.Send(ctx, webhook.Webhook{Content: "database down"}, webhook.WithSeverity(webhook.SeverityCritical)); err != nil {
+		t.Fatal(err)
+	}
+
+	if sent := publishedSent(t, "router-test/1"); sent != 1 {
+		t.Errorf("first webhook sent %d, want 1", sent)
+	}
+	if sent := publishedSent(t, "router-test/2"); sent != 2 {
+		t.Errorf("second webhook sent %d, want 2", sent)
+	}
+}
+
+func TestExpvarPublishesEveryDestination(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	profiles := webhook.NewProfiles(webhook.WithExpvar("profiles-test"))
+	for _, name := range []string{"prod-alerts", "staging-alerts"} {
+		if err := profiles.Add(name, webhook.Destination{URL: server.URL + "/api/webhooks/1/" + name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+	for _, name := range []string{"prod-alerts", "prod-alerts", "staging-alerts"} {
+		if err := profiles.Send(ctx, name, webhook.Webhook{Content: "hello"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if sent := publishedSent(t, "profiles-test/prod-alerts"); sent != 2 {
+		t.Errorf("prod-alerts sent %d, want 2", sent)
+	}
+	if sent := publishedSent(t, "profiles-test/staging-alerts"); sent != 1 {
+		t.Errorf("staging-alerts sent %d, want 1", sent)
+	}
+	if expvar.Get(webhook.ExpvarMapName).(*expvar.Map).Get("profiles-test") != nil {
+		t.Error("a destination was published without its name")
+	}
+}
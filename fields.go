@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"sort"
+	"strings"
+)
+
+// FieldOrder is a named ordering of embed fields; see Embed.OrderFields
+type FieldOrder int
+
+const (
+	// FieldOrderInsertion keeps the fields in the order they were added
+	FieldOrderInsertion FieldOrder = iota
+	// FieldOrderAlphabetical sorts the fields by name, ignoring case
+	FieldOrderAlphabetical
+	// FieldOrderInlineFirst groups the inline fields before the others, so they fill
+	// complete rows, and otherwise keeps the insertion order
+	FieldOrderInlineFirst
+)
+
+// SortFields sorts the fields of the embed with less. Fields that compare equal keep their order.
+func (e *Embed) SortFields(less func(a, b Field) bool) {
+	sort.SliceStable(e.Fields, func(i, j int) bool {
+		return less(e.Fields[i], e.Fields[j])
+	})
+}
+
+// OrderFields sorts the fields of the embed in a named order
+func (e *Embed) OrderFields(order FieldOrder) {
+	switch order {
+	case FieldOrderAlphabetical:
+		e.SortFields(fieldNameLess)
+	case FieldOrderInlineFirst:
+		e.SortFields(func(a, b Field) bool { return a.Inline && !b.Inline })
+	}
+}
+
+// fieldNameLess orders fields by name, ignoring case unless the names only differ in case
+func fieldNameLess(a, b Field) bool {
+	if lowerA, lowerB := strings.ToLower(a.Name), strings.ToLower(b.Name); lowerA != lowerB {
+		return lowerA < lowerB
+	}
+	return a.Name < b.Name
+}
+
+// FieldsFromMap creates one field per entry of the map, sorted by name, since
+// maps are iterated in random order
+func FieldsFromMap(values map[string]string, inline bool) []Field {
+	fields := make([]Field, 0, len(values))
+	for name, value := range values {
+		fields = append(fields, CreateField(name, value, inline))
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return fieldNameLess(fields[i], fields[j])
+	})
+	return fields
+}
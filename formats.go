@@ -0,0 +1,29 @@
+package webhook
+
+import (
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// fileFormats maps lowercase file extensions to converters into JSON; see RegisterFileFormat
+var fileFormats sync.Map
+
+// RegisterFileFormat makes LoadProfiles and the payload definitions loaded by
+// templates.ParseFS read files with the extension, such as ".yaml", by converting
+// them into JSON with toJSON. The module itself only reads JSON; importing the
+// yamlwebhook module registers YAML.
+func RegisterFileFormat(ext string, toJSON func(data []byte) ([]byte, error)) {
+	fileFormats.Store(strings.ToLower(ext), toJSON)
+}
+
+// FileToJSON converts the data of a file named name into JSON, by the format
+// registered for its extension. It reports false when no format is registered.
+func FileToJSON(name string, data []byte) ([]byte, bool, error) {
+	toJSON, ok := fileFormats.Load(strings.ToLower(filepath.Ext(name)))
+	if !ok {
+		return nil, false, nil
+	}
+	converted, err := toJSON.(func([]byte) ([]byte, error))(data)
+	return converted, true, err
+}
@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// Hash returns a hex-encoded SHA-256 hash of what the payload sends, such as for
+// idempotency keys or to detect changed messages. Payloads that are equal have the
+// same hash: nil and empty lists hash alike, and so do the IDs of allowed mentions
+// in any order.
+func (w Webhook) Hash() string {
+	data, err := json.Marshal(w.normalized())
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// normalized returns a copy of the payload with lists whose order does not matter sorted
+func (w Webhook) normalized() Webhook {
+	if w.AllowedMentions != nil {
+		mentions := AllowedMentions{
+			Parse: sortedCopy(w.AllowedMentions.Parse),
+			Roles: sortedCopy(w.AllowedMentions.Roles),
+			Users: sortedCopy(w.AllowedMentions.Users),
+		}
+		w.AllowedMentions = &mentions
+	}
+	return w
+}
+
+// sortedCopy returns a sorted copy of the strings
+func sortedCopy(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
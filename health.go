@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+)
+
+// HealthStatus classifies the result of a health check
+type HealthStatus int
+
+const (
+	// HealthOK means the webhook exists and its token is valid
+	HealthOK HealthStatus = iota
+	// HealthRevoked means Discord rejected the token, such as after it was regenerated
+	HealthRevoked
+	// HealthNotFound means the webhook was deleted, or the URL never pointed at one
+	HealthNotFound
+	// HealthUnreachable means Discord could not be reached
+	HealthUnreachable
+	// HealthUnknown covers every other failure, such as rate limits and server errors
+	HealthUnknown
+)
+
+// String returns the name of the status
+func (s HealthStatus) String() string {
+	switch s {
+	case HealthOK:
+		return "ok"
+	case HealthRevoked:
+		return "revoked"
+	case HealthNotFound:
+		return "not found"
+	case HealthUnreachable:
+		return "unreachable"
+	}
+	return "unknown"
+}
+
+// Healthcheck fetches the webhook and classifies the result, so services can fail
+// fast at startup or alert operators when a configured webhook is dead. The error
+// is nil only for HealthOK, which also clears the mark set when the webhook was reported invalid.
+func (c *Client) Healthcheck(ctx context.Context) (HealthStatus, error) {
+	_, err := c.Info(ctx)
+	if err == nil {
+		atomic.StoreInt32(&c.invalid, 0)
+	}
+	return healthStatus(err), err
+}
+
+// healthStatus classifies the error of a request
+func healthStatus(err error) HealthStatus {
+	if err == nil {
+		return HealthOK
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return HealthRevoked
+		case http.StatusNotFound:
+			return HealthNotFound
+		}
+		return HealthUnknown
+	}
+	var netErr *NetworkError
+	if errors.As(err, &netErr) {
+		return HealthUnreachable
+	}
+	return HealthUnknown
+}
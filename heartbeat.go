@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	heartbeatColor        = 0x57F287
+	heartbeatStoppedColor = 0x95A5A6
+)
+
+// Heartbeat posts a liveness message on an interval, so a channel shows when a
+// service was last seen without a separate bot. Discord renders the "last seen"
+// timestamp relative to the reader ("3 minutes ago"), so a stale message is
+// noticeable even between beats.
+type Heartbeat struct {
+	client   *Client
+	name     string
+	interval time.Duration
+	started  time.Time
+
+	// Version is shown in the message when it is set
+	Version string
+
+	// Edit keeps a single message up to date instead of posting a new one on
+	// every beat. It defaults to true.
+	Edit bool
+
+	// Fields, when set, is called on every beat for extra fields such as queue depth
+	Fields func() []Field
+
+	// OnError is called when a beat could not be delivered
+	OnError func(err error)
+
+	messageID string
+}
+
+// NewHeartbeat creates a heartbeat for the named service, beating every interval
+func NewHeartbeat(client *Client, name string, interval time.Duration) *Heartbeat {
+	return &Heartbeat{
+		client:   client,
+		name:     name,
+		interval: interval,
+		started:  time.Now(),
+		Edit:     true,
+	}
+}
+
+// Run beats immediately and then on every interval until ctx is cancelled. When
+// editing, the message is marked as stopped on the way out.
+func (h *Heartbeat) Run(ctx context.Context) error {
+	h.beat(ctx, false)
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if h.Edit && h.messageID != "" {
+				stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				h.beat(stopCtx, true)
+				cancel()
+			}
+			return ctx.Err()
+		case <-ticker.C:
+			h.beat(ctx, false)
+		}
+	}
+}
+
+// beat sends or edits the liveness message
+func (h *Heartbeat) beat(ctx context.Context, stopped bool) {
+	payload := h.payload(time.Now(), stopped)
+
+	if h.Edit && h.messageID != "" {
+		err := h.client.Edit(ctx, h.messageID, payload)
+		if err == nil {
+			return
+		}
+		if !isNotFound(err) {
+			h.reportError(err)
+			return
+		}
+		// The message was deleted, post a new one
+		h.messageID = ""
+	}
+
+	if !h.Edit {
+		if err := h.client.Send(ctx, payload); err != nil {
+			h.reportError(err)
+		}
+		return
+	}
+	message, err := h.client.execute(ctx, payload, true)
+	if err != nil {
+		h.reportError(err)
+		return
+	}
+	h.messageID = message.ID
+}
+
+// payload builds the liveness message
+func (h *Heartbeat) payload(now time.Time, stopped bool) Webhook {
+	title := h.name + " is running"
+	color := heartbeatColor
+	if stopped {
+		title = h.name + " stopped"
+		color = heartbeatStoppedColor
+	}
+
+	embed := Embed{
+		Title:       title,
+		Description: fmt.Sprintf("Last seen <t:%d:R>", now.Unix()),
+		Color:       color,
+		Timestamp:   now.UTC().Format(time.RFC3339),
+		Fields: []Field{
+			{Name: "Uptime", Value: formatDuration(now.Sub(h.started)), Inline: true},
+		},
+	}
+	if h.Version != "" {
+		embed.Fields = append(embed.Fields, Field{Name: "Version", Value: h.Version, Inline: true})
+	}
+	if host, err := os.Hostname(); err == nil {
+		embed.Fields = append(embed.Fields, Field{Name: "Host", Value: host, Inline: true})
+	}
+	if h.Fields != nil {
+		embed.Fields = append(embed.Fields, h.Fields()...)
+	}
+	return Webhook{Embeds: []Embed{embed}}
+}
+
+// reportError passes a failed beat to the error handler, if there is one
+func (h *Heartbeat) reportError(err error) {
+	if h.OnError != nil {
+		h.OnError(err)
+	}
+}
+
+// formatDuration renders a duration as days, hours and minutes, such as "3d 4h 12m",
+// or in seconds when it is shorter than a minute
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+	d = d.Truncate(time.Minute)
+	days := d / (24 * time.Hour)
+	hours := d % (24 * time.Hour) / time.Hour
+	minutes := d % time.Hour / time.Minute
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	if minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	return strings.Join(parts, " ")
+}
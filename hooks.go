@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// WithPayloadTransform calls transform with every payload the client sends or edits,
+// after defaults and the client's own changes such as sanitizing were applied, so it
+// has the last word on the payload. Options given several times run in order.
+func WithPayloadTransform(transform func(webhookPayload *Webhook)) ClientOption {
+	return func(c *Client) {
+		c.transforms = append(c.transforms, transform)
+	}
+}
+
+// WithJSONHook calls hook with the JSON of every payload the client sends or edits, and
+// sends the JSON it returns instead, such as to add experimental fields Discord accepts
+// but Webhook does not model yet. An error fails the request. Options given several
+// times run in order.
+func WithJSONHook(hook func(data []byte) ([]byte, error)) ClientOption {
+	return func(c *Client) {
+		c.jsonHooks = append(c.jsonHooks, hook)
+	}
+}
+
+// applyTransforms runs the payload transforms on the payload
+func (c *Client) applyTransforms(webhookPayload Webhook) Webhook {
+	for _, transform := range c.transforms {
+		transform(&webhookPayload)
+	}
+	return webhookPayload
+}
+
+// applyJSONHooks runs the JSON hooks on the encoded payload
+func (c *Client) applyJSONHooks(data []byte) ([]byte, error) {
+	for _, hook := range c.jsonHooks {
+		var err error
+		if data, err = hook(data); err != nil {
+			return nil, fmt.Errorf("JSON hook failed: %w", err)
+		}
+		if !json.Valid(data) {
+			return nil, fmt.Errorf("JSON hook returned invalid JSON")
+		}
+	}
+	return data, nil
+}
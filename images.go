@@ -0,0 +1,196 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultImageQuality = 85
+	// minImageDimension stops shrinking images that still do not fit into MaxBytes
+	minImageDimension = 64
+)
+
+// ImageOptions configures the downscaling of attached images; see WithImageDownscaling
+type ImageOptions struct {
+	// MaxWidth and MaxHeight bound the dimensions of images, which are scaled down
+	// to fit while keeping their aspect ratio. Zero means no bound.
+	MaxWidth  int
+	MaxHeight int
+
+	// MaxBytes bounds the size of images, which are re-encoded and scaled down
+	// further until they fit. Zero means no bound.
+	MaxBytes int64
+
+	// Quality is the JPEG quality of re-encoded JPEG images, from 1 to 100. It defaults to 85.
+	Quality int
+}
+
+// WithImageDownscaling scales down and re-encodes PNG and JPEG files uploaded by SendFiles
+// that exceed the dimensions or size of the options, so screenshots are never rejected for
+// being too large. Images keep their format; other files are sent as they are.
+func WithImageDownscaling(options ImageOptions) ClientOption {
+	return func(c *Client) {
+		if options.Quality <= 0 || options.Quality > 100 {
+			options.Quality = defaultImageQuality
+		}
+		c.images = &options
+	}
+}
+
+// downscaleImages returns the files with images replaced by ones that are downscaled when read
+func (c *Client) downscaleImages(files []File) []File {
+	if c.images == nil {
+		return files
+	}
+	scaled := make([]File, len(files))
+	for i, file := range files {
+		if format := imageFormat(file.Name); format != "" {
+			file = c.images.downscaleFile(file, format)
+		}
+		scaled[i] = file
+	}
+	return scaled
+}
+
+// imageFormat returns the format of the image files that can be downscaled, judging by the extension
+func imageFormat(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".png":
+		return "png"
+	case ".jpg", ".jpeg":
+		return "jpeg"
+	}
+	return ""
+}
+
+// downscaleFile returns the file downscaled when it is opened. Images are read into memory for it.
+func (o *ImageOptions) downscaleFile(file File, format string) File {
+	open := file.Open
+	file.Size = 0
+	file.Open = func() (io.ReadCloser, error) {
+		content, err := open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(content)
+		content.Close()
+		if err != nil {
+			return nil, err
+		}
+		if data, err = o.downscale(data, format); err != nil {
+			return nil, fmt.Errorf("failed to downscale image %s: %v", file.Name, err)
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	return file
+}
+
+// downscale returns the image scaled down to fit the options, or data itself when it fits already
+func (o *ImageOptions) downscale(data []byte, format string) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		// Not decodable as an image after all, so leave it to Discord
+		return data, nil
+	}
+	width, height := fitDimensions(config.Width, config.Height, o.MaxWidth, o.MaxHeight)
+	if width == config.Width && height == config.Height && (o.MaxBytes <= 0 || int64(len(data)) <= o.MaxBytes) {
+		return data, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	for {
+		encoded, err := o.encode(scaleImage(img, width, height), format)
+		if err != nil {
+			return nil, err
+		}
+		if o.MaxBytes <= 0 || int64(len(encoded)) <= o.MaxBytes || width <= minImageDimension || height <= minImageDimension {
+			return encoded, nil
+		}
+		width, height = width*3/4, height*3/4
+	}
+}
+
+// encode encodes the image in the format
+func (o *ImageOptions) encode(img image.Image, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: o.Quality})
+	} else {
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, img)
+	}
+	return buf.Bytes(), err
+}
+
+// fitDimensions scales width and height down to fit the bounds, keeping the aspect ratio
+func fitDimensions(width, height, maxWidth, maxHeight int) (int, int) {
+	if maxWidth > 0 && width > maxWidth {
+		height = max1(height * maxWidth / width)
+		width = maxWidth
+	}
+	if maxHeight > 0 && height > maxHeight {
+		width = max1(width * maxHeight / height)
+		height = maxHeight
+	}
+	return width, height
+}
+
+// max1 returns n, but at least 1
+func max1(n int) int {
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// scaleImage scales the image to the dimensions by averaging the source pixels
+// covered by each target pixel, which keeps text in screenshots legible
+func scaleImage(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() == width && bounds.Dy() == height {
+		return img
+	}
+	src := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*bounds.Dy()/height, (y+1)*bounds.Dy()/height
+		if y1 == y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0, x1 := x*bounds.Dx()/width, (x+1)*bounds.Dx()/width
+			if x1 == x0 {
+				x1 = x0 + 1
+			}
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride+x0*4 : sy*src.Stride+x1*4]
+				for i := 0; i < len(row); i += 4 {
+					sum[0] += int(row[i])
+					sum[1] += int(row[i+1])
+					sum[2] += int(row[i+2])
+					sum[3] += int(row[i+3])
+				}
+			}
+			n := (y1 - y0) * (x1 - x0)
+			i := y*dst.Stride + x*4
+			for c := 0; c < 4; c++ {
+				dst.Pix[i+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+	return dst
+}
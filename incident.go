@@ -0,0 +1,206 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IncidentStatus is the stage of an incident
+type IncidentStatus string
+
+const (
+	IncidentInvestigating IncidentStatus = "Investigating"
+	IncidentIdentified    IncidentStatus = "Identified"
+	IncidentMonitoring    IncidentStatus = "Monitoring"
+	IncidentResolved      IncidentStatus = "Resolved"
+)
+
+// incidentColors are the embed colors of the incident statuses
+var incidentColors = map[IncidentStatus]int{
+	IncidentInvestigating: 0xED4245,
+	IncidentIdentified:    0xE67E22,
+	IncidentMonitoring:    0xFEE75C,
+	IncidentResolved:      0x57F287,
+}
+
+// incidentUpdate is an entry of the incident timeline
+type incidentUpdate struct {
+	at     time.Time
+	status IncidentStatus
+	note   string
+}
+
+// Incident keeps a single message up to date over the lifecycle of an incident:
+// Open posts it, every Update edits it with the new status and a timeline entry,
+// and Resolve marks it resolved with the total duration
+type Incident struct {
+	client *Client
+	title  string
+
+	mu        sync.Mutex
+	messageID string
+	status    IncidentStatus
+	opened    time.Time
+	resolved  time.Time
+	timeline  []incidentUpdate
+}
+
+// NewIncident creates an incident with the title, posted through the client once it is opened
+func NewIncident(client *Client, title string) *Incident {
+	return &Incident{client: client, title: title}
+}
+
+// Open posts the incident message with the investigating status
+func (i *Incident) Open(ctx context.Context, note string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if !i.opened.IsZero() {
+		return fmt.Errorf("incident %q is already open", i.title)
+	}
+
+	now := time.Now()
+	i.opened = now
+	i.status = IncidentInvestigating
+	i.timeline = append(i.timeline, incidentUpdate{at: now, status: IncidentInvestigating, note: note})
+	if err := i.publish(ctx); err != nil {
+		// The incident was not posted, so Open can be tried again
+		i.opened, i.status, i.timeline = time.Time{}, "", i.timeline[:len(i.timeline)-1]
+		return err
+	}
+	return nil
+}
+
+// Update sets the status of an open incident and adds the note to its timeline
+func (i *Incident) Update(ctx context.Context, status IncidentStatus, note string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.opened.IsZero() {
+		return fmt.Errorf("incident %q is not open", i.title)
+	}
+	if !i.resolved.IsZero() {
+		return fmt.Errorf("incident %q is already resolved", i.title)
+	}
+
+	now := time.Now()
+	previous := i.status
+	if status == IncidentResolved {
+		i.resolved = now
+	}
+	i.status = status
+	i.timeline = append(i.timeline, incidentUpdate{at: now, status: status, note: note})
+	if err := i.publish(ctx); err != nil {
+		// The message still shows the previous state, and retrying must not repeat the note
+		i.resolved, i.status, i.timeline = time.Time{}, previous, i.timeline[:len(i.timeline)-1]
+		return err
+	}
+	return nil
+}
+
+// Resolve marks the incident resolved, adding the note to its timeline
+func (i *Incident) Resolve(ctx context.Context, note string) error {
+	return i.Update(ctx, IncidentResolved, note)
+}
+
+// Status returns the current status, or an empty status before the incident is opened
+func (i *Incident) Status() IncidentStatus {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.status
+}
+
+// Duration returns how long the incident has been open, or was open once resolved
+func (i *Incident) Duration() time.Duration {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.duration(time.Now())
+}
+
+// MessageID returns the ID of the incident message, or an empty string before it is posted
+func (i *Incident) MessageID() string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.messageID
+}
+
+// duration returns the open time up to now. The caller holds the lock.
+func (i *Incident) duration(now time.Time) time.Duration {
+	if i.opened.IsZero() {
+		return 0
+	}
+	if !i.resolved.IsZero() {
+		now = i.resolved
+	}
+	return now.Sub(i.opened)
+}
+
+// publish posts the incident message, or edits it once it exists. A deleted
+// message is posted again. The caller holds the lock.
+func (i *Incident) publish(ctx context.Context) error {
+	payload := i.payload(time.Now())
+	if i.messageID != "" {
+		err := i.client.Edit(ctx, i.messageID, payload)
+		if !isNotFound(err) {
+			return err
+		}
+		i.messageID = ""
+	}
+
+	message, err := i.client.execute(ctx, payload, true)
+	if err != nil {
+		return err
+	}
+	i.messageID = message.ID
+	return nil
+}
+
+// payload builds the incident message. The caller holds the lock.
+func (i *Incident) payload(now time.Time) Webhook {
+	duration := formatDuration(i.duration(now))
+	if i.resolved.IsZero() {
+		duration += " so far"
+	}
+
+	embed := Embed{
+		Title: truncateRunes(i.title, maxEmbedTitleLength),
+		Color: incidentColors[i.status],
+		Fields: []Field{
+			{Name: "Status", Value: string(i.status), Inline: true},
+			{Name: "Opened", Value: fmt.Sprintf("<t:%d:f>", i.opened.Unix()), Inline: true},
+			{Name: "Duration", Value: duration, Inline: true},
+			{Name: "Timeline", Value: i.timelineText()},
+		},
+		Timestamp: i.opened.UTC().Format(time.RFC3339),
+	}
+	return Webhook{Embeds: []Embed{embed}}
+}
+
+// timelineText renders the timeline, newest last, dropping the oldest entries that
+// do not fit in a field. The caller holds the lock.
+func (i *Incident) timelineText() string {
+	var lines []string
+	length := 0
+	for n := len(i.timeline) - 1; n >= 0; n-- {
+		update := i.timeline[n]
+		line := fmt.Sprintf("<t:%d:t> **%s**", update.at.Unix(), update.status)
+		if note := strings.TrimSpace(update.note); note != "" {
+			line += " " + note
+		}
+		line = truncateRunes(line, maxFieldValueLength/2)
+
+		// Leave room for the note about dropped entries
+		if length+len(line)+1 > maxFieldValueLength-40 {
+			lines = append(lines, "-# "+pluralize(n+1, "earlier update"))
+			break
+		}
+		length += len(line) + 1
+		lines = append(lines, line)
+	}
+
+	for l, r := 0, len(lines)-1; l < r; l, r = l+1, r-1 {
+		lines[l], lines[r] = lines[r], lines[l]
+	}
+	return strings.Join(lines, "\n")
+}
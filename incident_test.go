@@ -0,0 +1,78 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/webhooktest"
+)
+
+func TestIncidentOpenCanBeRetried(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	incident := webhook.NewIncident(webhook.NewClient(server.URL), "API outage")
+	ctx := context.Background()
+
+	server.Respond(webhooktest.Error(http.StatusInternalServerError, 0, "Internal Server Error"))
+	if err := incident.Open(ctx, "error rate above 5%"); err == nil {
+		t.Fatal("expected the first Open to fail")
+	}
+	if incident.Status() != "" || incident.MessageID() != "" {
+		t.Errorf("failed Open left status %q and message %q", incident.Status(), incident.MessageID())
+	}
+
+	if err := incident.Open(ctx, "error rate above 5%"); err != nil {
+		t.Fatalf("retried Open: %v", err)
+	}
+	if incident.Status() != webhook.IncidentInvestigating || incident.MessageID() == "" {
+		t.Errorf("status %q, message %q after Open", incident.Status(), incident.MessageID())
+	}
+	if timeline := lastTimeline(t, server); strings.Count(timeline, "error rate above 5%") != 1 {
+		t.Errorf("timeline repeats the note of the failed Open:\n%s", timeline)
+	}
+}
+
+func TestIncidentUpdateRollsBackOnFailure(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	incident := webhook.NewIncident(webhook.NewClient(server.URL), "API outage")
+	ctx := context.Background()
+	if err := incident.Open(ctx, "investigating"); err != nil {
+		t.Fatal(err)
+	}
+
+	server.Respond(webhooktest.Error(http.StatusInternalServerError, 0, "Internal Server Error"))
+	if err := incident.Resolve(ctx, "fixed"); err == nil {
+		t.Fatal("expected the first Resolve to fail")
+	}
+	if incident.Status() != webhook.IncidentInvestigating {
+		t.Errorf("status = %q after a failed Resolve, want %q", incident.Status(), webhook.IncidentInvestigating)
+	}
+
+	if err := incident.Resolve(ctx, "fixed"); err != nil {
+		t.Fatalf("retried Resolve: %v", err)
+	}
+	if timeline := lastTimeline(t, server); strings.Count(timeline, "fixed") != 1 {
+		t.Errorf("timeline repeats the note of the failed Resolve:\n%s", timeline)
+	}
+}
+
+// lastTimeline returns the timeline field of the last incident message received
+func lastTimeline(t *testing.T, server *webhooktest.Server) string {
+	t.Helper()
+	payloads := server.Payloads()
+	last := payloads[len(payloads)-1]
+	if len(last.Embeds) == 0 {
+		t.Fatal("incident message has no embed")
+	}
+	for _, field := range last.Embeds[0].Fields {
+		if field.Name == "Timeline" {
+			return field.Value
+		}
+	}
+	t.Fatal("incident message has no timeline")
+	return ""
+}
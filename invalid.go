@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+// maxErrorBodySize bounds how much of an error response is read
+const maxErrorBodySize = 64 << 10
+
+// WithOnWebhookInvalid sets a function called once when Discord reports that the
+// webhook was revoked or deleted, so the application can rotate to a replacement
+// URL. It is called synchronously from the failing request and must not block on
+// the client. Requests failing this way are never retried.
+func WithOnWebhookInvalid(handler func(webhookURL string, err error)) ClientOption {
+	return func(c *Client) {
+		c.onInvalid = handler
+	}
+}
+
+// Invalid reports whether Discord has reported the webhook as revoked or deleted.
+// A successful Healthcheck clears the mark.
+func (c *Client) Invalid() bool {
+	return atomic.LoadInt32(&c.invalid) == 1
+}
+
+// markInvalid marks the webhook invalid, calling the handler the first time
+func (c *Client) markInvalid(webhookURL string, err error) {
+	if atomic.CompareAndSwapInt32(&c.invalid, 0, 1) && c.onInvalid != nil {
+		c.onInvalid(webhookURL, err)
+	}
+}
+
+// invalidWebhook reports whether a failed request shows the webhook itself is gone,
+// as opposed to a message of it: Discord answers 401 for revoked tokens and 404 with
+// code 10015 for deleted webhooks. A 404 without a code only counts for requests
+// to the webhook itself.
+func invalidWebhook(err *APIError, requestURL string) bool {
+	switch {
+	case err.StatusCode == http.StatusUnauthorized:
+		return true
+	case err.StatusCode != http.StatusNotFound:
+		return false
+	case err.Code == ErrorCodeUnknownWebhook:
+		return true
+	case err.Code != 0:
+		return false
+	}
+	u, parseErr := url.Parse(requestURL)
+	return parseErr == nil && !strings.Contains(u.Path, "/messages/")
+}
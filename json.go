@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// LoadWebhookJSON reads a JSON payload, such as one stored in a file, and validates it
+// against Discord's limits. Validation failures are returned as a *ValidationError.
+func LoadWebhookJSON(r io.Reader) (Webhook, error) {
+	var webhook Webhook
+	if err := json.NewDecoder(r).Decode(&webhook); err != nil {
+		return Webhook{}, fmt.Errorf("failed to decode JSON payload: %v", err)
+	}
+	if err := ValidateWebhook(webhook); err != nil {
+		return Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// WriteJSON writes the payload as indented JSON, suitable for storing in files and reviewing in diffs
+func (w *Webhook) WriteJSON(out io.Writer) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	// Keep markdown and Discord timestamps such as <t:1700000000:R> readable
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(w); err != nil {
+		return fmt.Errorf("failed to write JSON payload: %v", err)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the embed, leaving out the footer, image, thumbnail and author
+// when they are not set instead of sending them as empty objects
+func (e Embed) MarshalJSON() ([]byte, error) {
+	type plainEmbed Embed
+	aux := struct {
+		plainEmbed
+		Footer    *Footer    `json:"footer,omitempty"`
+		Image     *Image     `json:"image,omitempty"`
+		Thumbnail *Thumbnail `json:"thumbnail,omitempty"`
+		Author    *Author    `json:"author,omitempty"`
+	}{plainEmbed: plainEmbed(e)}
+
+	if e.Footer != (Footer{}) {
+		aux.Footer = &e.Footer
+	}
+	if e.Image != (Image{}) {
+		aux.Image = &e.Image
+	}
+	if e.Thumbnail != (Thumbnail{}) {
+		aux.Thumbnail = &e.Thumbnail
+	}
+	if e.Author != (Author{}) {
+		aux.Author = &e.Author
+	}
+	return marshalWithExtra(aux, e.Extra)
+}
+
+// UnmarshalJSON decodes the embed, keeping fields it does not model in Extra
+func (e *Embed) UnmarshalJSON(data []byte) error {
+	type plainEmbed Embed
+	var decoded plainEmbed
+	extra, err := unmarshalWithExtra(data, &decoded)
+	if err != nil {
+		return err
+	}
+	*e = Embed(decoded)
+	e.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the payload with its Extra fields
+func (w Webhook) MarshalJSON() ([]byte, error) {
+	type plainWebhook Webhook
+	return marshalWithExtra(plainWebhook(w), w.Extra)
+}
+
+// UnmarshalJSON decodes the payload, keeping fields it does not model in Extra
+func (w *Webhook) UnmarshalJSON(data []byte) error {
+	type plainWebhook Webhook
+	var decoded plainWebhook
+	extra, err := unmarshalWithExtra(data, &decoded)
+	if err != nil {
+		return err
+	}
+	*w = Webhook(decoded)
+	w.Extra = extra
+	return nil
+}
+
+// marshalWithExtra encodes v, a struct, and merges the extra fields into the object.
+// HTML is not escaped here, since the encoder calling MarshalJSON escapes it when
+// configured to, and WriteJSON keeps it readable.
+func marshalWithExtra(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	data := bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+	if len(extra) == 0 {
+		return data, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for key, value := range extra {
+		if !json.Valid(value) {
+			return nil, fmt.Errorf("extra field %q is not valid JSON", key)
+		}
+		fields[key] = value
+	}
+	buf.Reset()
+	if err := encoder.Encode(fields); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// unmarshalWithExtra decodes data into v, a pointer to a struct, and returns the fields
+// of the object v has no field for, or nil when there are none
+func unmarshalWithExtra(data []byte, v any) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	known := jsonFieldNames(reflect.TypeOf(v).Elem())
+	var extra map[string]json.RawMessage
+	for key, value := range fields {
+		if known[strings.ToLower(key)] {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[key] = value
+	}
+	return extra, nil
+}
+
+// fieldNames caches the JSON field names of struct types
+var fieldNames sync.Map
+
+// jsonFieldNames returns the lowercase JSON names of the fields of a struct type, since
+// encoding/json matches them ignoring case while decoding
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	if names, ok := fieldNames.Load(t); ok {
+		return names.(map[string]bool)
+	}
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	fieldNames.Store(t, names)
+	return names
+}
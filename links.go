@@ -0,0 +1,197 @@
+package webhook
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// bareURLPattern matches URLs in text; the surroundings decide whether they are bare
+	bareURLPattern = regexp.MustCompile(`https?://[^\s<>]+`)
+	// codePattern matches code blocks and inline code, where URLs are not links
+	codePattern = regexp.MustCompile("(?s)```.*?```|`[^`\n]+`")
+	// maskedLinkPattern matches masked links such as [text](url) and [text](<url>)
+	maskedLinkPattern = regexp.MustCompile(`\[([^\[\]\n]*)\]\(\s*(?:<([^<>\n]*)>|([^()<>\s]*(?:\([^()\s]*\)[^()<>\s]*)*))\s*\)`)
+)
+
+// unsafeLinkSchemes are URL schemes that run code or embed content when a link is opened
+var unsafeLinkSchemes = []string{"javascript", "vbscript", "data", "file"}
+
+// WithoutLinkPreviews wraps the bare URLs in the content of every message the client
+// sends or edits in angle brackets, so Discord does not unfurl them; see WrapURLsNoPreview
+func WithoutLinkPreviews() ClientOption {
+	return func(c *Client) {
+		c.noLinkPreviews = true
+	}
+}
+
+// WrapURLsNoPreview wraps the bare URLs in the text in angle brackets, which makes Discord
+// link them without a preview. Unlike FlagSuppressEmbeds, it leaves the message's own
+// embeds alone. URLs already in angle brackets, targets of masked links and URLs in code
+// are left unchanged, as is trailing punctuation such as the period ending a sentence.
+func WrapURLsNoPreview(s string) string {
+	code := codePattern.FindAllStringIndex(s, -1)
+	var b strings.Builder
+	last := 0
+	for _, match := range bareURLPattern.FindAllStringIndex(s, -1) {
+		start, end := match[0], match[0]+len(trimURLPunctuation(s[match[0]:match[1]]))
+		if inRanges(code, start) || start > 0 && s[start-1] == '<' || strings.HasSuffix(s[:start], "](") {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString("<" + s[start:end] + ">")
+		last = end
+	}
+	if last == 0 {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// trimURLPunctuation removes punctuation that ends the sentence around a URL rather than
+// the URL itself, keeping closing parentheses that have an opening one in the URL
+func trimURLPunctuation(url string) string {
+	for len(url) > 0 {
+		switch last := url[len(url)-1]; {
+		case strings.IndexByte(".,;:!?'\"*_~|", last) >= 0:
+			url = url[:len(url)-1]
+		case last == ')' && strings.Count(url, "(") < strings.Count(url, ")"):
+			url = url[:len(url)-1]
+		default:
+			return url
+		}
+	}
+	return url
+}
+
+// inRanges reports whether the offset falls into one of the [start, end) ranges
+func inRanges(ranges [][]int, offset int) bool {
+	for _, r := range ranges {
+		if offset >= r[0] && offset < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// WithLinkChecking makes the client refuse messages with unsafe masked links and report
+// deceptive ones before sending or editing; see CheckLinks. Use it when forwarding
+// content from sources that are not fully trusted. onWarning, which may be nil, is
+// called with the payload and its warnings, and the message is sent anyway.
+func WithLinkChecking(onWarning func(webhookPayload Webhook, warnings []Violation)) ClientOption {
+	return func(c *Client) {
+		c.checkLinks = true
+		c.onLinkWarning = onWarning
+	}
+}
+
+// CheckLinks checks the masked links ([text](url)) in the content, embed descriptions
+// and field values, the text Discord renders links in. Links to javascript:, vbscript:,
+// data: and file: URLs are reported as a *ValidationError. Links whose text shows a
+// different site than they lead to, such as [https://bank.example](https://evil.example),
+// are returned as warnings, since they are sometimes legitimate.
+func CheckLinks(webhookPayload Webhook) ([]Violation, error) {
+	var errs, warnings validator
+	check := func(path, text string) {
+		code := codePattern.FindAllStringIndex(text, -1)
+		for _, match := range maskedLinkPattern.FindAllStringSubmatchIndex(text, -1) {
+			if inRanges(code, match[0]) {
+				continue
+			}
+			// The target is either in angle brackets or bare
+			display, target := text[match[2]:match[3]], ""
+			if match[4] >= 0 {
+				target = text[match[4]:match[5]]
+			} else {
+				target = text[match[6]:match[7]]
+			}
+			if scheme, unsafe := unsafeLinkScheme(target); unsafe {
+				errs.addf(path, "link %q uses the unsafe URL scheme %q", display, scheme)
+			} else if shown, actual, deceptive := deceptiveLink(display, target); deceptive {
+				warnings.addf(path, "link text shows %s but the link leads to %s", shown, actual)
+			}
+		}
+	}
+
+	check("content", webhookPayload.Content)
+	for i, embed := range webhookPayload.Embeds {
+		check(fmt.Sprintf("embeds[%d].description", i), embed.Description)
+		for j, field := range embed.Fields {
+			check(fmt.Sprintf("embeds[%d].fields[%d].value", i, j), field.Value)
+		}
+	}
+	if len(errs.violations) > 0 {
+		return warnings.violations, &ValidationError{Violations: errs.violations}
+	}
+	return warnings.violations, nil
+}
+
+// checkPayloadLinks applies link checking when the client is configured for it
+func (c *Client) checkPayloadLinks(webhookPayload Webhook) error {
+	if !c.checkLinks {
+		return nil
+	}
+	warnings, err := CheckLinks(webhookPayload)
+	if err != nil {
+		return err
+	}
+	if len(warnings) > 0 && c.onLinkWarning != nil {
+		c.onLinkWarning(webhookPayload, warnings)
+	}
+	return nil
+}
+
+// unsafeLinkScheme returns the scheme of the link target when it is unsafe. Whitespace and
+// control characters are ignored, as browsers do, so "java\tscript:" is caught too.
+func unsafeLinkScheme(target string) (string, bool) {
+	cleaned := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || unicode.IsControl(r) || invisibleRune(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, target)
+	if unescaped, err := url.PathUnescape(cleaned); err == nil {
+		cleaned = strings.ToLower(unescaped)
+	}
+	for _, scheme := range unsafeLinkSchemes {
+		if strings.HasPrefix(cleaned, scheme+":") {
+			return scheme, true
+		}
+	}
+	return "", false
+}
+
+// deceptiveLink reports whether the link text looks like a URL or domain of another host
+// than the target's, and returns both hosts
+func deceptiveLink(display, target string) (string, string, bool) {
+	shown := linkHost(display)
+	actual := linkHost(target)
+	if shown == "" || actual == "" || shown == actual {
+		return "", "", false
+	}
+	return shown, actual, true
+}
+
+// linkHost returns the host of text that looks like a URL or a domain such as
+// "example.com/path", without a leading "www.", or "" for other text
+func linkHost(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.ContainsAny(text, " \t\n") {
+		return ""
+	}
+	if !strings.Contains(text, "://") {
+		if !strings.Contains(text, ".") {
+			return ""
+		}
+		text = "https://" + text
+	}
+	u, err := url.Parse(text)
+	if err != nil || u.Hostname() == "" || !strings.Contains(u.Hostname(), ".") {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
@@ -0,0 +1,35 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/webhooktest"
+)
+
+func TestLinkCheckingCoversFilesAndEdits(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	client := webhook.NewClient(server.URL, webhook.WithLinkChecking(nil))
+	ctx := context.Background()
+	unsafe := webhook.Webhook{Content: "[click here](javascript:alert(1))"}
+	file := webhook.FileFromBytes("report.txt", []byte("report"))
+
+	if _, err := client.SendFiles(ctx, unsafe, file); !errors.Is(err, webhook.ErrValidation) {
+		t.Errorf("SendFiles error = %v, want ErrValidation", err)
+	}
+	if err := client.Edit(ctx, "1", unsafe, webhook.EditAddFiles(file)); !errors.Is(err, webhook.ErrValidation) {
+		t.Errorf("Edit error = %v, want ErrValidation", err)
+	}
+	if err := client.Send(ctx, unsafe); !errors.Is(err, webhook.ErrValidation) {
+		t.Errorf("Send error = %v, want ErrValidation", err)
+	}
+	server.AssertRequestCount(t, 0)
+
+	if _, err := client.SendFiles(ctx, webhook.Webhook{Content: "[docs](https://example.com)"}, file); err != nil {
+		t.Errorf("SendFiles with a safe link: %v", err)
+	}
+	server.AssertRequestCount(t, 1)
+}
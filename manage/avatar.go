@@ -0,0 +1,43 @@
+package manage
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+// maxAvatarSize bounds the size of avatar images
+const maxAvatarSize = 10 << 20
+
+// avatarTypes are the image types Discord accepts as avatars
+var avatarTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+// avatarDataURI reads the avatar image and encodes it as the data URI Discord expects,
+// detecting its type from the content
+func avatarDataURI(avatar webhook.File) (string, error) {
+	content, err := avatar.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to open avatar %s: %v", avatar.Name, err)
+	}
+	defer content.Close()
+	data, err := io.ReadAll(io.LimitReader(content, maxAvatarSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read avatar %s: %v", avatar.Name, err)
+	}
+	if len(data) > maxAvatarSize {
+		return "", fmt.Errorf("avatar %s exceeds the limit of %d MB", avatar.Name, maxAvatarSize>>20)
+	}
+	contentType := http.DetectContentType(data)
+	if !avatarTypes[contentType] {
+		return "", fmt.Errorf("avatar %s is %s, not a PNG, JPEG, GIF or WebP image", avatar.Name, contentType)
+	}
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
@@ -0,0 +1,369 @@
+// Package manage creates and manages Discord webhooks with a bot token, such as to
+// provision a webhook for every channel of a deployment. Creating webhooks needs the
+// Manage Webhooks permission in the channel.
+package manage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+const (
+	// DefaultBaseURL is the base URL of Discord's API
+	DefaultBaseURL = "https://discord.com/api"
+	// apiVersion is the version of the bot endpoints
+	apiVersion = "v10"
+	// maxErrorBodySize bounds how much of an error response is read
+	maxErrorBodySize = 64 << 10
+	// maxNameLength is Discord's limit for webhook names
+	maxNameLength = 80
+	// typeIncoming is the type of webhooks that can be sent to with a token
+	typeIncoming = 1
+)
+
+// Manager calls Discord's API with a bot token to manage webhooks
+type Manager struct {
+	token      string
+	httpClient *http.Client
+	baseURL    string
+
+	// clientOptions configure the clients of created webhooks
+	clientOptions []webhook.ClientOption
+
+	// tags caches the forum tags of channels; see ResolveTags
+	tags tagCache
+}
+
+// Option configures a Manager
+type Option func(*Manager)
+
+// WithHTTPClient sets the HTTP client used for requests. It defaults to http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(m *Manager) {
+		m.httpClient = httpClient
+	}
+}
+
+// WithBaseURL sets the base URL of Discord's API, such as for a mock server in tests.
+// It defaults to DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(m *Manager) {
+		m.baseURL = baseURL
+	}
+}
+
+// WithClientOptions sets the options of the clients returned for created webhooks
+func WithClientOptions(options ...webhook.ClientOption) Option {
+	return func(m *Manager) {
+		m.clientOptions = append(m.clientOptions, options...)
+	}
+}
+
+// New creates a manager authenticating as the bot with the token
+func New(botToken string, options ...Option) *Manager {
+	m := &Manager{
+		token:      botToken,
+		httpClient: http.DefaultClient,
+		baseURL:    DefaultBaseURL,
+	}
+	for _, option := range options {
+		option(m)
+	}
+	return m
+}
+
+// CreateParams describes a webhook to create
+type CreateParams struct {
+	// Name is the name of the webhook, at most 80 characters. Discord rejects names
+	// containing "clyde" or "discord".
+	Name string
+	// Avatar is the image of the webhook, such as webhook.FileFromPath("bot.png"). PNG,
+	// JPEG, GIF and WebP images are supported; leave it zero for the default avatar.
+	Avatar webhook.File
+	// Reason is recorded in the guild's audit log
+	Reason string
+}
+
+// ModifyParams describes the changes to a webhook; zero fields are left unchanged
+type ModifyParams struct {
+	Name   string
+	Avatar webhook.File
+	// RemoveAvatar resets the webhook to the default avatar
+	RemoveAvatar bool
+	// ChannelID moves the webhook to another channel of the guild
+	ChannelID string
+	// Reason is recorded in the guild's audit log
+	Reason string
+}
+
+// Webhook is a webhook as listed by Discord
+type Webhook struct {
+	ID string `json:"id"`
+	// Type is 1 for incoming webhooks, 2 for channel followers and 3 for applications
+	Type      int    `json:"type"`
+	GuildID   string `json:"guild_id,omitempty"`
+	ChannelID string `json:"channel_id"`
+	Name      string `json:"name"`
+	Avatar    string `json:"avatar,omitempty"`
+	// Token is set for incoming webhooks only
+	Token         string `json:"token,omitempty"`
+	ApplicationID string `json:"application_id,omitempty"`
+	// User is who created the webhook
+	User *User `json:"user,omitempty"`
+}
+
+// User is the creator of a webhook
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// Create creates a webhook in the channel and returns a client sending to it. The
+// client's URL contains the webhook's token; store it to send to the webhook later.
+func (m *Manager) Create(ctx context.Context, channelID string, params CreateParams) (*webhook.Client, error) {
+	created, err := m.create(ctx, channelID, params)
+	if err != nil {
+		return nil, err
+	}
+	return m.Client(created)
+}
+
+// create creates a webhook in the channel
+func (m *Manager) create(ctx context.Context, channelID string, params CreateParams) (Webhook, error) {
+	if channelID == "" {
+		return Webhook{}, fmt.Errorf("channel ID cannot be empty")
+	}
+	if params.Name == "" {
+		return Webhook{}, fmt.Errorf("webhook name cannot be empty")
+	}
+	if n := utf8.RuneCountInString(params.Name); n > maxNameLength {
+		return Webhook{}, fmt.Errorf("the length of the webhook name cannot exceed %d characters (your length: %d)", maxNameLength, n)
+	}
+
+	body := map[string]any{"name": params.Name}
+	if params.Avatar.Open != nil {
+		avatar, err := avatarDataURI(params.Avatar)
+		if err != nil {
+			return Webhook{}, err
+		}
+		body["avatar"] = avatar
+	}
+	var created Webhook
+	path := "/channels/" + url.PathEscape(channelID) + "/webhooks"
+	if err := m.do(ctx, http.MethodPost, path, params.Reason, body, &created); err != nil {
+		return Webhook{}, err
+	}
+	return created, nil
+}
+
+// Client returns a client sending to the webhook, which must be an incoming webhook
+// with its token
+func (m *Manager) Client(w Webhook) (*webhook.Client, error) {
+	if w.ID == "" || w.Token == "" {
+		return nil, fmt.Errorf("webhook %s has no token: only incoming webhooks can be sent to", w.ID)
+	}
+	return webhook.NewClient(m.webhookURL(w.ID, w.Token), m.clientOptions...), nil
+}
+
+// ListChannel lists the webhooks of the channel
+func (m *Manager) ListChannel(ctx context.Context, channelID string) ([]Webhook, error) {
+	if channelID == "" {
+		return nil, fmt.Errorf("channel ID cannot be empty")
+	}
+	var webhooks []Webhook
+	if err := m.do(ctx, http.MethodGet, "/channels/"+url.PathEscape(channelID)+"/webhooks", "", nil, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// ListGuild lists the webhooks of all channels of the guild
+func (m *Manager) ListGuild(ctx context.Context, guildID string) ([]Webhook, error) {
+	if guildID == "" {
+		return nil, fmt.Errorf("guild ID cannot be empty")
+	}
+	var webhooks []Webhook
+	if err := m.do(ctx, http.MethodGet, "/guilds/"+url.PathEscape(guildID)+"/webhooks", "", nil, &webhooks); err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// Modify changes the name, avatar or channel of the webhook and returns it as changed
+func (m *Manager) Modify(ctx context.Context, webhookID string, params ModifyParams) (Webhook, error) {
+	if webhookID == "" {
+		return Webhook{}, fmt.Errorf("webhook ID cannot be empty")
+	}
+	body := make(map[string]any)
+	if params.Name != "" {
+		if n := utf8.RuneCountInString(params.Name); n > maxNameLength {
+			return Webhook{}, fmt.Errorf("the length of the webhook name cannot exceed %d characters (your length: %d)", maxNameLength, n)
+		}
+		body["name"] = params.Name
+	}
+	switch {
+	case params.Avatar.Open != nil:
+		avatar, err := avatarDataURI(params.Avatar)
+		if err != nil {
+			return Webhook{}, err
+		}
+		body["avatar"] = avatar
+	case params.RemoveAvatar:
+		body["avatar"] = nil
+	}
+	if params.ChannelID != "" {
+		body["channel_id"] = params.ChannelID
+	}
+
+	var modified Webhook
+	if err := m.do(ctx, http.MethodPatch, "/webhooks/"+url.PathEscape(webhookID), params.Reason, body, &modified); err != nil {
+		return Webhook{}, err
+	}
+	return modified, nil
+}
+
+// Delete deletes the webhook, recording the reason in the guild's audit log
+func (m *Manager) Delete(ctx context.Context, webhookID, reason string) error {
+	if webhookID == "" {
+		return fmt.Errorf("webhook ID cannot be empty")
+	}
+	return m.do(ctx, http.MethodDelete, "/webhooks/"+url.PathEscape(webhookID), reason, nil, nil)
+}
+
+// Prune deletes the incoming webhooks of the channel whose name starts with prefix,
+// except the ones with the IDs to keep, and returns the deleted webhooks. Webhooks of
+// other integrations, such as channel followers, are left alone. The prefix cannot be
+// empty, which would delete every webhook of the channel. It stops at the first
+// failed deletion.
+func (m *Manager) Prune(ctx context.Context, channelID, prefix string, keep ...string) ([]Webhook, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("prefix cannot be empty")
+	}
+	webhooks, err := m.ListChannel(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	kept := make(map[string]bool, len(keep))
+	for _, id := range keep {
+		kept[id] = true
+	}
+	var deleted []Webhook
+	for _, w := range webhooks {
+		if kept[w.ID] || w.Type != typeIncoming || !strings.HasPrefix(w.Name, prefix) {
+			continue
+		}
+		if err := m.Delete(ctx, w.ID, "pruned stale webhook"); err != nil {
+			return deleted, fmt.Errorf("failed to delete webhook %s (%s): %w", w.ID, w.Name, err)
+		}
+		deleted = append(deleted, w)
+	}
+	return deleted, nil
+}
+
+// Ensure makes the channel have exactly one incoming webhook named params.Name and
+// returns a client sending to it: the oldest one is kept and the others are deleted,
+// and one is created if there is none. Running it again changes nothing, which suits
+// reconciling infrastructure.
+func (m *Manager) Ensure(ctx context.Context, channelID string, params CreateParams) (*webhook.Client, error) {
+	webhooks, err := m.ListChannel(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+	var matching []Webhook
+	for _, w := range webhooks {
+		if w.Name == params.Name && w.Type == typeIncoming && w.Token != "" {
+			matching = append(matching, w)
+		}
+	}
+	if len(matching) == 0 {
+		return m.Create(ctx, channelID, params)
+	}
+
+	// Snowflake IDs grow over time, so the shortest and then smallest ID is the oldest
+	sort.Slice(matching, func(i, j int) bool {
+		a, b := matching[i].ID, matching[j].ID
+		return len(a) < len(b) || len(a) == len(b) && a < b
+	})
+	for _, duplicate := range matching[1:] {
+		if err := m.Delete(ctx, duplicate.ID, "duplicate of webhook "+matching[0].ID); err != nil {
+			return nil, fmt.Errorf("failed to delete duplicate webhook %s: %w", duplicate.ID, err)
+		}
+	}
+	return m.Client(matching[0])
+}
+
+// webhookURL returns the URL messages are sent to
+func (m *Manager) webhookURL(id, token string) string {
+	return m.baseURL + "/webhooks/" + url.PathEscape(id) + "/" + url.PathEscape(token)
+}
+
+// do sends an authenticated request to the bot endpoint at path and decodes the
+// response into out when it is not nil
+func (m *Manager) do(ctx context.Context, method, path, reason string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON payload: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, m.baseURL+"/"+apiVersion+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bot "+m.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if reason != "" {
+		req.Header.Set("X-Audit-Log-Reason", url.PathEscape(reason))
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return &webhook.NetworkError{Err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return readAPIError(resp)
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode Discord response: %v", err)
+		}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// readAPIError reads Discord's explanation of a failed request
+func readAPIError(resp *http.Response) error {
+	apiErr := &webhook.APIError{StatusCode: resp.StatusCode}
+	if seconds, err := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64); err == nil && seconds > 0 {
+		apiErr.RetryAfter = time.Duration(seconds * float64(time.Second))
+	}
+	var body struct {
+		Code    webhook.ErrorCode `json:"code"`
+		Message string            `json:"message"`
+		Errors  json.RawMessage   `json:"errors"`
+	}
+	if json.NewDecoder(io.LimitReader(resp.Body, maxErrorBodySize)).Decode(&body) == nil {
+		apiErr.Code, apiErr.Message, apiErr.Errors = body.Code, body.Message, body.Errors
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodySize))
+	return apiErr
+}
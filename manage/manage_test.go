@@ -0,0 +1,55 @@
+package manage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPrune(t *testing.T) {
+	var mu sync.Mutex
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v10/channels/42/webhooks":
+			json.NewEncoder(w).Encode([]Webhook{
+				{ID: "1", Type: typeIncoming, Name: "deploy-old", Token: "a"},
+				{ID: "2", Type: typeIncoming, Name: "deploy-current", Token: "b"},
+				{ID: "3", Type: typeIncoming, Name: "alerts", Token: "c"},
+				{ID: "4", Type: 2, Name: "deploy-announcements"},
+				{ID: "5", Type: 3, Name: "deploy-bot", ApplicationID: "99"},
+			})
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/v10/webhooks/"):
+			mu.Lock()
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/v10/webhooks/"))
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	manager := New("bot-token", WithBaseURL(server.URL))
+
+	if _, err := manager.Prune(context.Background(), "42", ""); err == nil {
+		t.Error("Prune with an empty prefix succeeded, want an error")
+	}
+	if len(deleted) != 0 {
+		t.Fatalf("Prune with an empty prefix deleted %v", deleted)
+	}
+
+	pruned, err := manager.Prune(context.Background(), "42", "deploy-", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pruned) != 1 || pruned[0].ID != "1" {
+		t.Errorf("pruned %+v, want only webhook 1", pruned)
+	}
+	if len(deleted) != 1 || deleted[0] != "1" {
+		t.Errorf("deleted %v, want [1]", deleted)
+	}
+}
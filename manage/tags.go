@@ -0,0 +1,103 @@
+package manage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Tag is a tag of a forum or media channel
+type Tag struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Moderated tags can only be applied by members with the Manage Threads permission
+	Moderated bool `json:"moderated,omitempty"`
+}
+
+// tagCache holds the tags of the channels resolved so far
+type tagCache struct {
+	mu       sync.Mutex
+	channels map[string][]Tag
+}
+
+// get returns the cached tags of the channel
+func (c *tagCache) get(channelID string) ([]Tag, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tags, ok := c.channels[channelID]
+	return tags, ok
+}
+
+// set caches the tags of the channel
+func (c *tagCache) set(channelID string, tags []Tag) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.channels == nil {
+		c.channels = make(map[string][]Tag)
+	}
+	c.channels[channelID] = tags
+}
+
+// Tags returns the available tags of the forum or media channel
+func (m *Manager) Tags(ctx context.Context, channelID string) ([]Tag, error) {
+	if channelID == "" {
+		return nil, fmt.Errorf("channel ID cannot be empty")
+	}
+	var channel struct {
+		AvailableTags []Tag `json:"available_tags"`
+	}
+	if err := m.do(ctx, http.MethodGet, "/channels/"+url.PathEscape(channelID), "", nil, &channel); err != nil {
+		return nil, err
+	}
+	m.tags.set(channelID, channel.AvailableTags)
+	return channel.AvailableTags, nil
+}
+
+// ResolveTags returns the IDs of the channel's tags with the names, matched ignoring
+// case, for setting webhook.Webhook.AppliedTags from configuration. Names that already
+// are tag IDs are kept. The tags are fetched once per channel and refetched when a
+// name is not found, so tags created since are picked up.
+func (m *Manager) ResolveTags(ctx context.Context, channelID string, names ...string) ([]string, error) {
+	tags, cached := m.tags.get(channelID)
+	if !cached {
+		var err error
+		if tags, err = m.Tags(ctx, channelID); err != nil {
+			return nil, err
+		}
+	}
+	ids, missing := matchTags(tags, names)
+	if len(missing) > 0 && cached {
+		var err error
+		if tags, err = m.Tags(ctx, channelID); err != nil {
+			return nil, err
+		}
+		ids, missing = matchTags(tags, names)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("channel %s has no tags named %s", channelID, strings.Join(missing, ", "))
+	}
+	return ids, nil
+}
+
+// matchTags returns the IDs of the tags with the names and the names without a tag
+func matchTags(tags []Tag, names []string) ([]string, []string) {
+	var ids, missing []string
+	for _, name := range names {
+		id := ""
+		for _, tag := range tags {
+			if tag.ID == name || strings.EqualFold(tag.Name, name) {
+				id = tag.ID
+				break
+			}
+		}
+		if id == "" {
+			missing = append(missing, fmt.Sprintf("%q", name))
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, missing
+}
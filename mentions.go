@@ -0,0 +1,31 @@
+package webhook
+
+// Mention types for AllowedMentions.Parse
+const (
+	MentionRoles    = "roles"
+	MentionUsers    = "users"
+	MentionEveryone = "everyone"
+)
+
+// AllowedMentions controls which mentions in the content notify anyone.
+// Without it, Discord notifies every user, role and @everyone mentioned.
+type AllowedMentions struct {
+	// Parse lists the mention types notified wherever they appear in the content.
+	// Leaving it empty notifies only the listed roles and users.
+	Parse []string `json:"parse,omitempty"`
+
+	// Roles and Users list the IDs notified when mentioned, in addition to Parse.
+	// They must not repeat a type listed in Parse.
+	Roles []string `json:"roles,omitempty"`
+	Users []string `json:"users,omitempty"`
+}
+
+// NoMentions returns allowed mentions that notify nobody, keeping mentions in the content inert
+func NoMentions() *AllowedMentions {
+	return &AllowedMentions{}
+}
+
+// MentionRole returns allowed mentions that only notify the given roles
+func MentionRole(roleIDs ...string) *AllowedMentions {
+	return &AllowedMentions{Roles: roleIDs}
+}
@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// WebhookInfo represents the webhook object returned by Discord.
+// The token is deliberately not decoded so it does not end up in logs by accident.
+type WebhookInfo struct {
+	ID            string `json:"id"`
+	Type          int    `json:"type"`
+	GuildID       string `json:"guild_id,omitempty"`
+	ChannelID     string `json:"channel_id"`
+	Name          string `json:"name"`
+	Avatar        string `json:"avatar,omitempty"`
+	ApplicationID string `json:"application_id,omitempty"`
+}
+
+// Message is a message sent by the webhook, as returned by Discord
+type Message struct {
+	ID              string       `json:"id"`
+	ChannelID       string       `json:"channel_id"`
+	Content         string       `json:"content"`
+	Embeds          []Embed      `json:"embeds,omitempty"`
+	Attachments     []Attachment `json:"attachments,omitempty"`
+	Timestamp       string       `json:"timestamp"`
+	EditedTimestamp string       `json:"edited_timestamp,omitempty"`
+}
+
+// AttachmentIDs returns the IDs of the message's attachments, such as for keeping
+// some of them with EditKeepAttachments
+func (m Message) AttachmentIDs() []string {
+	ids := make([]string, len(m.Attachments))
+	for i, attachment := range m.Attachments {
+		ids[i] = attachment.ID
+	}
+	return ids
+}
+
+// EditMessage edits a message previously sent by the webhook; see Client.Edit for the options
+func EditMessage(webhookURL, messageID string, webhookPayload Webhook, options ...EditOption) error {
+	return NewClient(webhookURL).Edit(context.Background(), messageID, webhookPayload, options...)
+}
+
+// DeleteMessage deletes a message previously sent by the webhook
+func DeleteMessage(webhookURL, messageID string) error {
+	return NewClient(webhookURL).Delete(context.Background(), messageID)
+}
+
+// GetWebhookInfo fetches the webhook's name, channel and guild
+func GetWebhookInfo(webhookURL string) (WebhookInfo, error) {
+	return NewClient(webhookURL).Info(context.Background())
+}
+
+// messageURL returns the URL of a message sent by the webhook, keeping query parameters such as thread_id
+func messageURL(webhookURL, messageID string) (string, error) {
+	if messageID == "" {
+		return "", fmt.Errorf("message ID cannot be empty")
+	}
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid webhook URL: %v", err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/messages/" + url.PathEscape(messageID)
+	u.RawPath = ""
+	return u.String(), nil
+}
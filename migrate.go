@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ConvertMessage converts a message built with another Discord webhook library into a Webhook.
+// It accepts any value that marshals to Discord's webhook JSON format, such as
+// gtuk/discordwebhook's Message or disgo's discord.WebhookMessageCreate, so existing
+// call sites can be migrated one at a time without this package depending on those libraries.
+// Fields this package does not model are kept in Webhook.Extra and Embed.Extra, so they
+// are sent along with the others.
+func ConvertMessage(message any) (Webhook, error) {
+	switch m := message.(type) {
+	case Webhook:
+		return m, nil
+	case *Webhook:
+		return *m, nil
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return Webhook{}, fmt.Errorf("failed to marshal message: %v", err)
+	}
+
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return Webhook{}, fmt.Errorf("message does not marshal to a JSON object: %v", err)
+	}
+
+	// Some libraries encode embed colors as strings, which Discord also accepts
+	if embeds, ok := raw["embeds"].([]any); ok {
+		for _, e := range embeds {
+			embed, ok := e.(map[string]any)
+			if !ok {
+				continue
+			}
+			if color, ok := embed["color"].(string); ok {
+				value, err := parseColorString(color)
+				if err != nil {
+					return Webhook{}, err
+				}
+				embed["color"] = value
+			}
+		}
+	}
+
+	data, err = json.Marshal(raw)
+	if err != nil {
+		return Webhook{}, fmt.Errorf("failed to marshal message: %v", err)
+	}
+
+	var webhook Webhook
+	if err := json.Unmarshal(data, &webhook); err != nil {
+		return Webhook{}, fmt.Errorf("failed to convert message: %v", err)
+	}
+	return webhook, nil
+}
+
+// parseColorString parses a color given either as a decimal or a '#'-prefixed hex string
+func parseColorString(color string) (int, error) {
+	if strings.HasPrefix(color, "#") {
+		return hexToColorInt(color)
+	}
+	value, err := strconv.Atoi(color)
+	if err != nil {
+		return 0, fmt.Errorf("invalid embed color %q", color)
+	}
+	if value > maxDecimalRGBValue || value < minDecimalRGBValue {
+		return 0, fmt.Errorf("you can only use numbers from 0 to 16777215")
+	}
+	return value, nil
+}
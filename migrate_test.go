@@ -0,0 +1,21 @@
+package webhook
+
+import "testing"
+
+func TestConvertMessageKeepsUnknownFields(t *testing.T) {
+	message := map[string]any{
+		"content":       "hello",
+		"enforce_nonce": true,
+		"embeds":        []any{map[string]any{"title": "Deploy", "color": "#ff0000", "type": "rich"}},
+	}
+	w, err := ConvertMessage(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.Content != "hello" || string(w.Extra["enforce_nonce"]) != "true" {
+		t.Errorf("content %q, extra %v", w.Content, w.Extra)
+	}
+	if len(w.Embeds) != 1 || w.Embeds[0].Color != 0xff0000 || string(w.Embeds[0].Extra["type"]) != `"rich"` {
+		t.Errorf("embeds = %+v", w.Embeds)
+	}
+}
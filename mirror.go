@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"context"
+	"sync"
+)
+
+// Mirror delivers copies of a client's messages to a second webhook, such as an
+// audit or leadership channel
+type Mirror struct {
+	URL string
+
+	// Filter selects the messages to mirror; without it, every message is mirrored
+	Filter func(webhookPayload Webhook) bool
+
+	// Transform, when set, adapts the copy, such as to remove mentions or add a source
+	// note. The copy shares its embeds with the original, so replace slices instead of
+	// changing their elements.
+	Transform func(webhookPayload Webhook) Webhook
+
+	// OnError is called when a copy could not be delivered. Failed copies never fail the original send.
+	OnError func(err error)
+}
+
+// mirrorTarget is a mirror with the client delivering to it
+type mirrorTarget struct {
+	Mirror
+	once   sync.Once
+	client *Client
+}
+
+// WithMirror sends a copy of every new message the client posts, including queued
+// ones, to the mirror webhook once the message was posted. Messages that failed or
+// were spooled are not mirrored, and a message retried by the queue is mirrored once.
+// Edits and deletes are not mirrored.
+func WithMirror(mirror Mirror) ClientOption {
+	return func(c *Client) {
+		c.mirrors = append(c.mirrors, &mirrorTarget{Mirror: mirror})
+	}
+}
+
+// mirror starts delivering the copies of the payload and returns a function waiting for them
+func (c *Client) mirror(ctx context.Context, webhookPayload Webhook) (wait func()) {
+	var wg sync.WaitGroup
+	for _, target := range c.mirrors {
+		if target.Filter != nil && !target.Filter(webhookPayload) {
+			continue
+		}
+		payload := webhookPayload
+		if target.Transform != nil {
+			payload = target.Transform(payload)
+		}
+
+		target.once.Do(func() {
+			// The copy must not be mirrored again
+			target.client = c.withURL(target.URL)
+			target.client.mirrors = nil
+		})
+		wg.Add(1)
+		go func(target *mirrorTarget) {
+			defer wg.Done()
+			if err := target.client.Send(ctx, payload); err != nil && target.OnError != nil {
+				target.OnError(err)
+			}
+		}(target)
+	}
+	return wg.Wait
+}
@@ -0,0 +1,63 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/webhooktest"
+)
+
+func TestMirrorOncePerRetriedMessage(t *testing.T) {
+	primary := webhooktest.NewServer()
+	defer primary.Close()
+	mirror := webhooktest.NewServer()
+	defer mirror.Close()
+	failure := webhooktest.Error(http.StatusInternalServerError, 0, "500: Internal Server Error")
+	primary.Respond(failure, failure)
+
+	client := webhook.NewClient(primary.URL, fastBackoff, webhook.WithMaxRetries(3), webhook.WithMirror(webhook.Mirror{URL: mirror.URL}))
+	delivery, err := client.Enqueue(webhook.Webhook{Content: "database down"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := result(t, delivery); result.Err != nil || result.Attempts != 3 {
+		t.Fatalf("result = %+v, want success after 3 attempts", result)
+	}
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	primary.AssertRequestCount(t, 3)
+	mirror.AssertRequestCount(t, 1)
+	mirror.AssertLastPayload(t, webhook.Webhook{Content: "database down"})
+}
+
+func TestMirrorSkipsFailedMessages(t *testing.T) {
+	primary := webhooktest.NewServer()
+	defer primary.Close()
+	mirror := webhooktest.NewServer()
+	defer mirror.Close()
+	primary.Respond(webhooktest.Error(http.StatusBadRequest, webhook.ErrorCodeInvalidFormBody, "Invalid Form Body"))
+
+	client := webhook.NewClient(primary.URL, webhook.WithMirror(webhook.Mirror{URL: mirror.URL}))
+	if err := client.Send(context.Background(), webhook.Webhook{Content: "database down"}); err == nil {
+		t.Fatal("Send succeeded, want the 400 error")
+	}
+	mirror.AssertRequestCount(t, 0)
+}
+
+func TestMirrorSkipsSpooledMessages(t *testing.T) {
+	primary := webhooktest.NewServer()
+	defer primary.Close()
+	mirror := webhooktest.NewServer()
+	defer mirror.Close()
+
+	client := webhook.NewClient(primary.URL, webhook.WithOfflineSpool(t.TempDir()), webhook.WithMirror(webhook.Mirror{URL: mirror.URL}))
+	if err := client.Send(context.Background(), webhook.Webhook{Content: "database down"}); err != nil {
+		t.Fatal(err)
+	}
+	primary.AssertRequestCount(t, 0)
+	mirror.AssertRequestCount(t, 0)
+}
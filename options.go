@@ -0,0 +1,68 @@
+package webhook
+
+import "time"
+
+// sendOptions are the per-message settings of queued and routed messages
+type sendOptions struct {
+	key      string
+	severity Severity
+	labels   []string
+	ttl      time.Duration
+
+	priority    Priority
+	hasPriority bool
+}
+
+// SendOption configures a single queued or routed message
+type SendOption func(*sendOptions)
+
+// WithKey sets a key identifying what the message is about, such as "disk-space:host1".
+// Throttling is applied per key.
+func WithKey(key string) SendOption {
+	return func(o *sendOptions) {
+		o.key = key
+	}
+}
+
+// WithSeverity sets the severity of the message, which quiet hours and routes of a Router act on
+func WithSeverity(severity Severity) SendOption {
+	return func(o *sendOptions) {
+		o.severity = severity
+	}
+}
+
+// WithLabels tags the message with labels such as "security", which routes of a Router can match
+func WithLabels(labels ...string) SendOption {
+	return func(o *sendOptions) {
+		o.labels = append(o.labels, labels...)
+	}
+}
+
+// Priority orders queued messages: higher priorities are delivered first, such as
+// outage alerts queued behind routine reports while the webhook is rate limited
+type Priority int
+
+const (
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 1
+)
+
+// WithPriority sets the priority of a queued message. Without it, critical messages
+// have PriorityHigh and all others PriorityNormal.
+func WithPriority(priority Priority) SendOption {
+	return func(o *sendOptions) {
+		o.priority, o.hasPriority = priority, true
+	}
+}
+
+// effectivePriority returns the priority of the message
+func (o *sendOptions) effectivePriority() Priority {
+	if o.hasPriority {
+		return o.priority
+	}
+	if o.severity == SeverityCritical {
+		return PriorityHigh
+	}
+	return PriorityNormal
+}
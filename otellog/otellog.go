@@ -0,0 +1,303 @@
+// Package otellog forwards OpenTelemetry log records to a Discord webhook.
+//
+// Handler implements the OTLP/HTTP logs endpoint with JSON encoding, so applications
+// instrumented with any OpenTelemetry SDK can use their standard OTLP log exporter with
+// Discord as the destination, configured through the usual environment variables:
+//
+//	OTEL_EXPORTER_OTLP_LOGS_ENDPOINT=http://notifier:4318/v1/logs
+//	OTEL_EXPORTER_OTLP_LOGS_PROTOCOL=http/json
+//
+// Records below the configured severity threshold are acknowledged and dropped.
+// The remaining records are posted as embeds carrying their trace and span IDs as fields.
+package otellog
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+// OpenTelemetry severity numbers marking the start of each severity range
+const (
+	SeverityTrace = 1
+	SeverityDebug = 5
+	SeverityInfo  = 9
+	SeverityWarn  = 13
+	SeverityError = 17
+	SeverityFatal = 21
+)
+
+const (
+	maxBodySize         = 4 << 20
+	maxEmbedsPerMessage = 10
+	maxFieldsPerEmbed   = 25
+	maxTitleLength      = 256
+	maxDescriptionLen   = 4096
+	maxFieldValueLength = 1024
+)
+
+// Colors used for each severity range
+var severityColors = []struct {
+	min   int
+	color int
+}{
+	{SeverityFatal, 0x8B0000},
+	{SeverityError, 0xE74C3C},
+	{SeverityWarn, 0xF1C40F},
+	{SeverityInfo, 0x3498DB},
+	{SeverityTrace, 0x95A5A6},
+}
+
+// Handler receives OTLP/HTTP JSON log exports and forwards them to a Discord webhook
+type Handler struct {
+	// WebhookURL is the destination webhook
+	WebhookURL string
+
+	// MinSeverity is the lowest severity number that is forwarded
+	MinSeverity int
+
+	// Send delivers payloads. It defaults to webhook.SendWebhook.
+	Send func(webhookURL string, payload webhook.Webhook) error
+}
+
+// NewHandler creates a handler forwarding records at or above minSeverity to the webhook
+func NewHandler(webhookURL string, minSeverity int) *Handler {
+	return &Handler{
+		WebhookURL:  webhookURL,
+		MinSeverity: minSeverity,
+		Send:        webhook.SendWebhook,
+	}
+}
+
+// ServeHTTP handles an OTLP ExportLogsServiceRequest
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		http.Error(w, "only OTLP/HTTP JSON encoding is supported", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var request exportRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&request); err != nil {
+		http.Error(w, fmt.Sprintf("invalid OTLP payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	embeds := h.embeds(request)
+	for len(embeds) > 0 {
+		n := len(embeds)
+		if n > maxEmbedsPerMessage {
+			n = maxEmbedsPerMessage
+		}
+		if err := h.Send(h.WebhookURL, webhook.Webhook{Embeds: embeds[:n]}); err != nil {
+			// 503 tells OTLP exporters that the export can be retried
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		embeds = embeds[n:]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte("{}"))
+}
+
+// embeds converts all records at or above the threshold into embeds
+func (h *Handler) embeds(request exportRequest) []webhook.Embed {
+	var embeds []webhook.Embed
+	for _, resourceLogs := range request.ResourceLogs {
+		service := attributeString(resourceLogs.Resource.Attributes, "service.name")
+		for _, scopeLogs := range resourceLogs.ScopeLogs {
+			for _, record := range scopeLogs.LogRecords {
+				if record.SeverityNumber < h.MinSeverity {
+					continue
+				}
+				embeds = append(embeds, recordEmbed(record, service, scopeLogs.Scope.Name))
+			}
+		}
+	}
+	return embeds
+}
+
+// recordEmbed renders a single log record
+func recordEmbed(record logRecord, service, scope string) webhook.Embed {
+	title := record.SeverityText
+	if title == "" {
+		title = severityName(record.SeverityNumber)
+	}
+	if service != "" {
+		title = service + ": " + title
+	}
+
+	embed := webhook.Embed{
+		Title:       truncate(title, maxTitleLength),
+		Description: truncate(webhook.StripANSI(record.Body.String()), maxDescriptionLen),
+		Color:       severityColor(record.SeverityNumber),
+	}
+
+	nanos := record.TimeUnixNano
+	if nanos == "" {
+		nanos = record.ObservedTimeUnixNano
+	}
+	if n, err := nanos.Int64(); err == nil && n > 0 {
+		embed.Timestamp = time.Unix(0, n).UTC().Format(time.RFC3339)
+	}
+
+	if record.TraceID != "" {
+		embed.AddField(webhook.CreateField("Trace ID", "`"+strings.ToLower(record.TraceID)+"`", true))
+	}
+	if record.SpanID != "" {
+		embed.AddField(webhook.CreateField("Span ID", "`"+strings.ToLower(record.SpanID)+"`", true))
+	}
+	if scope != "" {
+		embed.SetFooter(webhook.CreateFooter(scope, "", ""))
+	}
+
+	for _, attribute := range record.Attributes {
+		if len(embed.Fields) >= maxFieldsPerEmbed {
+			break
+		}
+		value := webhook.StripANSI(attribute.Value.String())
+		if value == "" {
+			continue
+		}
+		embed.AddField(webhook.CreateField(truncate(attribute.Key, maxTitleLength), truncate(value, maxFieldValueLength), true))
+	}
+
+	return embed
+}
+
+// severityName returns the short severity name for a severity number
+func severityName(number int) string {
+	switch {
+	case number >= SeverityFatal:
+		return "FATAL"
+	case number >= SeverityError:
+		return "ERROR"
+	case number >= SeverityWarn:
+		return "WARN"
+	case number >= SeverityInfo:
+		return "INFO"
+	case number >= SeverityDebug:
+		return "DEBUG"
+	case number >= SeverityTrace:
+		return "TRACE"
+	default:
+		return "UNSPECIFIED"
+	}
+}
+
+// severityColor returns the embed color for a severity number
+func severityColor(number int) int {
+	for _, c := range severityColors {
+		if number >= c.min {
+			return c.color
+		}
+	}
+	return 0
+}
+
+// truncate shortens s to at most limit runes
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// attributeString returns the string form of the attribute with the given key
+func attributeString(attributes []keyValue, key string) string {
+	for _, attribute := range attributes {
+		if attribute.Key == key {
+			return attribute.Value.String()
+		}
+	}
+	return ""
+}
+
+// exportRequest is the JSON encoding of an OTLP ExportLogsServiceRequest
+type exportRequest struct {
+	ResourceLogs []struct {
+		Resource struct {
+			Attributes []keyValue `json:"attributes"`
+		} `json:"resource"`
+		ScopeLogs []struct {
+			Scope struct {
+				Name string `json:"name"`
+			} `json:"scope"`
+			LogRecords []logRecord `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+// logRecord is the JSON encoding of an OTLP LogRecord
+type logRecord struct {
+	TimeUnixNano         json.Number `json:"timeUnixNano"`
+	ObservedTimeUnixNano json.Number `json:"observedTimeUnixNano"`
+	SeverityNumber       int         `json:"severityNumber"`
+	SeverityText         string      `json:"severityText"`
+	Body                 anyValue    `json:"body"`
+	Attributes           []keyValue  `json:"attributes"`
+	TraceID              string      `json:"traceId"`
+	SpanID               string      `json:"spanId"`
+}
+
+// keyValue is the JSON encoding of an OTLP KeyValue
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+// anyValue is the JSON encoding of an OTLP AnyValue
+type anyValue struct {
+	StringValue *string                      `json:"stringValue"`
+	BoolValue   *bool                        `json:"boolValue"`
+	IntValue    *json.Number                 `json:"intValue"`
+	DoubleValue *float64                     `json:"doubleValue"`
+	BytesValue  *string                      `json:"bytesValue"`
+	ArrayValue  *struct{ Values []anyValue } `json:"arrayValue"`
+	KvlistValue *struct{ Values []keyValue } `json:"kvlistValue"`
+}
+
+// String renders the value for display
+func (v anyValue) String() string {
+	switch {
+	case v.StringValue != nil:
+		return *v.StringValue
+	case v.BoolValue != nil:
+		return strconv.FormatBool(*v.BoolValue)
+	case v.IntValue != nil:
+		return v.IntValue.String()
+	case v.DoubleValue != nil:
+		return strconv.FormatFloat(*v.DoubleValue, 'g', -1, 64)
+	case v.BytesValue != nil:
+		return *v.BytesValue
+	case v.ArrayValue != nil:
+		values := make([]string, len(v.ArrayValue.Values))
+		for i, value := range v.ArrayValue.Values {
+			values[i] = value.String()
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	case v.KvlistValue != nil:
+		pairs := make([]string, len(v.KvlistValue.Values))
+		for i, kv := range v.KvlistValue.Values {
+			pairs[i] = kv.Key + "=" + kv.Value.String()
+		}
+		sort.Strings(pairs)
+		return "{" + strings.Join(pairs, ", ") + "}"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,232 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultOutboxPollInterval = 5 * time.Second
+	defaultOutboxMaxAttempts  = 10
+	outboxBatchSize           = 100
+)
+
+// Execer runs statements, such as a *sql.DB or a *sql.Tx
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Outbox implements the transactional outbox pattern: application code writes
+// payloads into a database table in the same transaction as its own changes, and
+// a dispatcher delivers them with retries, removing each row once Discord accepted
+// it. Messages survive crashes and are delivered at least once. The table uses
+// SQLite syntax; the database driver is left to the application.
+type Outbox struct {
+	db     *sql.DB
+	table  string
+	client *Client
+
+	// PollInterval is how often the dispatcher looks for due messages. It defaults to 5s.
+	PollInterval time.Duration
+
+	// MaxAttempts is how often a message is tried before it is marked failed and
+	// left in the table for inspection. It defaults to 10.
+	MaxAttempts int
+
+	// OnError is called when the dispatcher gives up on a message or cannot read the table
+	OnError func(err error)
+
+	wake chan struct{}
+}
+
+// NewOutbox creates an outbox storing messages in the table, creating it if it does
+// not exist, and delivering them through the client
+func NewOutbox(db *sql.DB, table string, client *Client) (*Outbox, error) {
+	if !sqlIdentifier.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+	create := "CREATE TABLE IF NOT EXISTS " + table + ` (
+	id INTEGER PRIMARY KEY,
+	payload TEXT NOT NULL,
+	created_at INTEGER NOT NULL,
+	attempts INTEGER NOT NULL DEFAULT 0,
+	next_attempt_at INTEGER NOT NULL,
+	last_error TEXT,
+	failed_at INTEGER
+)`
+	if _, err := db.Exec(create); err != nil {
+		return nil, fmt.Errorf("failed to create outbox table: %v", err)
+	}
+	return &Outbox{
+		db:           db,
+		table:        table,
+		client:       client,
+		PollInterval: defaultOutboxPollInterval,
+		MaxAttempts:  defaultOutboxMaxAttempts,
+		wake:         make(chan struct{}, 1),
+	}, nil
+}
+
+// Add writes the payload into the outbox through exec, typically the transaction
+// that makes the change the message is about, so the message is stored if and only
+// if the transaction commits
+func (o *Outbox) Add(ctx context.Context, exec Execer, webhookPayload Webhook) error {
+	data, err := json.Marshal(webhookPayload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON payload: %v", err)
+	}
+	now := time.Now().Unix()
+	_, err = exec.ExecContext(ctx, "INSERT INTO "+o.table+" (payload, created_at, next_attempt_at) VALUES (?, ?, ?)",
+		string(data), now, now)
+	if err != nil {
+		return fmt.Errorf("failed to add message to outbox: %v", err)
+	}
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Run delivers due messages every poll interval, and soon after Add, until ctx is
+// cancelled. Only one dispatcher should run per table.
+func (o *Outbox) Run(ctx context.Context) error {
+	ticker := time.NewTicker(o.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := o.DeliverPending(ctx); err != nil && ctx.Err() == nil {
+			o.reportError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		case <-o.wake:
+		}
+	}
+}
+
+// outboxMessage is a row of the outbox table
+type outboxMessage struct {
+	id       int64
+	payload  Webhook
+	attempts int
+}
+
+// DeliverPending delivers the messages that are due, oldest first, and returns how
+// many were delivered. It stops early when Discord is unavailable.
+func (o *Outbox) DeliverPending(ctx context.Context) (int, error) {
+	messages, err := o.due(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, message := range messages {
+		sendErr := o.client.Send(ctx, message.payload)
+		if sendErr == nil {
+			if _, err := o.db.ExecContext(ctx, "DELETE FROM "+o.table+" WHERE id = ?", message.id); err != nil {
+				return delivered, fmt.Errorf("failed to remove delivered outbox message %d: %v", message.id, err)
+			}
+			delivered++
+			continue
+		}
+		if ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+
+		attempts := message.attempts + 1
+		delay, retryable := o.client.retryDelay(sendErr, message.attempts, 0)
+		if !retryable || attempts >= o.MaxAttempts {
+			if err := o.markFailed(ctx, message.id, attempts, sendErr); err != nil {
+				return delivered, err
+			}
+			o.reportError(fmt.Errorf("giving up on outbox message %d after %d attempts: %v", message.id, attempts, RedactError(sendErr)))
+			continue
+		}
+
+		// Errors may quote the webhook URL, whose token must not end up in the table
+		next := time.Now().Add(delay).Unix()
+		_, err := o.db.ExecContext(ctx, "UPDATE "+o.table+" SET attempts = ?, last_error = ?, next_attempt_at = ? WHERE id = ?",
+			attempts, RedactError(sendErr).Error(), next, message.id)
+		if err != nil {
+			return delivered, fmt.Errorf("failed to update outbox message %d: %v", message.id, err)
+		}
+		// The remaining messages would most likely fail the same way
+		break
+	}
+	return delivered, nil
+}
+
+// Pending returns the number of messages waiting for delivery, not counting failed ones
+func (o *Outbox) Pending(ctx context.Context) (int, error) {
+	var n int
+	if err := o.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+o.table+" WHERE failed_at IS NULL").Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count outbox messages: %v", err)
+	}
+	return n, nil
+}
+
+// markFailed records that the message will not be delivered, leaving it in the table for inspection
+func (o *Outbox) markFailed(ctx context.Context, id int64, attempts int, cause error) error {
+	_, err := o.db.ExecContext(ctx, "UPDATE "+o.table+" SET attempts = ?, last_error = ?, failed_at = ? WHERE id = ?",
+		attempts, RedactError(cause).Error(), time.Now().Unix(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update outbox message %d: %v", id, err)
+	}
+	return nil
+}
+
+// due reads the next batch of messages whose attempt is due. Messages that cannot be
+// decoded are marked failed, so they do not hold up the others.
+func (o *Outbox) due(ctx context.Context) ([]outboxMessage, error) {
+	rows, err := o.db.QueryContext(ctx, "SELECT id, payload, attempts FROM "+o.table+
+		" WHERE failed_at IS NULL AND next_attempt_at <= ? ORDER BY id LIMIT ?", time.Now().Unix(), outboxBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %v", err)
+	}
+	defer rows.Close()
+
+	var messages []outboxMessage
+	type corruptMessage struct {
+		outboxMessage
+		err error
+	}
+	var corrupt []corruptMessage
+	for rows.Next() {
+		var message outboxMessage
+		var data string
+		if err := rows.Scan(&message.id, &data, &message.attempts); err != nil {
+			return nil, fmt.Errorf("failed to read outbox: %v", err)
+		}
+		if err := json.Unmarshal([]byte(data), &message.payload); err != nil {
+			corrupt = append(corrupt, corruptMessage{message, fmt.Errorf("failed to decode outbox message %d: %v", message.id, err)})
+			continue
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %v", err)
+	}
+	// Databases such as SQLite cannot update the table while it is being read
+	rows.Close()
+
+	for _, message := range corrupt {
+		if err := o.markFailed(ctx, message.id, message.attempts, message.err); err != nil {
+			return nil, err
+		}
+		o.reportError(message.err)
+	}
+	return messages, nil
+}
+
+// reportError passes a dispatcher failure to the error handler, if there is one
+func (o *Outbox) reportError(err error) {
+	if o.OnError != nil {
+		o.OnError(err)
+	}
+}
@@ -0,0 +1,109 @@
+package webhook_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/webhooktest"
+)
+
+func newTestOutbox(t *testing.T, webhookURL string) (*webhook.Outbox, *sql.DB) {
+	t.Helper()
+	db, err := openFakeDB(t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	outbox, err := webhook.NewOutbox(db, "outbox", webhook.NewClient(webhookURL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return outbox, db
+}
+
+func TestOutboxSkipsUndecodableRows(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	outbox, db := newTestOutbox(t, server.URL)
+	var reported []error
+	outbox.OnError = func(err error) { reported = append(reported, err) }
+	ctx := context.Background()
+
+	if err := outbox.Add(ctx, db, webhook.Webhook{Content: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO outbox (payload, created_at, next_attempt_at) VALUES (?, ?, ?)", "{not json", 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := outbox.Add(ctx, db, webhook.Webhook{Content: "third"}); err != nil {
+		t.Fatal(err)
+	}
+
+	delivered, err := outbox.DeliverPending(ctx)
+	if err != nil {
+		t.Fatalf("DeliverPending: %v", err)
+	}
+	if delivered != 2 {
+		t.Errorf("delivered = %d, want 2", delivered)
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "decode outbox message 2") {
+		t.Errorf("reported errors = %v, want the decode error of message 2", reported)
+	}
+
+	var lastError string
+	var failedAt sql.NullInt64
+	if err := db.QueryRow("SELECT last_error, failed_at FROM outbox WHERE id = ?", 2).Scan(&lastError, &failedAt); err != nil {
+		t.Fatal(err)
+	}
+	if !failedAt.Valid || lastError == "" {
+		t.Errorf("undecodable row not marked failed: failed_at %v, last_error %q", failedAt, lastError)
+	}
+
+	// The failed row no longer holds up the outbox
+	if delivered, err := outbox.DeliverPending(ctx); err != nil || delivered != 0 {
+		t.Errorf("second DeliverPending = %d, %v, want 0, nil", delivered, err)
+	}
+	if pending, err := outbox.Pending(ctx); err != nil || pending != 0 {
+		t.Errorf("Pending = %d, %v, want 0, nil", pending, err)
+	}
+	if got := len(server.Requests()); got != 2 {
+		t.Errorf("server received %d requests, want 2", got)
+	}
+}
+
+func TestOutboxRedactsStoredErrors(t *testing.T) {
+	outbox, db := newTestOutbox(t, unreachableURL())
+	var reported []error
+	outbox.OnError = func(err error) { reported = append(reported, err) }
+	outbox.MaxAttempts = 2
+	ctx := context.Background()
+
+	if err := outbox.Add(ctx, db, webhook.Webhook{Content: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	for attempt := 0; attempt < 2; attempt++ {
+		// Make the message due again right away
+		if _, err := db.Exec("UPDATE outbox SET next_attempt_at = ? WHERE id = ?", 0, 1); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := outbox.DeliverPending(ctx); err != nil {
+			t.Fatalf("DeliverPending: %v", err)
+		}
+		var lastError string
+		if err := db.QueryRow("SELECT last_error FROM outbox WHERE id = ?", 1).Scan(&lastError); err != nil {
+			t.Fatal(err)
+		}
+		if lastError == "" || strings.Contains(lastError, "SECRETTOKEN") {
+			t.Errorf("attempt %d: last_error = %q, want the redacted error", attempt+1, lastError)
+		}
+	}
+	if len(reported) != 1 {
+		t.Fatalf("reported %d errors, want 1 for giving up", len(reported))
+	}
+	if strings.Contains(reported[0].Error(), "SECRETTOKEN") {
+		t.Errorf("reported error leaks the webhook token: %v", reported[0])
+	}
+}
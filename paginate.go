@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// pageSuffixReserve is the room left in every page for a title suffix such as " (Page 12/34)"
+const pageSuffixReserve = len(" (Page 999/999)")
+
+// PaginateFields distributes the fields over as many copies of the template embed
+// as the 25 field and 6000 character limits require, keeping their order, and
+// groups the embeds into as few messages as possible. With several pages, titles
+// get a suffix such as " (Page 2/3)". The description, author and thumbnail are
+// kept on the first page and the footer, image and timestamp on the last.
+func PaginateFields(template Embed, fields []Field) []Webhook {
+	base := utf8.RuneCountInString(template.Title) + pageSuffixReserve +
+		utf8.RuneCountInString(template.Description) + utf8.RuneCountInString(template.Author.Name) +
+		utf8.RuneCountInString(template.Footer.Text)
+
+	var pages [][]Field
+	var page []Field
+	length := base
+	for _, field := range fields {
+		fieldLength := utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+		if len(page) > 0 && (len(page) == maxEmbedFields || length+fieldLength > maxEmbedTotalLength) {
+			pages = append(pages, page)
+			page, length = nil, base
+		}
+		page = append(page, field)
+		length += fieldLength
+	}
+	if len(page) > 0 || len(pages) == 0 {
+		pages = append(pages, page)
+	}
+
+	embeds := make([]Embed, len(pages))
+	for i, fields := range pages {
+		embeds[i] = pageEmbed(template, fields, i, len(pages))
+	}
+
+	return SplitEmbeds(Webhook{Embeds: embeds})
+}
+
+// pageEmbed builds page i of n from the template
+func pageEmbed(template Embed, fields []Field, i, n int) Embed {
+	embed := Embed{
+		Title:  template.Title,
+		URL:    template.URL,
+		Color:  template.Color,
+		Fields: fields,
+	}
+	if n > 1 {
+		suffix := fmt.Sprintf("Page %d/%d", i+1, n)
+		if embed.Title == "" {
+			embed.Title = suffix
+		} else {
+			embed.Title += " (" + suffix + ")"
+		}
+	}
+	if i == 0 {
+		embed.Description = template.Description
+		embed.Author = template.Author
+		embed.Thumbnail = template.Thumbnail
+	}
+	if i == n-1 {
+		embed.Footer = template.Footer
+		embed.Image = template.Image
+		embed.Timestamp = template.Timestamp
+	}
+	return embed
+}
+
+// embedTextLength counts the characters of an embed that count towards Discord's 6000 character limit
+func embedTextLength(embed Embed) int {
+	n := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description) +
+		utf8.RuneCountInString(embed.Footer.Text) + utf8.RuneCountInString(embed.Author.Name)
+	for _, field := range embed.Fields {
+		n += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+	return n
+}
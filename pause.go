@@ -0,0 +1,165 @@
+package webhook
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	pausedColor  = 0x95A5A6
+	resumedColor = 0x57F287
+)
+
+// PauseMode selects what happens to non-critical messages queued while the client is paused
+type PauseMode int
+
+const (
+	// PauseHold keeps the messages and delivers them on Resume
+	PauseHold PauseMode = iota
+	// PauseDrop discards the messages
+	PauseDrop
+)
+
+// pauser holds back queued messages during maintenance
+type pauser struct {
+	mode PauseMode
+
+	mu      sync.Mutex
+	paused  bool
+	until   time.Time
+	timer   *time.Timer
+	held    []*queuedMessage
+	dropped int
+}
+
+// WithPauseMode sets what happens to non-critical messages queued while the client
+// is paused. It defaults to PauseHold.
+func WithPauseMode(mode PauseMode) ClientOption {
+	return func(c *Client) {
+		c.pause.mode = mode
+	}
+}
+
+// Pause holds back or drops queued messages below SeverityCritical until Resume,
+// such as during planned maintenance, and posts a marker saying notifications are paused
+func (c *Client) Pause() {
+	c.pauseUntil(time.Time{})
+}
+
+// PauseFor pauses like Pause, resuming automatically after d
+func (c *Client) PauseFor(d time.Duration) {
+	c.pauseUntil(time.Now().Add(d))
+}
+
+// Resume ends a pause, posting a marker saying how many messages were held or dropped,
+// followed by the held messages
+func (c *Client) Resume() {
+	p := &c.pause
+	p.mu.Lock()
+	if !p.paused {
+		p.mu.Unlock()
+		return
+	}
+	if p.timer != nil {
+		p.timer.Stop()
+	}
+	held, dropped := p.held, p.dropped
+	p.paused, p.until, p.timer, p.held, p.dropped = false, time.Time{}, nil, nil, 0
+	p.mu.Unlock()
+
+	description := "Notifications are back to normal."
+	switch {
+	case len(held) > 0:
+		description = "Delivering " + pluralize(len(held), "message") + " held during the pause."
+	case dropped > 0:
+		description = pluralize(dropped, "message") + " dropped during the pause."
+	}
+	// Only fails when the client was closed in the meantime
+	_ = c.enqueue(&queuedMessage{
+		payload: pauseMarker("Notifications resumed", description, resumedColor),
+		options: sendOptions{priority: PriorityHigh, hasPriority: true},
+	})
+	for _, message := range held {
+		_ = c.enqueue(message)
+	}
+}
+
+// Paused reports whether the client is paused
+func (c *Client) Paused() bool {
+	c.pause.mu.Lock()
+	defer c.pause.mu.Unlock()
+	return c.pause.paused
+}
+
+// pauseUntil starts or extends a pause; a zero time pauses until Resume
+func (c *Client) pauseUntil(until time.Time) {
+	p := &c.pause
+	p.mu.Lock()
+	wasPaused := p.paused
+	p.paused, p.until = true, until
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	if !until.IsZero() {
+		p.timer = time.AfterFunc(time.Until(until), c.Resume)
+	}
+	p.mu.Unlock()
+
+	if wasPaused {
+		return
+	}
+	description := "Non-critical notifications are paused until further notice."
+	if !until.IsZero() {
+		description = fmt.Sprintf("Non-critical notifications are paused until <t:%d:t>.", until.Unix())
+	}
+	_ = c.enqueue(&queuedMessage{
+		payload: pauseMarker("Notifications paused", description, pausedColor),
+		options: sendOptions{priority: PriorityHigh, hasPriority: true},
+	})
+}
+
+// allow reports whether the message may be queued now
+func (p *pauser) allow(message *queuedMessage) bool {
+	if message.options.severity >= SeverityCritical {
+		return true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.paused {
+		return true
+	}
+	if p.mode == PauseHold {
+		p.held = append(p.held, message)
+	} else {
+		p.dropped++
+		message.finish(SendResult{Suppressed: true})
+	}
+	return false
+}
+
+// flushAll queues the held messages, so Close delivers them, and cancels an automatic resume
+func (p *pauser) flushAll(c *Client) {
+	p.mu.Lock()
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	held := p.held
+	p.held = nil
+	p.mu.Unlock()
+	for _, message := range held {
+		_ = c.enqueue(message)
+	}
+}
+
+// pauseMarker builds the messages posted when pausing and resuming
+func pauseMarker(title, description string, color int) Webhook {
+	return Webhook{Embeds: []Embed{{
+		Title:       title,
+		Description: description,
+		Color:       color,
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+	}}}
+}
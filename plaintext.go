@@ -0,0 +1,134 @@
+package webhook
+
+import (
+	"errors"
+	"strings"
+)
+
+// PlainTextMode selects when a client sends embeds as plain text; see WithPlainTextFallback
+type PlainTextMode int
+
+const (
+	// PlainTextOnRejection resends a message as plain text when Discord rejects its embeds
+	PlainTextOnRejection PlainTextMode = iota
+	// PlainTextAlways sends every message as plain text, such as to minimal relays
+	// that only forward the content
+	PlainTextAlways
+)
+
+// WithPlainTextFallback flattens the embeds and link buttons of messages into their
+// content with ToPlainText, either always or to resend messages Discord rejected
+// because of their embeds
+func WithPlainTextFallback(mode PlainTextMode) ClientOption {
+	return func(c *Client) {
+		c.plainText = true
+		c.plainTextMode = mode
+	}
+}
+
+// ToPlainText renders the message as markdown text: the content followed by each embed's
+// author, title, description, fields, image and footer, and the links of link buttons.
+// It is also useful to log what was sent.
+func (w Webhook) ToPlainText() string {
+	var sections []string
+	if w.Content != "" {
+		sections = append(sections, w.Content)
+	}
+	for _, embed := range w.Embeds {
+		if text := embedPlainText(embed); text != "" {
+			sections = append(sections, text)
+		}
+	}
+	var links []string
+	for _, button := range flattenComponents(w.Components) {
+		if button.URL != "" {
+			links = append(links, "["+button.Label+"]("+button.URL+")")
+		}
+	}
+	if len(links) > 0 {
+		sections = append(sections, strings.Join(links, " · "))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// embedPlainText renders an embed as markdown lines
+func embedPlainText(embed Embed) string {
+	var lines []string
+	if embed.Author.Name != "" {
+		lines = append(lines, "*"+embed.Author.Name+"*")
+	}
+	switch {
+	case embed.Title != "" && embed.URL != "":
+		lines = append(lines, "**["+embed.Title+"]("+embed.URL+")**")
+	case embed.Title != "":
+		lines = append(lines, "**"+embed.Title+"**")
+	}
+	if embed.Description != "" {
+		lines = append(lines, embed.Description)
+	}
+	for _, field := range embed.Fields {
+		name, value := strings.TrimSpace(strings.ReplaceAll(field.Name, zeroWidthSpace, "")), field.Value
+		switch {
+		case name == "":
+			lines = append(lines, value)
+		case field.Inline && !strings.Contains(value, "\n"):
+			lines = append(lines, "**"+name+":** "+value)
+		default:
+			lines = append(lines, "**"+name+"**", value)
+		}
+	}
+	if embed.Image.URL != "" {
+		lines = append(lines, embed.Image.URL)
+	}
+	var footer []string
+	if embed.Footer.Text != "" {
+		footer = append(footer, embed.Footer.Text)
+	}
+	if embed.Timestamp != "" {
+		footer = append(footer, embed.Timestamp)
+	}
+	if len(footer) > 0 {
+		lines = append(lines, "-# "+strings.Join(footer, " · "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// flattenComponents lists the components inside action rows
+func flattenComponents(components []Component) []Component {
+	var flat []Component
+	for _, component := range components {
+		if component.Type == ComponentTypeActionRow {
+			flat = append(flat, flattenComponents(component.Components)...)
+			continue
+		}
+		flat = append(flat, component)
+	}
+	return flat
+}
+
+// plainTextPayload replaces the embeds and components of the payload with its plain
+// text rendering, shortened to the content limit if needed
+func plainTextPayload(webhookPayload Webhook) Webhook {
+	if len(webhookPayload.Embeds) == 0 && len(webhookPayload.Components) == 0 {
+		return webhookPayload
+	}
+	content := webhookPayload.ToPlainText()
+	truncateText(&content, "content", maxContentLength)
+	webhookPayload.Content = content
+	webhookPayload.Embeds, webhookPayload.Components = nil, nil
+	return webhookPayload
+}
+
+// embedsRejected reports whether Discord rejected the request because of its embeds
+func embedsRejected(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !errors.Is(apiErr, ErrValidation) {
+		return false
+	}
+	for path := range apiErr.FieldErrors() {
+		if path == "embeds" || strings.HasPrefix(path, "embeds.") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxPollQuestionLength = 300
+	maxPollAnswers        = 10
+	maxPollAnswerLength   = 55
+	// maxPollDurationHours is the longest a poll can run, 32 days
+	maxPollDurationHours = 768
+)
+
+// PollLayoutDefault is the only layout of polls Discord supports
+const PollLayoutDefault = 1
+
+// Poll is a poll attached to a message. Build it with CreatePoll and AddAnswer.
+type Poll struct {
+	Question PollMedia    `json:"question"`
+	Answers  []PollAnswer `json:"answers"`
+	// Duration is how many hours the poll is open, at most 768
+	Duration         int  `json:"duration,omitempty"`
+	AllowMultiselect bool `json:"allow_multiselect,omitempty"`
+	LayoutType       int  `json:"layout_type,omitempty"`
+}
+
+// PollMedia is the text and emoji of a poll's question or answer
+type PollMedia struct {
+	Text  string     `json:"text,omitempty"`
+	Emoji *PollEmoji `json:"emoji,omitempty"`
+}
+
+// PollAnswer is an answer of a poll
+type PollAnswer struct {
+	PollMedia PollMedia `json:"poll_media"`
+}
+
+// PollEmoji is a Unicode emoji, set by Name, or a custom emoji of a server, set by ID
+type PollEmoji struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+}
+
+// CreatePoll creates a poll asking the question for the duration, which is rounded up
+// to whole hours, between 1 hour and 32 days
+func CreatePoll(question string, duration time.Duration) (Poll, error) {
+	if question == "" {
+		return Poll{}, fmt.Errorf("poll question cannot be empty")
+	}
+	if n := utf8.RuneCountInString(question); n > maxPollQuestionLength {
+		return Poll{}, fmt.Errorf("the length of the poll question cannot exceed %d characters (your length: %d)", maxPollQuestionLength, n)
+	}
+	hours := int((duration + time.Hour - 1) / time.Hour)
+	if hours < 1 || hours > maxPollDurationHours {
+		return Poll{}, fmt.Errorf("poll duration must be between 1 hour and %d hours (got %v)", maxPollDurationHours, duration)
+	}
+	return Poll{
+		Question:   PollMedia{Text: question},
+		Duration:   hours,
+		LayoutType: PollLayoutDefault,
+	}, nil
+}
+
+// AddAnswer adds an answer to the poll
+func (p *Poll) AddAnswer(text string) error {
+	return p.AddAnswerWithEmoji("", text)
+}
+
+// AddAnswerWithEmoji adds an answer shown with an emoji: either a Unicode emoji such as
+// "🚀", or a custom emoji as written in messages, such as "<:shipit:123456789>"
+func (p *Poll) AddAnswerWithEmoji(emoji string, text string) error {
+	if len(p.Answers) >= maxPollAnswers {
+		return fmt.Errorf("a poll cannot have more than %d answers", maxPollAnswers)
+	}
+	if text == "" {
+		return fmt.Errorf("poll answer cannot be empty")
+	}
+	if n := utf8.RuneCountInString(text); n > maxPollAnswerLength {
+		return fmt.Errorf("the length of the poll answer cannot exceed %d characters (your length: %d)", maxPollAnswerLength, n)
+	}
+	answer := PollAnswer{PollMedia: PollMedia{Text: text}}
+	if emoji != "" {
+		answer.PollMedia.Emoji = parsePollEmoji(emoji)
+	}
+	p.Answers = append(p.Answers, answer)
+	return nil
+}
+
+// SetPoll attaches the poll to the message
+func (w *Webhook) SetPoll(poll Poll) {
+	w.Poll = &poll
+}
+
+// parsePollEmoji parses a custom emoji such as "<:name:id>" or "<a:name:id>", and
+// takes anything else as a Unicode emoji
+func parsePollEmoji(emoji string) *PollEmoji {
+	if strings.HasPrefix(emoji, "<") && strings.HasSuffix(emoji, ">") {
+		parts := strings.Split(strings.Trim(emoji, "<>"), ":")
+		if len(parts) == 3 && (parts[0] == "" || parts[0] == "a") && parts[2] != "" {
+			return &PollEmoji{ID: parts[2], Name: parts[1]}
+		}
+	}
+	return &PollEmoji{Name: emoji}
+}
+
+// poll validates the poll of a message
+func (v *validator) poll(poll *Poll) {
+	if poll.Question.Text == "" {
+		v.addf("poll.question.text", "cannot be empty")
+	}
+	v.maxLength("poll.question.text", poll.Question.Text, maxPollQuestionLength)
+	if n := len(poll.Answers); n == 0 || n > maxPollAnswers {
+		v.addf("poll.answers", "polls must have 1 to %d answers (got %d)", maxPollAnswers, n)
+	}
+	for i, answer := range poll.Answers {
+		path := fmt.Sprintf("poll.answers[%d].poll_media", i)
+		if answer.PollMedia.Text == "" {
+			v.addf(path+".text", "cannot be empty")
+		}
+		v.maxLength(path+".text", answer.PollMedia.Text, maxPollAnswerLength)
+		if emoji := answer.PollMedia.Emoji; emoji != nil && emoji.ID == "" && emoji.Name == "" {
+			v.addf(path+".emoji", "needs the ID of a custom emoji or the name of a Unicode emoji")
+		}
+	}
+	if poll.Duration < 0 || poll.Duration > maxPollDurationHours {
+		v.addf("poll.duration", "must be between 1 and %d hours", maxPollDurationHours)
+	}
+}
@@ -0,0 +1,218 @@
+package preview
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"regexp"
+	"strings"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+// defaultAvatarURL is shown when the payload does not override the avatar
+const defaultAvatarURL = "https://cdn.discordapp.com/embed/avatars/0.png"
+
+// HTMLOptions configures RenderHTML
+type HTMLOptions struct {
+	// Fragment renders only the message markup and its stylesheet, without the
+	// surrounding document, for embedding into docs or template galleries
+	Fragment bool
+}
+
+var (
+	markdownCodeBlock = regexp.MustCompile("(?s)```(?:[a-zA-Z0-9_+-]*\n)?(.*?)```")
+	markdownCode      = regexp.MustCompile("`([^`\n]+)`")
+	markdownLink      = regexp.MustCompile(`\[([^\]\n]+)\]\((https?://[^)\s]+)\)`)
+	markdownBold      = regexp.MustCompile(`\*\*([^*\n]+)\*\*`)
+	markdownUnderline = regexp.MustCompile(`__([^_\n]+)__`)
+	markdownItalic    = regexp.MustCompile(`\*([^*\n]+)\*|\b_([^_\n]+)_\b`)
+	markdownStrike    = regexp.MustCompile(`~~([^~\n]+)~~`)
+	markdownQuote     = regexp.MustCompile(`(?m)^&gt; (.*)$`)
+)
+
+// htmlField is a field with its grid placement
+type htmlField struct {
+	webhook.Field
+	Column string
+}
+
+// htmlEmbed is an embed prepared for the template
+type htmlEmbed struct {
+	webhook.Embed
+	Border string
+	Fields []htmlField
+}
+
+// htmlData is the data passed to the HTML template
+type htmlData struct {
+	Fragment   bool
+	Username   string
+	AvatarURL  string
+	Content    string
+	Embeds     []htmlEmbed
+	Components []webhook.Component
+}
+
+// RenderHTML writes a static HTML approximation of how Discord displays the payload:
+// avatar, username, content and embed cards with fields laid out like the client does.
+// Common markdown (bold, italics, code, links, quotes) is rendered.
+func RenderHTML(w io.Writer, payload webhook.Webhook, options HTMLOptions) error {
+	data := htmlData{
+		Fragment:   options.Fragment,
+		Username:   payload.Username,
+		AvatarURL:  payload.AvatarURL,
+		Content:    payload.Content,
+		Components: payload.Components,
+	}
+	if data.Username == "" {
+		data.Username = "Webhook"
+	}
+	if data.AvatarURL == "" {
+		data.AvatarURL = defaultAvatarURL
+	}
+
+	for _, embed := range payload.Embeds {
+		color := embed.Color
+		if color == 0 {
+			color = defaultEmbedColor
+		}
+		data.Embeds = append(data.Embeds, htmlEmbed{
+			Embed:  embed,
+			Border: fmt.Sprintf("#%06x", color),
+			Fields: layoutFields(embed.Fields),
+		})
+	}
+
+	return htmlTemplate.Execute(w, data)
+}
+
+// layoutFields assigns grid columns the way Discord does: up to three consecutive
+// inline fields share a row, other fields span the whole row
+func layoutFields(fields []webhook.Field) []htmlField {
+	const columns = 12
+	laidOut := make([]htmlField, 0, len(fields))
+	for i := 0; i < len(fields); {
+		group := 1
+		if fields[i].Inline {
+			for i+group < len(fields) && fields[i+group].Inline && group < inlineFieldsInRow {
+				group++
+			}
+		}
+		span := columns / group
+		for j := 0; j < group; j++ {
+			laidOut = append(laidOut, htmlField{
+				Field:  fields[i+j],
+				Column: fmt.Sprintf("%d / %d", j*span+1, (j+1)*span+1),
+			})
+		}
+		i += group
+	}
+	return laidOut
+}
+
+// renderMarkdown converts Discord markdown into HTML. The text is escaped first,
+// so only the markup produced here reaches the output.
+func renderMarkdown(text string) template.HTML {
+	escaped := html.EscapeString(text)
+
+	// Protect code from further formatting
+	var code []string
+	stash := func(rendered string) string {
+		code = append(code, rendered)
+		return fmt.Sprintf("\x00%d\x00", len(code)-1)
+	}
+	escaped = markdownCodeBlock.ReplaceAllStringFunc(escaped, func(match string) string {
+		return stash("<pre><code>" + markdownCodeBlock.FindStringSubmatch(match)[1] + "</code></pre>")
+	})
+	escaped = markdownCode.ReplaceAllStringFunc(escaped, func(match string) string {
+		return stash("<code>" + markdownCode.FindStringSubmatch(match)[1] + "</code>")
+	})
+
+	escaped = markdownLink.ReplaceAllString(escaped, `<a href="$2">$1</a>`)
+	escaped = markdownBold.ReplaceAllString(escaped, "<strong>$1</strong>")
+	escaped = markdownUnderline.ReplaceAllString(escaped, "<u>$1</u>")
+	escaped = markdownItalic.ReplaceAllString(escaped, "<em>$1$2</em>")
+	escaped = markdownStrike.ReplaceAllString(escaped, "<s>$1</s>")
+	escaped = markdownQuote.ReplaceAllString(escaped, "<blockquote>$1</blockquote>")
+	escaped = strings.ReplaceAll(escaped, "\n", "<br>")
+
+	for i, rendered := range code {
+		escaped = strings.Replace(escaped, fmt.Sprintf("\x00%d\x00", i), rendered, 1)
+	}
+
+	return template.HTML(escaped)
+}
+
+var htmlTemplate = template.Must(template.New("preview").Funcs(template.FuncMap{
+	"markdown": renderMarkdown,
+	"isLink": func(c webhook.Component) bool {
+		return c.Style == webhook.ButtonStyleLink
+	},
+	"safeCSS": func(s string) template.CSS {
+		return template.CSS(s)
+	},
+}).Parse(`{{if not .Fragment}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Username}} preview</title>
+</head>
+<body class="dw-page">
+{{end}}<style>
+.dw-page { background: #313338; margin: 0; padding: 16px; }
+.dw-message { display: flex; gap: 16px; padding: 8px 16px; background: #313338; color: #dbdee1;
+  font: 16px/1.375 "gg sans", "Noto Sans", "Helvetica Neue", Helvetica, Arial, sans-serif; max-width: 760px; }
+.dw-avatar { width: 40px; height: 40px; border-radius: 50%; flex: none; }
+.dw-body { min-width: 0; flex: 1; }
+.dw-username { color: #f2f3f5; font-weight: 500; }
+.dw-bot { background: #5865f2; color: #fff; font-size: 10px; font-weight: 600; border-radius: 3px; padding: 1px 4px; margin-left: 4px; vertical-align: 2px; }
+.dw-content { white-space: normal; word-wrap: break-word; }
+.dw-embed { display: flex; justify-content: space-between; gap: 16px; max-width: 516px; margin-top: 4px; padding: 8px 16px 16px 12px;
+  background: #2b2d31; border-left: 4px solid; border-radius: 4px; font-size: 14px; }
+.dw-embed-main { min-width: 0; flex: 1; }
+.dw-author { display: flex; align-items: center; gap: 8px; margin-top: 8px; font-weight: 600; color: #f2f3f5; }
+.dw-author img { width: 24px; height: 24px; border-radius: 50%; }
+.dw-title { margin-top: 8px; font-weight: 600; color: #f2f3f5; }
+.dw-description { margin-top: 8px; }
+.dw-fields { display: grid; grid-template-columns: repeat(12, 1fr); gap: 8px; margin-top: 8px; }
+.dw-field-name { font-weight: 600; color: #f2f3f5; }
+.dw-image { max-width: 100%; margin-top: 16px; border-radius: 4px; }
+.dw-thumbnail { max-width: 80px; max-height: 80px; margin-top: 8px; border-radius: 4px; }
+.dw-footer { display: flex; align-items: center; gap: 8px; margin-top: 8px; font-size: 12px; color: #b5bac1; }
+.dw-footer img { width: 20px; height: 20px; border-radius: 50%; }
+.dw-buttons { display: flex; flex-wrap: wrap; gap: 8px; margin-top: 8px; }
+.dw-button { padding: 2px 16px; line-height: 28px; border-radius: 3px; background: #4e5058; color: #fff; font-size: 14px; text-decoration: none; }
+.dw-message a { color: #00a8fc; text-decoration: none; }
+.dw-message a.dw-button { color: #fff; }
+.dw-message code { background: #1e1f22; border-radius: 3px; padding: 0 2px; font-size: 85%; }
+.dw-message pre { background: #1e1f22; border: 1px solid #1e1f22; border-radius: 4px; padding: 8px; white-space: pre-wrap; }
+.dw-message blockquote { margin: 0; padding-left: 12px; border-left: 4px solid #4e5058; }
+</style>
+<div class="dw-message">
+<img class="dw-avatar" src="{{.AvatarURL}}" alt="">
+<div class="dw-body">
+<div><span class="dw-username">{{.Username}}</span><span class="dw-bot">BOT</span></div>
+{{with .Content}}<div class="dw-content">{{markdown .}}</div>
+{{end}}{{range .Embeds}}<div class="dw-embed" style="border-color: {{safeCSS .Border}}">
+<div class="dw-embed-main">
+{{with .Author}}{{if .Name}}<div class="dw-author">{{with .IconURL}}<img src="{{.}}" alt="">{{end}}{{if .URL}}<a href="{{.URL}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}</div>
+{{end}}{{end}}{{if .Title}}<div class="dw-title">{{if .URL}}<a href="{{.URL}}">{{.Title}}</a>{{else}}{{.Title}}{{end}}</div>
+{{end}}{{with .Description}}<div class="dw-description">{{markdown .}}</div>
+{{end}}{{with .Fields}}<div class="dw-fields">
+{{range .}}<div style="grid-column: {{safeCSS .Column}}"><div class="dw-field-name">{{markdown .Name}}</div><div>{{markdown .Value}}</div></div>
+{{end}}</div>
+{{end}}{{with .Image.URL}}<img class="dw-image" src="{{.}}" alt="">
+{{end}}{{if or .Footer.Text .Timestamp}}<div class="dw-footer">{{with .Footer.IconURL}}<img src="{{.}}" alt="">{{end}}<span>{{.Footer.Text}}{{if and .Footer.Text .Timestamp}} • {{end}}{{.Timestamp}}</span></div>
+{{end}}</div>
+{{with .Thumbnail.URL}}<img class="dw-thumbnail" src="{{.}}" alt="">
+{{end}}</div>
+{{end}}{{range .Components}}<div class="dw-buttons">
+{{range .Components}}{{if isLink .}}<a class="dw-button" href="{{.URL}}">{{.Label}} ↗</a>{{else}}<span class="dw-button">{{.Label}}</span>{{end}}
+{{end}}</div>
+{{end}}</div>
+</div>
+{{if not .Fragment}}</body>
+</html>
+{{end}}`))
@@ -0,0 +1,244 @@
+// Package preview renders approximate mock-ups of how Discord displays a webhook payload,
+// so message formatting can be iterated on without posting to a real channel.
+package preview
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+const (
+	defaultWidth      = 80
+	inlineFieldsInRow = 3
+
+	// defaultEmbedColor is the border color Discord uses for embeds without a color
+	defaultEmbedColor = 0x4E5058
+)
+
+// ANSI escape sequences used by the renderer
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiBlue  = "\x1b[38;2;0;168;252m"
+)
+
+// TerminalOptions configures RenderTerminal
+type TerminalOptions struct {
+	// Width is the number of columns to render into. It defaults to 80.
+	Width int
+
+	// NoColor disables ANSI escape sequences
+	NoColor bool
+}
+
+// terminal writes a payload mock-up
+type terminal struct {
+	w       io.Writer
+	width   int
+	noColor bool
+	err     error
+}
+
+// RenderTerminal writes an approximate, colored mock-up of the payload to w:
+// the username and content, each embed with a border in the embed color, inline
+// fields laid out in columns, and buttons below the message. Markdown is shown as is.
+func RenderTerminal(w io.Writer, payload webhook.Webhook, options TerminalOptions) error {
+	t := &terminal{w: w, width: options.Width, noColor: options.NoColor}
+	if t.width <= 0 {
+		t.width = defaultWidth
+	}
+
+	username := payload.Username
+	if username == "" {
+		username = "Webhook"
+	}
+	t.line(t.style(ansiBold, username) + " " + t.style(ansiDim, "BOT"))
+
+	for _, line := range wrap(payload.Content, t.width) {
+		t.line(line)
+	}
+
+	for _, embed := range payload.Embeds {
+		t.line("")
+		t.embed(embed)
+	}
+
+	if len(payload.Components) > 0 {
+		t.line("")
+	}
+	for _, row := range payload.Components {
+		var buttons []string
+		for _, button := range row.Components {
+			label := button.Label
+			if button.Style == webhook.ButtonStyleLink {
+				label += " ↗"
+			}
+			buttons = append(buttons, "[ "+label+" ]")
+		}
+		t.line(strings.Join(buttons, " "))
+	}
+
+	return t.err
+}
+
+// embed renders an embed with a colored border
+func (t *terminal) embed(embed webhook.Embed) {
+	color := embed.Color
+	if color == 0 {
+		color = defaultEmbedColor
+	}
+	border := t.style(fmt.Sprintf("\x1b[38;2;%d;%d;%dm", color>>16&0xFF, color>>8&0xFF, color&0xFF), "▌") + " "
+	inner := t.width - 2
+
+	row := func(text string) {
+		t.line(border + text)
+	}
+	block := func(text, style string) {
+		for _, line := range wrap(text, inner) {
+			row(t.style(style, line))
+		}
+	}
+
+	if embed.Author.Name != "" {
+		block(embed.Author.Name, ansiBold)
+	}
+	if embed.Title != "" {
+		style := ansiBold
+		if embed.URL != "" {
+			style = ansiBold + ansiBlue
+		}
+		block(embed.Title, style)
+	}
+	if embed.Description != "" {
+		block(embed.Description, "")
+	}
+
+	t.fields(embed.Fields, inner, row)
+
+	if embed.Image.URL != "" {
+		block("[image] "+embed.Image.URL, ansiDim)
+	}
+	if embed.Thumbnail.URL != "" {
+		block("[thumbnail] "+embed.Thumbnail.URL, ansiDim)
+	}
+
+	footer := embed.Footer.Text
+	if embed.Timestamp != "" {
+		if footer != "" {
+			footer += " • "
+		}
+		footer += embed.Timestamp
+	}
+	if footer != "" {
+		block(footer, ansiDim)
+	}
+}
+
+// fields lays out consecutive inline fields in up to three columns
+func (t *terminal) fields(fields []webhook.Field, width int, row func(string)) {
+	for i := 0; i < len(fields); {
+		group := []webhook.Field{fields[i]}
+		if fields[i].Inline {
+			for i+len(group) < len(fields) && fields[i+len(group)].Inline && len(group) < inlineFieldsInRow {
+				group = append(group, fields[i+len(group)])
+			}
+		}
+		i += len(group)
+
+		columnWidth := width
+		if len(group) > 1 {
+			columnWidth = (width - 2*(len(group)-1)) / len(group)
+		}
+
+		names := make([][]string, len(group))
+		values := make([][]string, len(group))
+		for j, field := range group {
+			names[j] = wrap(field.Name, columnWidth)
+			values[j] = wrap(field.Value, columnWidth)
+		}
+		t.columns(names, columnWidth, ansiBold, row)
+		t.columns(values, columnWidth, "", row)
+	}
+}
+
+// columns renders wrapped cells side by side
+func (t *terminal) columns(cells [][]string, width int, style string, row func(string)) {
+	height := 0
+	for _, cell := range cells {
+		if len(cell) > height {
+			height = len(cell)
+		}
+	}
+	for line := 0; line < height; line++ {
+		var b strings.Builder
+		for j, cell := range cells {
+			text := ""
+			if line < len(cell) {
+				text = cell[line]
+			}
+			if j < len(cells)-1 {
+				text += strings.Repeat(" ", width-utf8.RuneCountInString(text)+2)
+			}
+			b.WriteString(t.style(style, text))
+		}
+		row(strings.TrimRight(b.String(), " "))
+	}
+}
+
+// style wraps text in an ANSI style unless colors are disabled
+func (t *terminal) style(style, text string) string {
+	if t.noColor || style == "" || text == "" {
+		return text
+	}
+	return style + text + ansiReset
+}
+
+// line writes a line, remembering the first write error
+func (t *terminal) line(text string) {
+	if t.err != nil {
+		return
+	}
+	_, t.err = fmt.Fprintln(t.w, text)
+}
+
+// wrap breaks text into lines of at most width runes, preferring word boundaries
+func wrap(text string, width int) []string {
+	if text == "" {
+		return nil
+	}
+	if width < 1 {
+		width = 1
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for utf8.RuneCountInString(word) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				runes := []rune(word)
+				lines = append(lines, string(runes[:width]))
+				word = string(runes[width:])
+			}
+			switch {
+			case line == "":
+				line = word
+			case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) <= width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
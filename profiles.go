@@ -0,0 +1,231 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Destination is a named webhook with the defaults of its messages
+type Destination struct {
+	URL string `json:"url"`
+
+	// Username, AvatarURL and AllowedMentions apply to messages that do not set them
+	Username        string           `json:"username,omitempty"`
+	AvatarURL       string           `json:"avatar_url,omitempty"`
+	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
+}
+
+// apply fills in the defaults the payload does not set
+func (d *Destination) apply(webhookPayload Webhook) Webhook {
+	if webhookPayload.Username == "" {
+		webhookPayload.Username = d.Username
+	}
+	if webhookPayload.AvatarURL == "" {
+		webhookPayload.AvatarURL = d.AvatarURL
+	}
+	if webhookPayload.AllowedMentions == nil {
+		webhookPayload.AllowedMentions = d.AllowedMentions
+	}
+	return webhookPayload
+}
+
+// Profiles maps logical destination names such as "prod-alerts" to webhooks, so
+// code refers to names and each environment supplies its own URLs
+type Profiles struct {
+	options []ClientOption
+
+	mu           sync.Mutex
+	destinations map[string]Destination
+	clients      map[string]*Client
+}
+
+// NewProfiles creates an empty set of destinations whose clients are configured with the options
+func NewProfiles(options ...ClientOption) *Profiles {
+	return &Profiles{
+		options:      options,
+		destinations: make(map[string]Destination),
+		clients:      make(map[string]*Client),
+	}
+}
+
+// LoadProfiles reads destinations from a JSON file mapping names to destinations:
+//
+//	{"prod-alerts": {"url": "${PROD_ALERTS_WEBHOOK_URL}", "username": "Alerts"}}
+//
+// Environment variables in the values are expanded, so the file can be committed
+// without the webhook tokens. Files in a format registered with RegisterFileFormat
+// are converted into JSON first; import the yamlwebhook module to load YAML files
+// (.yaml and .yml), which are rejected without it.
+func LoadProfiles(path string, options ...ClientOption) (*Profiles, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles: %v", err)
+	}
+	if converted, registered, err := FileToJSON(path, data); registered {
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse profiles %s: %v", path, err)
+		}
+		data = converted
+	} else if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		return nil, fmt.Errorf("profiles %s: YAML profiles need the yamlwebhook module", path)
+	}
+
+	var destinations map[string]Destination
+	if err := json.Unmarshal(data, &destinations); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles %s: %v", path, err)
+	}
+	p := NewProfiles(options...)
+	for name, destination := range destinations {
+		destination.URL = os.ExpandEnv(destination.URL)
+		destination.Username = os.ExpandEnv(destination.Username)
+		destination.AvatarURL = os.ExpandEnv(destination.AvatarURL)
+		if err := p.Add(name, destination); err != nil {
+			return nil, fmt.Errorf("profiles %s: %v", path, err)
+		}
+	}
+	return p, nil
+}
+
+// ProfilesFromEnv reads destinations from environment variables starting with the
+// prefix: DISCORD_PROD_ALERTS_URL, DISCORD_PROD_ALERTS_USERNAME and
+// DISCORD_PROD_ALERTS_AVATAR_URL define "prod-alerts" for the prefix "DISCORD_".
+func ProfilesFromEnv(prefix string, options ...ClientOption) (*Profiles, error) {
+	destinations := make(map[string]Destination)
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		key = strings.TrimPrefix(key, prefix)
+
+		// The longest suffix wins, so FOO_AVATAR_URL is not read as the URL of "foo-avatar"
+		var name string
+		var set func(d *Destination)
+		switch {
+		case strings.HasSuffix(key, "_AVATAR_URL"):
+			name, set = strings.TrimSuffix(key, "_AVATAR_URL"), func(d *Destination) { d.AvatarURL = value }
+		case strings.HasSuffix(key, "_USERNAME"):
+			name, set = strings.TrimSuffix(key, "_USERNAME"), func(d *Destination) { d.Username = value }
+		case strings.HasSuffix(key, "_URL"):
+			name, set = strings.TrimSuffix(key, "_URL"), func(d *Destination) { d.URL = value }
+		default:
+			continue
+		}
+		name = strings.ReplaceAll(strings.ToLower(name), "_", "-")
+		destination := destinations[name]
+		set(&destination)
+		destinations[name] = destination
+	}
+
+	p := NewProfiles(options...)
+	for name, destination := range destinations {
+		if err := p.Add(name, destination); err != nil {
+			return nil, fmt.Errorf("environment profiles: %v", err)
+		}
+	}
+	return p, nil
+}
+
+// Add defines or replaces the destination with the name
+func (p *Profiles) Add(name string, destination Destination) error {
+	if name == "" {
+		return fmt.Errorf("destination name cannot be empty")
+	}
+	if destination.URL == "" {
+		return fmt.Errorf("destination %q has no webhook URL", name)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.destinations[name] = destination
+	delete(p.clients, name)
+	return nil
+}
+
+// Names returns the names of the destinations in alphabetical order
+func (p *Profiles) Names() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	names := make([]string, 0, len(p.destinations))
+	for name := range p.destinations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Destination returns the destination with the name
+func (p *Profiles) Destination(name string) (Destination, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	destination, ok := p.destinations[name]
+	return destination, ok
+}
+
+// Client returns the client of the destination, created on first use. It does not
+// apply the destination's defaults; Send and Enqueue do.
+func (p *Profiles) Client(name string) (*Client, error) {
+	client, _, err := p.lookup(name)
+	return client, err
+}
+
+// Send sends the payload to the destination with its defaults applied
+func (p *Profiles) Send(ctx context.Context, name string, webhookPayload Webhook) error {
+	client, destination, err := p.lookup(name)
+	if err != nil {
+		return err
+	}
+	return client.Send(ctx, destination.apply(webhookPayload))
+}
+
+// Enqueue queues the payload for the destination with its defaults applied, like Client.Enqueue
+func (p *Profiles) Enqueue(name string, webhookPayload Webhook, options ...SendOption) (*Delivery, error) {
+	client, destination, err := p.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	return client.Enqueue(destination.apply(webhookPayload), options...)
+}
+
+// Close closes the clients of all destinations, flushing their queues
+func (p *Profiles) Close(ctx context.Context) error {
+	p.mu.Lock()
+	clients := make([]*Client, 0, len(p.clients))
+	for _, client := range p.clients {
+		clients = append(clients, client)
+	}
+	p.mu.Unlock()
+
+	var failed []string
+	for _, client := range clients {
+		if err := client.Close(ctx); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", RedactWebhookURL(client.URL()), err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to close %d of %d webhooks: %s", len(failed), len(clients), strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// lookup returns the client and destination with the name
+func (p *Profiles) lookup(name string) (*Client, *Destination, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	destination, ok := p.destinations[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown destination %q", name)
+	}
+	client, ok := p.clients[name]
+	if !ok {
+		client = newClient(destination.URL, p.options)
+		publishExpvarOf(client, name)
+		p.clients[name] = client
+	}
+	return client, &destination, nil
+}
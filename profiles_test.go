@@ -0,0 +1,34 @@
+package webhook
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadProfiles(t *testing.T) {
+	t.Setenv("TEST_ALERTS_URL", "https://discord.com/api/webhooks/1/token")
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+	if err := os.WriteFile(path, []byte(`{"alerts": {"url": "${TEST_ALERTS_URL}", "username": "Alerts"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	profiles, err := LoadProfiles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	destination, ok := profiles.Destination("alerts")
+	if !ok || destination.URL != "https://discord.com/api/webhooks/1/token" || destination.Username != "Alerts" {
+		t.Errorf("destination = %+v, %v", destination, ok)
+	}
+}
+
+func TestLoadProfilesRejectsYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.yaml")
+	if err := os.WriteFile(path, []byte("alerts:\n  url: https://discord.com/api/webhooks/1/token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProfiles(path); err == nil || !strings.Contains(err.Error(), "YAML") {
+		t.Errorf("error = %v, want YAML to be rejected", err)
+	}
+}
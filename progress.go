@@ -0,0 +1,185 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	progressColor       = 0x5865F2
+	progressDoneColor   = 0x57F287
+	progressFailedColor = 0xED4245
+	progressBarWidth    = 20
+	progressEditTimeout = 10 * time.Second
+)
+
+// Progress reports the progress of a long running task in a single message. Update
+// may be called as often as needed: the message is edited at most once per
+// interval with the latest state, so frequent updates do not hit rate limits.
+type Progress struct {
+	client   *Client
+	title    string
+	interval time.Duration
+	started  time.Time
+
+	// OnError is called when an update in the background could not be delivered
+	OnError func(err error)
+
+	// publishMu serializes the requests, so edits arrive in order
+	publishMu sync.Mutex
+	messageID string
+
+	mu       sync.Mutex
+	percent  float64
+	note     string
+	lastEdit time.Time
+	timer    *time.Timer
+	finished bool
+}
+
+// NewProgress creates a progress message with the title, edited at most once per interval.
+// The message is posted with the first update.
+func NewProgress(client *Client, title string, interval time.Duration) *Progress {
+	return &Progress{client: client, title: title, interval: interval, started: time.Now()}
+}
+
+// Update records the progress, from 0 to 100, and a note on the current step. It
+// never blocks: the message is edited in the background once the interval since
+// the last edit has passed.
+func (p *Progress) Update(percent float64, note string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.finished {
+		return
+	}
+	p.percent, p.note = clampPercent(percent), note
+	if p.timer == nil {
+		p.timer = time.AfterFunc(time.Until(p.lastEdit.Add(p.interval)), p.flush)
+	}
+}
+
+// Done marks the task complete, editing the message with the note and the total duration
+func (p *Progress) Done(ctx context.Context, note string) error {
+	return p.finish(ctx, 100, note, progressDoneColor, "completed")
+}
+
+// Fail marks the task failed, keeping the progress it made
+func (p *Progress) Fail(ctx context.Context, err error) error {
+	p.mu.Lock()
+	percent := p.percent
+	p.mu.Unlock()
+	return p.finish(ctx, percent, err.Error(), progressFailedColor, "failed")
+}
+
+// MessageID returns the ID of the progress message, or an empty string before it is posted
+func (p *Progress) MessageID() string {
+	p.publishMu.Lock()
+	defer p.publishMu.Unlock()
+	return p.messageID
+}
+
+// flush edits the message with the latest progress
+func (p *Progress) flush() {
+	p.publishMu.Lock()
+	defer p.publishMu.Unlock()
+
+	p.mu.Lock()
+	if p.finished {
+		p.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	payload := p.payload(now, p.percent, p.note, progressColor, "")
+	p.lastEdit, p.timer = now, nil
+	p.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), progressEditTimeout)
+	defer cancel()
+	if err := p.publish(ctx, payload); err != nil && p.OnError != nil {
+		p.OnError(err)
+	}
+}
+
+// finish stops the background updates and publishes the final state
+func (p *Progress) finish(ctx context.Context, percent float64, note string, color int, outcome string) error {
+	p.mu.Lock()
+	if p.finished {
+		p.mu.Unlock()
+		return fmt.Errorf("progress %q is already finished", p.title)
+	}
+	p.finished = true
+	if p.timer != nil {
+		p.timer.Stop()
+		p.timer = nil
+	}
+	p.mu.Unlock()
+
+	p.publishMu.Lock()
+	defer p.publishMu.Unlock()
+	return p.publish(ctx, p.payload(time.Now(), percent, note, color, outcome))
+}
+
+// publish posts the message, or edits it once it exists. A deleted message is
+// posted again. The caller holds publishMu.
+func (p *Progress) publish(ctx context.Context, payload Webhook) error {
+	if p.messageID != "" {
+		err := p.client.Edit(ctx, p.messageID, payload)
+		if !isNotFound(err) {
+			return err
+		}
+		p.messageID = ""
+	}
+	message, err := p.client.execute(ctx, payload, true)
+	if err != nil {
+		return err
+	}
+	p.messageID = message.ID
+	return nil
+}
+
+// payload builds the progress message. An outcome marks the final state.
+func (p *Progress) payload(now time.Time, percent float64, note string, color int, outcome string) Webhook {
+	description := fmt.Sprintf("%s %.0f%%", progressBar(percent), percent)
+	if note = strings.TrimSpace(note); note != "" {
+		description += "\n" + note
+	}
+
+	elapsed := now.Sub(p.started)
+	title := p.title
+	fields := []Field{{Name: "Elapsed", Value: formatDuration(elapsed), Inline: true}}
+	if outcome != "" {
+		title += " " + outcome
+	} else if percent > 0 && percent < 100 {
+		remaining := time.Duration(float64(elapsed) * (100 - percent) / percent)
+		fields = append(fields, Field{Name: "Remaining", Value: "about " + formatDuration(remaining), Inline: true})
+	}
+
+	return Webhook{Embeds: []Embed{{
+		Title:       truncateRunes(title, maxEmbedTitleLength),
+		Description: truncateRunes(description, maxEmbedDescriptionLength),
+		Color:       color,
+		Fields:      fields,
+		Timestamp:   now.UTC().Format(time.RFC3339),
+	}}}
+}
+
+// progressBar renders the percentage as a bar of filled and empty blocks
+func progressBar(percent float64) string {
+	filled := int(percent / 100 * progressBarWidth)
+	return strings.Repeat("▰", filled) + strings.Repeat("▱", progressBarWidth-filled)
+}
+
+// clampPercent keeps a percentage between 0 and 100
+func clampPercent(percent float64) float64 {
+	if percent < 0 || math.IsNaN(percent) {
+		return 0
+	}
+	if percent > 100 {
+		return 100
+	}
+	return percent
+}
@@ -0,0 +1,145 @@
+package webhook
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// Version 1-M codewords of "HELLO WORLD" from the QR code specification walkthrough
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+
+	if got := rsRemainder(data, rsDivisor(len(want))); !bytes.Equal(got, want) {
+		t.Errorf("rsRemainder = %v, want %v", got, want)
+	}
+}
+
+func TestEncodeQRVersion(t *testing.T) {
+	tests := []struct {
+		length  int
+		version int
+	}{
+		{0, 1},
+		{14, 1},
+		{15, 2},
+		{26, 2},
+		{27, 3},
+		{2331, 40},
+	}
+	for _, tt := range tests {
+		code, err := encodeQR(bytes.Repeat([]byte("a"), tt.length))
+		if err != nil {
+			t.Fatalf("encodeQR(%d bytes): %v", tt.length, err)
+		}
+		if code.version != tt.version || code.size != 17+4*tt.version {
+			t.Errorf("encodeQR(%d bytes) = version %d of size %d, want version %d", tt.length, code.version, code.size, tt.version)
+		}
+	}
+
+	if _, err := encodeQR(bytes.Repeat([]byte("a"), 2332)); err == nil {
+		t.Error("encodeQR(2332 bytes) succeeded, want an error")
+	}
+}
+
+func TestEncodeQRFunctionPatterns(t *testing.T) {
+	code, err := encodeQR([]byte("https://discord.gg/example"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Finder patterns: a dark 7x7 ring around a light ring around a dark 3x3 center
+	finder := func(left, top int) {
+		for y := 0; y < 7; y++ {
+			for x := 0; x < 7; x++ {
+				ring := chebyshev(x-3, y-3)
+				if want := ring != 2; code.modules[top+y][left+x] != want {
+					t.Errorf("finder module (%d, %d) = %v, want %v", left+x, top+y, !want, want)
+				}
+			}
+		}
+	}
+	finder(0, 0)
+	finder(code.size-7, 0)
+	finder(0, code.size-7)
+
+	for i := 8; i < code.size-8; i++ {
+		if code.modules[6][i] != (i%2 == 0) || code.modules[i][6] != (i%2 == 0) {
+			t.Errorf("timing pattern broken at %d", i)
+		}
+	}
+}
+
+func TestEncodeQRFormatBits(t *testing.T) {
+	// Format information for level M and each mask, from the QR code specification
+	valid := map[int]bool{
+		0x5412: true, 0x5125: true, 0x5E7C: true, 0x5B4B: true,
+		0x45F9: true, 0x40CE: true, 0x4F97: true, 0x4AA0: true,
+	}
+
+	code, err := encodeQR([]byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var first, second int
+	bit := func(x, y int) int {
+		if code.modules[y][x] {
+			return 1
+		}
+		return 0
+	}
+	for i := 0; i <= 5; i++ {
+		first |= bit(8, i) << i
+	}
+	first |= bit(8, 7)<<6 | bit(8, 8)<<7 | bit(7, 8)<<8
+	for i := 9; i < 15; i++ {
+		first |= bit(14-i, 8) << i
+	}
+	for i := 0; i < 8; i++ {
+		second |= bit(code.size-1-i, 8) << i
+	}
+	for i := 8; i < 15; i++ {
+		second |= bit(8, code.size-15+i) << i
+	}
+
+	if first != second {
+		t.Errorf("format bits differ: %015b and %015b", first, second)
+	}
+	if !valid[first] {
+		t.Errorf("format bits %015b are not level M", first)
+	}
+	if !code.modules[code.size-8][8] {
+		t.Error("dark module is not set")
+	}
+}
+
+func TestQRCode(t *testing.T) {
+	data, err := QRCode("hello", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("QRCode did not return a PNG: %v", err)
+	}
+	// Version 1 is 21 modules wide, plus the quiet zone on both sides
+	if size := (21 + 2*qrQuietZone) * 2; img.Bounds().Dx() != size || img.Bounds().Dy() != size {
+		t.Errorf("image is %v, want %dx%d", img.Bounds(), size, size)
+	}
+	dark := func(x, y int) bool {
+		r, _, _, _ := img.At(x, y).RGBA()
+		return r == 0
+	}
+	if dark(0, 0) {
+		t.Error("quiet zone is dark")
+	}
+	if corner := qrQuietZone * 2; !dark(corner, corner) || !dark(corner+1, corner+1) {
+		t.Error("finder pattern corner is light")
+	}
+
+	if _, err := QRCode(strings.Repeat("a", 3000), 0); err == nil {
+		t.Error("QRCode of 3000 bytes succeeded, want an error")
+	}
+}
@@ -0,0 +1,268 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+	initialRetryDelay = time.Second
+	maxRetryDelay     = 30 * time.Second
+)
+
+// queuedMessage is a message waiting for delivery
+type queuedMessage struct {
+	payload Webhook
+
+	options sendOptions
+
+	// hash identifies identical payloads when duplicates are collapsed
+	hash string
+	// repeats counts identical payloads merged into this one while it was queued
+	repeats int
+
+	// deliveries are settled with the result of the message; see Enqueue
+	deliveries []*Delivery
+}
+
+// asyncQueue holds the state of a client's background delivery
+type asyncQueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending []*queuedMessage
+	started bool
+	closed  bool
+	done    chan struct{}
+
+	// ctx is cancelled when Close gives up waiting for the queue to drain
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// scheduled are the scheduled sends that have not fired yet
+	scheduled map[*ScheduledSend]struct{}
+
+	maxRetries int
+	onError    func(webhookPayload Webhook, err error)
+
+	// collapse holds the state of duplicate collapsing; see WithDuplicateCollapsing
+	collapse duplicateCollapser
+}
+
+// init prepares an idle queue; its worker starts with the first message
+func (q *asyncQueue) init() {
+	q.done = make(chan struct{})
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+	q.scheduled = make(map[*ScheduledSend]struct{})
+	q.maxRetries = defaultMaxRetries
+}
+
+// WithMaxRetries sets how often queued messages are retried after rate limits,
+// server errors or network failures. It defaults to 3; 0 disables retries.
+func WithMaxRetries(retries int) ClientOption {
+	return func(c *Client) {
+		c.queue.maxRetries = retries
+	}
+}
+
+// WithErrorHandler sets a function called with every queued message that could
+// not be delivered. Without it, failed messages are dropped silently.
+func WithErrorHandler(handler func(webhookPayload Webhook, err error)) ClientOption {
+	return func(c *Client) {
+		c.queue.onError = handler
+	}
+}
+
+// Enqueue queues the payload for delivery in the background and returns immediately.
+// Messages are delivered one at a time, by priority and then in the order they were
+// queued; rate limited, failed and unreachable sends are retried. Call Close to
+// flush the queue before exiting. The returned Delivery reports the outcome, for
+// callers that care about it.
+func (c *Client) Enqueue(webhookPayload Webhook, options ...SendOption) (*Delivery, error) {
+	delivery := newDelivery()
+	message := &queuedMessage{payload: webhookPayload, deliveries: []*Delivery{delivery}}
+	for _, option := range options {
+		option(&message.options)
+	}
+	if !c.pause.allow(message) || !c.quiet.allow(c, message) || !c.throttle.allow(c, message) {
+		return delivery, nil
+	}
+	if err := c.enqueue(message); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// enqueue adds a message to the queue, starting the worker if needed
+func (c *Client) enqueue(message *queuedMessage) error {
+	q := &c.queue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		message.finish(SendResult{Err: ErrClosed})
+		return ErrClosed
+	}
+	if !q.started {
+		q.started = true
+		q.cond = sync.NewCond(&q.mu)
+		go c.work()
+	}
+	// Messages go behind those of the same or a higher priority
+	priority := message.options.effectivePriority()
+	i := len(q.pending)
+	for i > 0 && q.pending[i-1].options.effectivePriority() < priority {
+		i--
+	}
+
+	if q.collapse.enabled && message.options.ttl == 0 {
+		message.hash = message.payload.Hash()
+		if i > 0 && q.pending[i-1].hash == message.hash {
+			q.pending[i-1].repeats++
+			q.pending[i-1].absorb(message)
+			return nil
+		}
+	}
+	q.pending = append(q.pending, nil)
+	copy(q.pending[i+1:], q.pending[i:])
+	q.pending[i] = message
+	q.cond.Signal()
+	return nil
+}
+
+// Close stops accepting messages, cancels scheduled sends that have not fired yet
+// and waits until the queued messages, including those held back by a throttle,
+// quiet hours or a pause, are delivered. If ctx ends first, the remaining messages
+// are abandoned and the context's error is returned.
+func (c *Client) Close(ctx context.Context) error {
+	c.throttle.flushAll(c)
+	c.quiet.flushAll(c)
+	c.pause.flushAll(c)
+
+	q := &c.queue
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	for scheduled := range q.scheduled {
+		scheduled.timer.Stop()
+		delete(q.scheduled, scheduled)
+	}
+	started := q.started
+	if started {
+		q.cond.Broadcast()
+	}
+	q.mu.Unlock()
+
+	if !started {
+		q.cancel()
+		return nil
+	}
+	select {
+	case <-q.done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-q.done
+		return ctx.Err()
+	}
+}
+
+// work delivers queued messages until the client is closed and the queue is empty
+func (c *Client) work() {
+	q := &c.queue
+	defer close(q.done)
+
+	for {
+		q.mu.Lock()
+		for len(q.pending) == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if len(q.pending) == 0 || q.ctx.Err() != nil {
+			abandoned := q.pending
+			q.pending = nil
+			q.mu.Unlock()
+			for _, message := range abandoned {
+				message.finish(SendResult{Err: q.ctx.Err()})
+			}
+			return
+		}
+		message := q.pending[0]
+		q.pending[0] = nil
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+
+		attempts, err := c.deliver(q.ctx, message)
+		if err != nil && q.onError != nil {
+			q.onError(message.payload, err)
+		}
+		message.finish(SendResult{Err: err, Attempts: attempts})
+	}
+}
+
+// deliver sends a queued message, retrying failures that may go away on their own.
+// It returns the number of attempts made.
+func (c *Client) deliver(ctx context.Context, message *queuedMessage) (int, error) {
+	ctx, cancel := c.operationContext(ctx)
+	defer cancel()
+	var previous time.Duration
+	for attempt := 0; ; attempt++ {
+		c.budget.request()
+		err := c.deliverOnce(ctx, message)
+		if err == nil {
+			return attempt + 1, nil
+		}
+
+		delay, retryable := c.retryDelay(err, attempt, previous)
+		if !retryable || attempt >= c.queue.maxRetries || !fitsDeadline(ctx, delay) || !c.budget.allowRetry(c.stats) {
+			return attempt + 1, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt + 1, err
+		}
+		atomic.AddInt64(&c.stats.retried, 1)
+		previous = delay
+	}
+}
+
+// deliverOnce makes a single delivery attempt
+func (c *Client) deliverOnce(ctx context.Context, message *queuedMessage) error {
+	if message.options.ttl > 0 {
+		_, err := c.SendWithTTL(ctx, message.payload, message.options.ttl)
+		return err
+	}
+	if c.queue.collapse.enabled {
+		return c.queue.collapse.deliver(ctx, c, message)
+	}
+	return c.Send(ctx, message.payload)
+}
+
+// retryDelay reports whether a failed send is worth retrying and how long to wait first.
+// Only temporary errors are retried: rate limits wait as long as Discord asks, server
+// and network errors back off as the client's Backoff says, exponentially by default.
+// previous is the wait before the last retry.
+func (c *Client) retryDelay(err error, attempt int, previous time.Duration) (time.Duration, bool) {
+	if !IsTemporary(err) {
+		return 0, false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, true
+	}
+	backoff := c.backoff
+	if backoff == nil {
+		backoff = ExponentialBackoff{}
+	}
+	return backoff.Delay(attempt, previous), true
+}
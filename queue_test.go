@@ -0,0 +1,192 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/webhooktest"
+)
+
+// fastBackoff retries failed sends right away, so tests do not wait out the default backoff
+var fastBackoff = webhook.WithBackoff(webhook.BackoffFunc(func(int, time.Duration) time.Duration {
+	return 10 * time.Millisecond
+}))
+
+// result waits for a queued message to settle
+func result(t *testing.T, delivery *webhook.Delivery) webhook.SendResult {
+	t.Helper()
+	select {
+	case result := <-delivery.Done():
+		return result
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not settled in time")
+		return webhook.SendResult{}
+	}
+}
+
+func contents(server *webhooktest.Server) []string {
+	var contents []string
+	for _, payload := range server.Payloads() {
+		contents = append(contents, payload.Content)
+	}
+	return contents
+}
+
+func TestEnqueueDeliversByPriority(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	// Hold up the first message so the others are queued behind it
+	server.Respond(webhooktest.RateLimited(200*time.Millisecond, false))
+	client := webhook.NewClient(server.URL)
+
+	if _, err := client.Enqueue(webhook.Webhook{Content: "first"}); err != nil {
+		t.Fatal(err)
+	}
+	if !server.WaitForRequests(1, 5*time.Second) {
+		t.Fatal("first message was not sent")
+	}
+	for _, message := range []struct {
+		content  string
+		priority webhook.Priority
+	}{
+		{"low", webhook.PriorityLow},
+		{"normal", webhook.PriorityNormal},
+		{"high", webhook.PriorityHigh},
+		{"second normal", webhook.PriorityNormal},
+	} {
+		if _, err := client.Enqueue(webhook.Webhook{Content: message.content}, webhook.WithPriority(message.priority)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"first", "first", "high", "normal", "second normal", "low"}
+	got := contents(server)
+	if len(got) != len(want) {
+		t.Fatalf("sent %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sent %q, want %q", got, want)
+		}
+	}
+}
+
+func TestEnqueueRetriesTemporaryErrors(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	server.Respond(
+		webhooktest.RateLimited(10*time.Millisecond, false),
+		webhooktest.Error(http.StatusInternalServerError, 0, "500: Internal Server Error"),
+	)
+	client := webhook.NewClient(server.URL, fastBackoff)
+	defer client.Close(context.Background())
+
+	delivery, err := client.Enqueue(webhook.Webhook{Content: "backup finished"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := result(t, delivery); result.Err != nil || result.Attempts != 3 {
+		t.Errorf("result = %+v, want success after 3 attempts", result)
+	}
+	if stats := client.Stats(); stats.Retried != 2 {
+		t.Errorf("Retried = %d, want 2", stats.Retried)
+	}
+}
+
+func TestEnqueueGivesUpAfterMaxRetries(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	failure := webhooktest.Error(http.StatusBadGateway, 0, "502: Bad Gateway")
+	server.Respond(failure, failure, failure)
+
+	var handled error
+	client := webhook.NewClient(server.URL, fastBackoff, webhook.WithMaxRetries(1),
+		webhook.WithErrorHandler(func(_ webhook.Webhook, err error) { handled = err }))
+
+	delivery, err := client.Enqueue(webhook.Webhook{Content: "backup finished"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := result(t, delivery)
+	var apiErr *webhook.APIError
+	if !errors.As(result.Err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway || result.Attempts != 2 {
+		t.Errorf("result = %+v, want a 502 after 2 attempts", result)
+	}
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if handled == nil {
+		t.Error("error handler was not called")
+	}
+	server.AssertRequestCount(t, 2)
+}
+
+func TestEnqueueDoesNotRetryClientErrors(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	server.Respond(webhooktest.Error(http.StatusBadRequest, webhook.ErrorCodeInvalidFormBody, "Invalid Form Body"))
+	client := webhook.NewClient(server.URL, fastBackoff)
+	defer client.Close(context.Background())
+
+	delivery, err := client.Enqueue(webhook.Webhook{Content: "backup finished"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := result(t, delivery); result.Err == nil || result.Attempts != 1 {
+		t.Errorf("result = %+v, want a failure after 1 attempt", result)
+	}
+}
+
+func TestCloseFlushesQueue(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	client := webhook.NewClient(server.URL)
+
+	for i := 0; i < 5; i++ {
+		if _, err := client.Enqueue(webhook.Webhook{Content: "report"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	server.AssertRequestCount(t, 5)
+
+	if _, err := client.Enqueue(webhook.Webhook{Content: "late"}); !errors.Is(err, webhook.ErrClosed) {
+		t.Errorf("Enqueue after Close: error = %v, want ErrClosed", err)
+	}
+}
+
+func TestCloseAbandonsQueueWhenContextEnds(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	server.Respond(webhooktest.RateLimited(time.Minute, false))
+	client := webhook.NewClient(server.URL)
+
+	first, err := client.Enqueue(webhook.Webhook{Content: "first"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.Enqueue(webhook.Webhook{Content: "second"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := client.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Close error = %v, want context.DeadlineExceeded", err)
+	}
+	if result := result(t, first); result.Err == nil {
+		t.Error("first message succeeded, want the rate limit error")
+	}
+	if result := result(t, second); !errors.Is(result.Err, context.Canceled) {
+		t.Errorf("second message error = %v, want context.Canceled", result.Err)
+	}
+}
@@ -0,0 +1,198 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	quietDigestColor     = 0x5865F2
+	quietMaxSummaryRunes = 200
+)
+
+// QuietHours is a daily time window during which low-severity queued messages are
+// held back, such as overnight. Messages at or above MinSeverity still go through.
+type QuietHours struct {
+	// Start and End are times of day as offsets from midnight, such as 22*time.Hour
+	// and 7*time.Hour+30*time.Minute. A window ending before it starts spans midnight.
+	Start, End time.Duration
+
+	// Location is the time zone of Start and End. It defaults to time.Local.
+	Location *time.Location
+
+	// Days, when set, restricts the window to the days it starts on
+	Days []time.Weekday
+
+	// MinSeverity is the lowest severity delivered during the window. It defaults to SeverityCritical.
+	MinSeverity Severity
+
+	// Digest holds back the quiet messages and, once the window ends, sends a single
+	// summary of them. Without it, they are dropped.
+	Digest bool
+}
+
+// quietKeeper holds back queued messages during quiet hours
+type quietKeeper struct {
+	mu      sync.Mutex
+	windows []QuietHours
+	held    []*queuedMessage
+	timer   *time.Timer
+}
+
+// WithQuietHours holds back queued messages below the window's minimum severity,
+// set with WithSeverity, during the window. Messages without a severity count as
+// SeverityInfo. The option may be given several times for several windows.
+func WithQuietHours(window QuietHours) ClientOption {
+	return func(c *Client) {
+		if window.Location == nil {
+			window.Location = time.Local
+		}
+		if window.MinSeverity == 0 {
+			window.MinSeverity = SeverityCritical
+		}
+		c.quiet.windows = append(c.quiet.windows, window)
+	}
+}
+
+// end returns the end of the occurrence of the window containing now, if any
+func (w *QuietHours) end(now time.Time) (time.Time, bool) {
+	now = now.In(w.Location)
+	year, month, day := now.Date()
+	// An occurrence spanning midnight may have started yesterday
+	for _, offset := range []int{-1, 0} {
+		if !w.onDay(time.Date(year, month, day+offset, 0, 0, 0, 0, w.Location).Weekday()) {
+			continue
+		}
+		start := timeOfDay(year, month, day+offset, w.Start, w.Location)
+		end := timeOfDay(year, month, day+offset, w.End, w.Location)
+		if w.End <= w.Start {
+			end = timeOfDay(year, month, day+offset+1, w.End, w.Location)
+		}
+		if !now.Before(start) && now.Before(end) {
+			return end, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// timeOfDay returns the wall clock time offset from midnight on the day. Unlike adding
+// the offset to midnight, it is not shifted on days clocks change.
+func timeOfDay(year int, month time.Month, day int, offset time.Duration, location *time.Location) time.Time {
+	return time.Date(year, month, day, 0, 0, int(offset/time.Second), int(offset%time.Second), location)
+}
+
+// onDay reports whether the window starts on the weekday
+func (w *QuietHours) onDay(weekday time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, day := range w.Days {
+		if day == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// allow reports whether the message may be queued now. Messages held back for a
+// digest are summarized by a timer once the window ends.
+func (q *quietKeeper) allow(c *Client, message *queuedMessage) bool {
+	if len(q.windows) == 0 {
+		return true
+	}
+	severity := message.options.severity.orDefault()
+	now := time.Now()
+	for i := range q.windows {
+		window := &q.windows[i]
+		if severity >= window.MinSeverity {
+			continue
+		}
+		end, quiet := window.end(now)
+		if !quiet {
+			continue
+		}
+		if window.Digest {
+			q.mu.Lock()
+			q.held = append(q.held, message)
+			if q.timer == nil {
+				q.timer = time.AfterFunc(end.Sub(now), func() {
+					q.flush(c)
+				})
+			}
+			q.mu.Unlock()
+		} else {
+			message.finish(SendResult{Suppressed: true})
+		}
+		return false
+	}
+	return true
+}
+
+// flush queues the held back messages, summarized in one message when there are several
+func (q *quietKeeper) flush(c *Client) {
+	q.mu.Lock()
+	held := q.held
+	q.held, q.timer = nil, nil
+	q.mu.Unlock()
+
+	switch len(held) {
+	case 0:
+		return
+	case 1:
+		// Only fails when the client was closed in the meantime
+		_ = c.enqueue(held[0])
+		return
+	}
+	summary := &queuedMessage{payload: quietSummary(held)}
+	for _, message := range held {
+		summary.absorb(message)
+	}
+	_ = c.enqueue(summary)
+}
+
+// flushAll queues the held back messages immediately, so Close delivers them
+func (q *quietKeeper) flushAll(c *Client) {
+	q.mu.Lock()
+	stopped := q.timer != nil && q.timer.Stop()
+	q.mu.Unlock()
+	if stopped {
+		q.flush(c)
+	}
+}
+
+// quietSummary builds the message listing the messages held back during quiet hours
+func quietSummary(held []*queuedMessage) Webhook {
+	var description strings.Builder
+	for i, message := range held {
+		line := fmt.Sprintf("• **%s** %s\n", message.options.severity.orDefault(),
+			truncateRunes(summaryLine(message.payload), quietMaxSummaryRunes))
+		if description.Len()+len(line) > maxEmbedDescriptionLength-20 {
+			fmt.Fprintf(&description, "… and %d more", len(held)-i)
+			break
+		}
+		description.WriteString(line)
+	}
+	return Webhook{Embeds: []Embed{{
+		Title:       fmt.Sprintf("%d messages held during quiet hours", len(held)),
+		Description: strings.TrimSpace(description.String()),
+		Color:       quietDigestColor,
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+	}}}
+}
+
+// summaryLine returns the first line of the content, or of the first embed's title
+// or description, to stand for the message in summaries
+func summaryLine(webhookPayload Webhook) string {
+	candidates := []string{webhookPayload.Content}
+	for _, embed := range webhookPayload.Embeds {
+		candidates = append(candidates, embed.Title, embed.Description)
+	}
+	for _, text := range candidates {
+		if text = strings.TrimSpace(text); text != "" {
+			return strings.TrimSpace(strings.SplitN(text, "\n", 2)[0])
+		}
+	}
+	return "(no content)"
+}
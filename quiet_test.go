@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestQuietHoursEnd(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	at := func(loc *time.Location, month time.Month, day, hour, minute, second int) time.Time {
+		return time.Date(2024, month, day, hour, minute, second, 0, loc)
+	}
+	overnight := QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour, Location: time.UTC}
+	fridayNights := QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour, Location: time.UTC, Days: []time.Weekday{time.Friday}}
+	office := QuietHours{Start: 9 * time.Hour, End: 17 * time.Hour, Location: time.UTC}
+	earlyMorning := QuietHours{Start: time.Hour, End: 3*time.Hour + 30*time.Minute, Location: newYork}
+
+	tests := []struct {
+		name    string
+		window  QuietHours
+		now     time.Time
+		quiet   bool
+		wantEnd time.Time
+	}{
+		{"before an overnight window", overnight, at(time.UTC, time.March, 8, 21, 59, 59), false, time.Time{}},
+		{"start is inclusive", overnight, at(time.UTC, time.March, 8, 22, 0, 0), true, at(time.UTC, time.March, 9, 7, 0, 0)},
+		{"after midnight", overnight, at(time.UTC, time.March, 9, 6, 59, 59), true, at(time.UTC, time.March, 9, 7, 0, 0)},
+		{"end is exclusive", overnight, at(time.UTC, time.March, 9, 7, 0, 0), false, time.Time{}},
+		{"other time zones are converted", overnight, at(time.FixedZone("UTC+2", 2*3600), time.March, 9, 0, 30, 0), true, at(time.UTC, time.March, 9, 7, 0, 0)},
+		{"restricted day continues past midnight", fridayNights, at(time.UTC, time.March, 9, 3, 0, 0), true, at(time.UTC, time.March, 9, 7, 0, 0)},
+		{"restricted day excludes the night before", fridayNights, at(time.UTC, time.March, 8, 3, 0, 0), false, time.Time{}},
+		{"restricted day excludes other nights", fridayNights, at(time.UTC, time.March, 9, 23, 0, 0), false, time.Time{}},
+		{"same day window", office, at(time.UTC, time.March, 8, 16, 59, 0), true, at(time.UTC, time.March, 8, 17, 0, 0)},
+		{"same day window end", office, at(time.UTC, time.March, 8, 17, 0, 0), false, time.Time{}},
+		{"same day window before", office, at(time.UTC, time.March, 8, 8, 59, 0), false, time.Time{}},
+		// Clocks spring forward at 02:00, but the window still ends at 03:30 local time
+		{"spring forward", earlyMorning, at(newYork, time.March, 10, 3, 15, 0), true, at(newYork, time.March, 10, 3, 30, 0)},
+		{"after spring forward", earlyMorning, at(newYork, time.March, 10, 3, 45, 0), false, time.Time{}},
+		// Clocks fall back at 02:00, so the window lasts an hour longer
+		{"fall back", earlyMorning, at(newYork, time.November, 3, 3, 15, 0), true, at(newYork, time.November, 3, 3, 30, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end, quiet := tt.window.end(tt.now)
+			if quiet != tt.quiet || !end.Equal(tt.wantEnd) {
+				t.Errorf("end(%v) = %v, %v; want %v, %v", tt.now, end, quiet, tt.wantEnd, tt.quiet)
+			}
+		})
+	}
+}
+
+func TestQuietHoursSuppressBelowMinSeverity(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := NewClient(server.URL+"/api/webhooks/123/token", WithQuietHours(QuietHours{
+		Start:    0,
+		End:      24 * time.Hour,
+		Location: time.UTC,
+	}))
+
+	info, err := client.Enqueue(Webhook{Content: "nightly report"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	critical, err := client.Enqueue(Webhook{Content: "database down"}, WithSeverity(SeverityCritical))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if result, _ := info.Result(); !result.Suppressed {
+		t.Errorf("info message result = %+v, want it suppressed", result)
+	}
+	if result, settled := critical.Result(); !settled || result.Err != nil || result.Suppressed {
+		t.Errorf("critical message result = %+v, want it delivered", result)
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("got %d requests, want 1", n)
+	}
+}
@@ -0,0 +1,64 @@
+package webhook
+
+import (
+	"net/url"
+	"strings"
+)
+
+// redactedToken replaces webhook tokens in redacted URLs
+const redactedToken = "REDACTED"
+
+// RedactWebhookURL replaces the token of a Discord webhook URL
+// (https://discord.com/api/webhooks/{id}/{token}) so the URL can be logged or shared.
+// URLs that cannot be parsed are fully redacted.
+func RedactWebhookURL(webhookURL string) string {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return redactedToken
+	}
+	if _, hasPassword := u.User.Password(); hasPassword {
+		u.User = url.UserPassword(u.User.Username(), redactedToken)
+	}
+
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		if segment == "webhooks" && i+2 < len(segments) && segments[i+2] != "" {
+			segments[i+2] = redactedToken
+			break
+		}
+	}
+	u.Path = strings.Join(segments, "/")
+	u.RawPath = ""
+	return u.String()
+}
+
+// RedactError returns the error with the tokens of the webhook URLs in its text replaced,
+// such as those quoted by network errors, so it can be logged or shown to others. The
+// result matches the same errors with errors.Is and errors.As as err does.
+func RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+	text := err.Error()
+	redacted := webhookURLPattern.ReplaceAllStringFunc(text, RedactWebhookURL)
+	if redacted == text {
+		return err
+	}
+	return &redactedError{err: err, text: redacted}
+}
+
+// redactedError is an error whose text had webhook tokens replaced; see RedactError
+type redactedError struct {
+	err  error
+	text string
+}
+
+// Error returns the redacted text
+func (e *redactedError) Error() string {
+	return e.text
+}
+
+// Unwrap returns the original error
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
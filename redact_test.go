@@ -0,0 +1,39 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRedactWebhookURL(t *testing.T) {
+	got := RedactWebhookURL("https://discord.com/api/webhooks/123/SECRETTOKEN?wait=true")
+	want := "https://discord.com/api/webhooks/123/REDACTED?wait=true"
+	if got != want {
+		t.Errorf("RedactWebhookURL = %q, want %q", got, want)
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	if RedactError(nil) != nil {
+		t.Error("RedactError(nil) is not nil")
+	}
+	plain := errors.New("boom")
+	if RedactError(plain) != plain {
+		t.Error("errors without webhook URLs should be returned unchanged")
+	}
+
+	err := &NetworkError{Err: fmt.Errorf(`Post "http://127.0.0.1:1/api/webhooks/123/SECRET-token_1?wait=true": dial tcp: connection refused`)}
+	redacted := RedactError(err)
+	if strings.Contains(redacted.Error(), "SECRET") {
+		t.Errorf("token not redacted: %q", redacted)
+	}
+	if !strings.Contains(redacted.Error(), "/api/webhooks/123/REDACTED") {
+		t.Errorf("webhook ID should be kept: %q", redacted)
+	}
+	var netErr *NetworkError
+	if !errors.As(redacted, &netErr) || netErr != err {
+		t.Error("redacted error does not unwrap to the original")
+	}
+}
@@ -0,0 +1,104 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RotationPeriod is how long a ThreadRotator keeps posting into the same thread
+type RotationPeriod int
+
+const (
+	RotateDaily RotationPeriod = iota
+	RotateWeekly
+	RotateMonthly
+)
+
+// ThreadRotator posts into one forum thread per day, week or month, such as
+// "Alerts — 2025-W23", starting the next thread with the first message of each
+// period, so long-running notifiers keep their channel tidy
+type ThreadRotator struct {
+	client *Client
+	prefix string
+	period RotationPeriod
+
+	// Location is the time zone periods start in. It defaults to time.Local.
+	Location *time.Location
+
+	// Store, when set, remembers the thread of each period by name, so restarts
+	// keep posting into the current thread instead of starting another one
+	Store MessageStore
+
+	mu     sync.Mutex
+	name   string
+	thread *Client
+}
+
+// NewThreadRotator creates a rotator starting threads named after the prefix and the period
+func NewThreadRotator(client *Client, prefix string, period RotationPeriod) *ThreadRotator {
+	return &ThreadRotator{client: client, prefix: prefix, period: period, Location: time.Local}
+}
+
+// Send posts the payload into the thread of the current period, starting the thread with it if needed
+func (r *ThreadRotator) Send(ctx context.Context, webhookPayload Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	name := r.ThreadName(time.Now())
+	if name != r.name {
+		r.name, r.thread = name, nil
+		if r.Store != nil {
+			threadID, err := r.Store.Load(name)
+			if err != nil {
+				return fmt.Errorf("failed to load thread %q: %v", name, err)
+			}
+			if threadID != "" {
+				if r.thread, err = r.client.InThread(threadID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if r.thread != nil {
+		err := r.thread.Send(ctx, webhookPayload)
+		if !isNotFound(err) {
+			return err
+		}
+		// The thread was deleted, start it again
+		r.thread = nil
+	}
+
+	thread, err := r.client.StartThread(ctx, name, webhookPayload)
+	if err != nil {
+		return err
+	}
+	r.thread = thread
+	if r.Store != nil {
+		if err := r.Store.Store(name, thread.ThreadID()); err != nil {
+			return fmt.Errorf("failed to store thread %q: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// ThreadName returns the name of the thread for the period containing t, such as "Alerts — 2025-06-02",
+// "Alerts — 2025-W23" or "Alerts — 2025-06"
+func (r *ThreadRotator) ThreadName(t time.Time) string {
+	t = t.In(r.Location)
+	var label string
+	switch r.period {
+	case RotateWeekly:
+		year, week := t.ISOWeek()
+		label = fmt.Sprintf("%d-W%02d", year, week)
+	case RotateMonthly:
+		label = t.Format("2006-01")
+	default:
+		label = t.Format("2006-01-02")
+	}
+	if r.prefix == "" {
+		return label
+	}
+	return r.prefix + " — " + label
+}
@@ -0,0 +1,186 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Route sends the messages matching all of its conditions to a webhook. A route
+// without conditions receives every message.
+type Route struct {
+	URL string
+
+	// MinSeverity and MaxSeverity, when set, bound the severity of matching
+	// messages, set with WithSeverity
+	MinSeverity Severity
+	MaxSeverity Severity
+
+	// Labels, when set, must all be carried by matching messages, set with WithLabels
+	Labels []string
+
+	// Pattern, when set, must match the content or an embed title or description
+	Pattern *regexp.Regexp
+}
+
+// Router fans messages out to the webhooks of every route they match, so one call
+// reaches the right channels, such as errors to #alerts and security events to #sec-alerts:
+//
+//	router := webhook.NewRouter()
+//	router.Add(webhook.Route{URL: notificationsURL, MaxSeverity: webhook.SeverityWarning})
+//	router.Add(webhook.Route{URL: alertsURL, MinSeverity: webhook.SeverityError})
+//	router.Add(webhook.Route{URL: securityURL, Labels: []string{"security"}})
+//	err := router.Send(ctx, payload, webhook.WithSeverity(webhook.SeverityError))
+type Router struct {
+	options []ClientOption
+	routes  []Route
+
+	// Fallback, when set, receives the messages matching no route
+	Fallback string
+
+	mu      sync.Mutex
+	clients map[string]*Client
+}
+
+// NewRouter creates a router whose webhook clients are configured with the options
+func NewRouter(options ...ClientOption) *Router {
+	return &Router{options: options, clients: make(map[string]*Client)}
+}
+
+// Add adds a route. Messages matching several routes are sent to each of them,
+// once per webhook.
+func (r *Router) Add(route Route) error {
+	if route.URL == "" {
+		return fmt.Errorf("route has no webhook URL")
+	}
+	if route.MinSeverity != 0 && route.MaxSeverity != 0 && route.MinSeverity > route.MaxSeverity {
+		return fmt.Errorf("route minimum severity %s is above its maximum %s", route.MinSeverity, route.MaxSeverity)
+	}
+	r.routes = append(r.routes, route)
+	return nil
+}
+
+// Send sends the payload to the webhooks of the matching routes, once each. Every
+// webhook is tried; when any failed, the error is a *BroadcastError reporting each
+// one. Broadcast sends concurrently with retries.
+func (r *Router) Send(ctx context.Context, webhookPayload Webhook, options ...SendOption) error {
+	return r.each(ctx, webhookPayload, options, func(ctx context.Context, client *Client) error {
+		return client.Send(ctx, webhookPayload)
+	})
+}
+
+// Enqueue queues the payload on the clients of the matching routes, like Client.Enqueue
+func (r *Router) Enqueue(webhookPayload Webhook, options ...SendOption) error {
+	return r.each(context.Background(), webhookPayload, options, func(_ context.Context, client *Client) error {
+		_, err := client.Enqueue(webhookPayload, options...)
+		return err
+	})
+}
+
+// Close closes the clients of all routes, flushing their queues
+func (r *Router) Close(ctx context.Context) error {
+	r.mu.Lock()
+	clients := make([]*Client, 0, len(r.clients))
+	for _, client := range r.clients {
+		clients = append(clients, client)
+	}
+	r.mu.Unlock()
+
+	var failed []string
+	for _, client := range clients {
+		if err := client.Close(ctx); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", RedactWebhookURL(client.URL()), err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to close %d of %d webhooks: %s", len(failed), len(clients), strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// Match returns the webhook URLs a message with the payload and options would be sent to
+func (r *Router) Match(webhookPayload Webhook, options ...SendOption) []string {
+	var o sendOptions
+	for _, option := range options {
+		option(&o)
+	}
+
+	var urls []string
+	seen := make(map[string]bool)
+	for i := range r.routes {
+		route := &r.routes[i]
+		if !seen[route.URL] && route.matches(webhookPayload, &o) {
+			seen[route.URL] = true
+			urls = append(urls, route.URL)
+		}
+	}
+	if len(urls) == 0 && r.Fallback != "" {
+		urls = append(urls, r.Fallback)
+	}
+	return urls
+}
+
+// each calls send with the client of every matching webhook, collecting the results
+func (r *Router) each(ctx context.Context, webhookPayload Webhook, options []SendOption, send func(ctx context.Context, client *Client) error) error {
+	urls := r.Match(webhookPayload, options...)
+	results := make([]DestinationResult, len(urls))
+	for i, webhookURL := range urls {
+		client := r.client(webhookURL)
+		recorder := &statusRecorder{client: client}
+		err := send(withStatusRecorder(ctx, recorder), client)
+		results[i] = DestinationResult{URL: RedactWebhookURL(webhookURL), Status: int(atomic.LoadInt32(&recorder.status)), Err: RedactError(err)}
+	}
+	return broadcastError(results)
+}
+
+// client returns the client of a webhook, creating it on first use
+func (r *Router) client(webhookURL string) *Client {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	client, ok := r.clients[webhookURL]
+	if !ok {
+		client = newClient(webhookURL, r.options)
+		publishExpvarOf(client, webhookIDOf(webhookURL))
+		r.clients[webhookURL] = client
+	}
+	return client
+}
+
+// matches reports whether a message satisfies all conditions of the route
+func (route *Route) matches(webhookPayload Webhook, o *sendOptions) bool {
+	severity := o.severity.orDefault()
+	if route.MinSeverity != 0 && severity < route.MinSeverity {
+		return false
+	}
+	if route.MaxSeverity != 0 && severity > route.MaxSeverity {
+		return false
+	}
+	for _, label := range route.Labels {
+		if !hasLabel(o.labels, label) {
+			return false
+		}
+	}
+	if route.Pattern != nil {
+		texts := []string{webhookPayload.Content}
+		for _, embed := range webhookPayload.Embeds {
+			texts = append(texts, embed.Title, embed.Description)
+		}
+		if !route.Pattern.MatchString(strings.Join(texts, "\n")) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasLabel reports whether the labels contain the label
+func hasLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if l == label {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"strings"
+	"unicode"
+)
+
+// WithSanitizer strips invisible Unicode from the text of every message the client
+// sends or edits; see SanitizeText. Use it for channels fed by user input, where
+// bidi overrides and zero-width characters can disguise what an alert says.
+func WithSanitizer() ClientOption {
+	return func(c *Client) {
+		c.sanitize = true
+	}
+}
+
+// SanitizeText removes zero-width characters, bidi controls, other invisible format
+// characters and control characters except newlines and tabs. Zero-width joiners
+// within emoji sequences such as 👩‍💻 are kept.
+func SanitizeText(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	var previous rune
+	for _, r := range text {
+		if invisibleRune(r) && !(r == '\u200D' && unicode.Is(unicode.So, previous)) {
+			continue
+		}
+		b.WriteRune(r)
+		previous = r
+	}
+	return b.String()
+}
+
+// invisibleRune reports whether the rune renders as nothing or changes the direction of the text around it
+func invisibleRune(r rune) bool {
+	switch {
+	case r == '\n' || r == '\t':
+		return false
+	case unicode.Is(unicode.Cc, r):
+		return true
+	case r == '\u00AD', r == '\u034F', r == '\u061C', r == '\u115F', r == '\u1160',
+		r == '\u180E', r == '\u3164', r == '\uFEFF', r == '\uFFA0':
+		// Soft hyphen, grapheme joiner, Arabic letter mark, Hangul fillers, Mongolian vowel separator, byte order mark
+		return true
+	case r >= '\u200B' && r <= '\u200F', r >= '\u202A' && r <= '\u202E', r >= '\u2060' && r <= '\u206F':
+		// Zero-width spaces and joiners, direction marks, embeddings, overrides and isolates
+		return true
+	case r >= '\U000E0000' && r <= '\U000E007F':
+		// Tag characters
+		return true
+	}
+	return false
+}
+
+// SanitizeWebhook applies SanitizeText to all text of the payload that Discord displays
+func SanitizeWebhook(webhookPayload Webhook) Webhook {
+	return mapText(webhookPayload, SanitizeText)
+}
+
+// mapText returns a copy of the payload with f applied to its content, username, thread
+// name, embed texts and button labels. URLs are left alone.
+func mapText(webhookPayload Webhook, f func(string) string) Webhook {
+	webhookPayload.Content = f(webhookPayload.Content)
+	webhookPayload.Username = f(webhookPayload.Username)
+	webhookPayload.ThreadName = f(webhookPayload.ThreadName)
+
+	if webhookPayload.Embeds != nil {
+		embeds := make([]Embed, len(webhookPayload.Embeds))
+		for i, embed := range webhookPayload.Embeds {
+			embed.Title = f(embed.Title)
+			embed.Description = f(embed.Description)
+			embed.Footer.Text = f(embed.Footer.Text)
+			embed.Author.Name = f(embed.Author.Name)
+			if embed.Fields != nil {
+				fields := make([]Field, len(embed.Fields))
+				for j, field := range embed.Fields {
+					field.Name = f(field.Name)
+					field.Value = f(field.Value)
+					fields[j] = field
+				}
+				embed.Fields = fields
+			}
+			embeds[i] = embed
+		}
+		webhookPayload.Embeds = embeds
+	}
+	webhookPayload.Components = mapComponentText(webhookPayload.Components, f)
+	return webhookPayload
+}
+
+// mapComponentText returns a copy of the components with f applied to their labels
+func mapComponentText(components []Component, f func(string) string) []Component {
+	if components == nil {
+		return nil
+	}
+	mapped := make([]Component, len(components))
+	for i, component := range components {
+		component.Label = f(component.Label)
+		component.Placeholder = f(component.Placeholder)
+		if component.Options != nil {
+			options := make([]SelectOption, len(component.Options))
+			for j, option := range component.Options {
+				option.Label, option.Description = f(option.Label), f(option.Description)
+				options[j] = option
+			}
+			component.Options = options
+		}
+		component.Components = mapComponentText(component.Components, f)
+		mapped[i] = component
+	}
+	return mapped
+}
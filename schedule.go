@@ -0,0 +1,60 @@
+package webhook
+
+import "time"
+
+// ScheduledSend is a message waiting to be queued at a later time
+type ScheduledSend struct {
+	at     time.Time
+	timer  *time.Timer
+	client *Client
+}
+
+// Schedule queues the payload for delivery at the given time, like Enqueue.
+// Times in the past queue the message immediately. The returned handle can
+// cancel the send until it fires.
+func (c *Client) Schedule(at time.Time, webhookPayload Webhook) (*ScheduledSend, error) {
+	return c.SendAfter(time.Until(at), webhookPayload)
+}
+
+// SendAfter queues the payload for delivery once the delay has passed, like Enqueue
+func (c *Client) SendAfter(delay time.Duration, webhookPayload Webhook) (*ScheduledSend, error) {
+	q := &c.queue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return nil, ErrClosed
+	}
+
+	scheduled := &ScheduledSend{at: time.Now().Add(delay), client: c}
+	scheduled.timer = time.AfterFunc(delay, func() {
+		q.mu.Lock()
+		_, pending := q.scheduled[scheduled]
+		delete(q.scheduled, scheduled)
+		q.mu.Unlock()
+		if pending {
+			// Only fails when the client was closed in the meantime
+			_, _ = c.Enqueue(webhookPayload)
+		}
+	})
+	q.scheduled[scheduled] = struct{}{}
+	return scheduled, nil
+}
+
+// At returns the time the message is due
+func (s *ScheduledSend) At() time.Time {
+	return s.at
+}
+
+// Cancel prevents the send. It reports whether the message was still pending,
+// false meaning it was already queued, cancelled or dropped by Close.
+func (s *ScheduledSend) Cancel() bool {
+	q := &s.client.queue
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, pending := q.scheduled[s]; !pending {
+		return false
+	}
+	s.timer.Stop()
+	delete(q.scheduled, s)
+	return true
+}
@@ -0,0 +1,236 @@
+package webhook
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// jsonSchema is the JSON Schema of the payload format, maintained next to the types it describes
+//
+//go:embed webhook.schema.json
+var jsonSchema []byte
+
+// JSONSchema returns the JSON Schema (draft 2020-12) describing webhook payloads,
+// including this package's extensions such as components. Point editors at it
+// with a "$schema" key to get completion and inline validation of payload files.
+func JSONSchema() []byte {
+	return append([]byte(nil), jsonSchema...)
+}
+
+// schema is the subset of JSON Schema used by webhook.schema.json
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 string             `json:"type"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties *bool              `json:"additionalProperties"`
+	Required             []string           `json:"required"`
+	Items                *schema            `json:"items"`
+	MinLength            *int               `json:"minLength"`
+	MaxLength            *int               `json:"maxLength"`
+	MinItems             *int               `json:"minItems"`
+	MaxItems             *int               `json:"maxItems"`
+	Minimum              *json.Number       `json:"minimum"`
+	Maximum              *json.Number       `json:"maximum"`
+	Enum                 []any              `json:"enum"`
+	Const                any                `json:"const"`
+	AnyOf                []*schema          `json:"anyOf"`
+	Defs                 map[string]*schema `json:"$defs"`
+}
+
+// payloadSchema is the parsed form of jsonSchema
+var payloadSchema = func() *schema {
+	decoder := json.NewDecoder(bytes.NewReader(jsonSchema))
+	decoder.UseNumber()
+	var s schema
+	if err := decoder.Decode(&s); err != nil {
+		panic(fmt.Sprintf("webhook: invalid embedded schema: %v", err))
+	}
+	return &s
+}()
+
+// ValidateWebhookJSON checks a JSON payload against the schema, reporting unknown keys
+// and mistyped values, and then against Discord's limits like ValidateWebhook.
+// Every problem is reported at once as a *ValidationError.
+func ValidateWebhookJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return fmt.Errorf("failed to decode JSON payload: %v", err)
+	}
+
+	v := &validator{}
+	v.schema("", document, payloadSchema)
+	if len(v.violations) > 0 {
+		return &ValidationError{Violations: v.violations}
+	}
+
+	var webhook Webhook
+	if err := json.Unmarshal(data, &webhook); err != nil {
+		return fmt.Errorf("failed to decode JSON payload: %v", err)
+	}
+	return ValidateWebhook(webhook)
+}
+
+// schema records the violations of value against s
+func (v *validator) schema(path string, value any, s *schema) {
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/$defs/")
+		s = payloadSchema.Defs[name]
+	}
+
+	if len(s.AnyOf) > 0 {
+		// Report the violations of the alternative the value comes closest to
+		var closest []Violation
+		for i, alternative := range s.AnyOf {
+			sub := &validator{}
+			sub.schema(path, value, alternative)
+			if len(sub.violations) == 0 {
+				closest = nil
+				break
+			}
+			if i == 0 || len(sub.violations) < len(closest) {
+				closest = sub.violations
+			}
+		}
+		v.violations = append(v.violations, closest...)
+		if len(closest) > 0 {
+			return
+		}
+	}
+	if s.Const != nil && !sameValue(value, s.Const) {
+		v.addf(path, "must be %v", s.Const)
+		return
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, option := range s.Enum {
+			found = found || sameValue(value, option)
+		}
+		if !found {
+			v.addf(path, "%v is not one of %v", value, s.Enum)
+			return
+		}
+	}
+	if s.Type != "" && !hasSchemaType(value, s.Type) {
+		v.addf(path, "must be of type %s", s.Type)
+		return
+	}
+
+	switch value := value.(type) {
+	case map[string]any:
+		v.schemaObject(path, value, s)
+	case []any:
+		if s.MinItems != nil && len(value) < *s.MinItems {
+			v.addf(path, "needs at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(value) > *s.MaxItems {
+			v.addf(path, "%d items exceed the limit of %d", len(value), *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range value {
+				v.schema(fmt.Sprintf("%s[%d]", path, i), item, s.Items)
+			}
+		}
+	case string:
+		n := utf8.RuneCountInString(value)
+		if s.MinLength != nil && n < *s.MinLength {
+			v.addf(path, "cannot be empty")
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			v.addf(path, "length %d exceeds the limit of %d characters", n, *s.MaxLength)
+		}
+	case json.Number:
+		n, _ := value.Float64()
+		if s.Minimum != nil {
+			if minimum, _ := s.Minimum.Float64(); n < minimum {
+				v.addf(path, "%s is below the minimum of %s", value, *s.Minimum)
+			}
+		}
+		if s.Maximum != nil {
+			if maximum, _ := s.Maximum.Float64(); n > maximum {
+				v.addf(path, "%s exceeds the maximum of %s", value, *s.Maximum)
+			}
+		}
+	}
+}
+
+// schemaObject records the violations of an object's properties
+func (v *validator) schemaObject(path string, value map[string]any, s *schema) {
+	for _, key := range s.Required {
+		if _, ok := value[key]; !ok {
+			v.addf(joinPath(path, key), "is required")
+		}
+	}
+
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		property, ok := s.Properties[key]
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				v.addf(joinPath(path, key), "unknown property")
+			}
+			continue
+		}
+		v.schema(joinPath(path, key), value[key], property)
+	}
+}
+
+// hasSchemaType reports whether a decoded JSON value has the JSON Schema type
+func hasSchemaType(value any, schemaType string) bool {
+	switch value := value.(type) {
+	case map[string]any:
+		return schemaType == "object"
+	case []any:
+		return schemaType == "array"
+	case string:
+		return schemaType == "string"
+	case bool:
+		return schemaType == "boolean"
+	case json.Number:
+		if schemaType == "number" {
+			return true
+		}
+		_, err := value.Int64()
+		return schemaType == "integer" && err == nil
+	case nil:
+		return schemaType == "null"
+	}
+	return false
+}
+
+// sameValue reports whether two decoded JSON scalars are equal, comparing numbers by value
+func sameValue(value, want any) bool {
+	a, aIsNumber := value.(json.Number)
+	b, bIsNumber := want.(json.Number)
+	if aIsNumber && bIsNumber {
+		x, errX := a.Float64()
+		y, errY := b.Float64()
+		return errX == nil && errY == nil && x == y
+	}
+	if aIsNumber || bIsNumber {
+		return false
+	}
+	switch value.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	return value == want
+}
+
+// joinPath appends a property to a violation path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,60 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity ranks how important a message is. The zero value means no severity was
+// set and is treated as SeverityInfo.
+type Severity int
+
+const (
+	SeverityDebug Severity = iota + 1
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+// severityNames are the names accepted by ParseSeverity, indexed by severity
+var severityNames = []string{"", "debug", "info", "warning", "error", "critical"}
+
+// String returns the lowercase name of the severity
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return fmt.Sprintf("severity(%d)", int(s))
+	}
+	if s == 0 {
+		return "info"
+	}
+	return severityNames[s]
+}
+
+// ParseSeverity parses a severity name such as "warning" or "ERROR". "warn",
+// "err" and "crit" are accepted as well.
+func ParseSeverity(name string) (Severity, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	switch name {
+	case "warn":
+		return SeverityWarning, nil
+	case "err":
+		return SeverityError, nil
+	case "crit", "fatal":
+		return SeverityCritical, nil
+	}
+	for i, known := range severityNames {
+		if i > 0 && name == known {
+			return Severity(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown severity %q", name)
+}
+
+// orDefault returns the severity, or SeverityInfo when it is not set
+func (s Severity) orDefault() Severity {
+	if s == 0 {
+		return SeverityInfo
+	}
+	return s
+}
@@ -0,0 +1,478 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Colors Slack uses for its named attachment colors
+const (
+	slackColorGood    = 0x2EB886
+	slackColorWarning = 0xDAA038
+	slackColorDanger  = 0xA30200
+)
+
+// SlackMessage represents a Slack incoming webhook payload
+type SlackMessage struct {
+	Text        string            `json:"text,omitempty"`
+	Username    string            `json:"username,omitempty"`
+	IconURL     string            `json:"icon_url,omitempty"`
+	IconEmoji   string            `json:"icon_emoji,omitempty"`
+	Blocks      []SlackBlock      `json:"blocks,omitempty"`
+	Attachments []SlackAttachment `json:"attachments,omitempty"`
+}
+
+// SlackAttachment represents a legacy Slack message attachment
+type SlackAttachment struct {
+	Fallback   string       `json:"fallback,omitempty"`
+	Color      string       `json:"color,omitempty"`
+	Pretext    string       `json:"pretext,omitempty"`
+	AuthorName string       `json:"author_name,omitempty"`
+	AuthorLink string       `json:"author_link,omitempty"`
+	AuthorIcon string       `json:"author_icon,omitempty"`
+	Title      string       `json:"title,omitempty"`
+	TitleLink  string       `json:"title_link,omitempty"`
+	Text       string       `json:"text,omitempty"`
+	Fields     []SlackField `json:"fields,omitempty"`
+	ImageURL   string       `json:"image_url,omitempty"`
+	ThumbURL   string       `json:"thumb_url,omitempty"`
+	Footer     string       `json:"footer,omitempty"`
+	FooterIcon string       `json:"footer_icon,omitempty"`
+	Timestamp  json.Number  `json:"ts,omitempty"`
+	Blocks     []SlackBlock `json:"blocks,omitempty"`
+}
+
+// SlackField represents a field of a legacy Slack attachment
+type SlackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short,omitempty"`
+}
+
+// SlackBlock represents a Slack Block Kit layout block
+type SlackBlock struct {
+	Type      string         `json:"type"`
+	Text      *SlackText     `json:"text,omitempty"`
+	Fields    []SlackText    `json:"fields,omitempty"`
+	Elements  []SlackElement `json:"elements,omitempty"`
+	Accessory *SlackElement  `json:"accessory,omitempty"`
+	ImageURL  string         `json:"image_url,omitempty"`
+	AltText   string         `json:"alt_text,omitempty"`
+	Title     *SlackText     `json:"title,omitempty"`
+}
+
+// SlackElement represents a Block Kit element (text, image, button or rich text node)
+type SlackElement struct {
+	Type     string         `json:"type"`
+	Text     *SlackText     `json:"text,omitempty"`
+	URL      string         `json:"url,omitempty"`
+	ImageURL string         `json:"image_url,omitempty"`
+	AltText  string         `json:"alt_text,omitempty"`
+	Name     string         `json:"name,omitempty"`
+	UserID   string         `json:"user_id,omitempty"`
+	Style    map[string]any `json:"style,omitempty"`
+	Elements []SlackElement `json:"elements,omitempty"`
+}
+
+// SlackText represents a Block Kit text object. Slack uses both plain strings and
+// {"type": ..., "text": ...} objects for text, so both forms are accepted when decoding.
+type SlackText struct {
+	Type string `json:"type,omitempty"`
+	Text string `json:"text"`
+}
+
+// UnmarshalJSON decodes a text object from either a JSON string or a text object
+func (t *SlackText) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = SlackText{Text: s}
+		return nil
+	}
+	type plain SlackText
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*t = SlackText(p)
+	return nil
+}
+
+var (
+	slackLinkPattern   = regexp.MustCompile(`<([^<>|]+)(?:\|([^<>]*))?>`)
+	slackBoldPattern   = regexp.MustCompile(`\*([^*\n]+)\*`)
+	slackStrikePattern = regexp.MustCompile(`~([^~\n]+)~`)
+)
+
+// ParseSlackMessage decodes a Slack webhook JSON payload and converts it into a Webhook
+func ParseSlackMessage(data []byte) (Webhook, error) {
+	var msg SlackMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return Webhook{}, fmt.Errorf("failed to parse Slack payload: %v", err)
+	}
+	return ConvertSlackMessage(msg), nil
+}
+
+// ConvertSlackMessage converts a Slack message into a Webhook on a best-effort basis.
+// Blocks are rendered into a single embed, each attachment becomes its own embed and
+// Slack mrkdwn is translated into Discord markdown. Elements without a Discord
+// equivalent (interactive inputs, icon emoji) are dropped.
+func ConvertSlackMessage(msg SlackMessage) Webhook {
+	webhook := Webhook{
+		Username:  msg.Username,
+		AvatarURL: msg.IconURL,
+	}
+
+	// Slack only displays text as a notification fallback when blocks are present
+	if len(msg.Blocks) == 0 {
+		webhook.Content = convertSlackMrkdwn(msg.Text)
+	}
+
+	if len(msg.Blocks) > 0 {
+		if embed, ok := convertSlackBlocks(msg.Blocks); ok {
+			webhook.AddEmbed(embed)
+		}
+	}
+
+	var pretexts []string
+	for _, attachment := range msg.Attachments {
+		if attachment.Pretext != "" {
+			pretexts = append(pretexts, convertSlackMrkdwn(attachment.Pretext))
+		}
+		webhook.AddEmbed(convertSlackAttachment(attachment))
+	}
+	if len(pretexts) > 0 {
+		webhook.Content = strings.TrimSpace(webhook.Content + "\n" + strings.Join(pretexts, "\n"))
+	}
+
+	return webhook
+}
+
+// convertSlackAttachment converts a legacy attachment into an embed
+func convertSlackAttachment(attachment SlackAttachment) Embed {
+	embed := Embed{
+		Title:       attachment.Title,
+		URL:         attachment.TitleLink,
+		Description: convertSlackMrkdwn(attachment.Text),
+		Color:       slackColor(attachment.Color),
+	}
+	if embed.Title == "" && embed.Description == "" && len(attachment.Blocks) == 0 {
+		embed.Description = convertSlackMrkdwn(attachment.Fallback)
+	}
+
+	if len(attachment.Blocks) > 0 {
+		if blocks, ok := convertSlackBlocks(attachment.Blocks); ok {
+			if embed.Title == "" {
+				embed.Title = blocks.Title
+			}
+			embed.Description = joinNonEmpty("\n", embed.Description, blocks.Description)
+			embed.Fields = append(embed.Fields, blocks.Fields...)
+			embed.Image = blocks.Image
+			embed.Thumbnail = blocks.Thumbnail
+			embed.Footer = blocks.Footer
+		}
+	}
+
+	for _, field := range attachment.Fields {
+		embed.AddField(CreateField(field.Title, convertSlackMrkdwn(field.Value), field.Short))
+	}
+
+	if attachment.AuthorName != "" {
+		embed.SetAuthor(CreateAuthor(attachment.AuthorName, attachment.AuthorLink, attachment.AuthorIcon, ""))
+	}
+	if attachment.ImageURL != "" {
+		embed.SetImage(Image{URL: attachment.ImageURL})
+	}
+	if attachment.ThumbURL != "" {
+		embed.SetThumbnail(Thumbnail{URL: attachment.ThumbURL})
+	}
+	if attachment.Footer != "" {
+		embed.SetFooter(Footer{Text: attachment.Footer, IconURL: attachment.FooterIcon})
+	}
+	if attachment.Timestamp != "" {
+		if ts, err := attachment.Timestamp.Float64(); err == nil {
+			embed.Timestamp = time.Unix(int64(ts), 0).UTC().Format(time.RFC3339)
+		}
+	}
+
+	return embed
+}
+
+// convertSlackBlocks renders a list of blocks into a single embed.
+// It reports false when none of the blocks produced visible content.
+func convertSlackBlocks(blocks []SlackBlock) (Embed, bool) {
+	var embed Embed
+	var description []string
+	var footer []string
+
+	for _, block := range blocks {
+		switch block.Type {
+		case "header":
+			if block.Text == nil {
+				continue
+			}
+			if embed.Title == "" {
+				embed.Title = block.Text.Text
+			} else {
+				description = append(description, "**"+block.Text.Text+"**")
+			}
+		case "section":
+			if block.Text != nil {
+				description = append(description, convertSlackText(*block.Text))
+			}
+			for _, field := range block.Fields {
+				name, value := splitSlackField(convertSlackText(field))
+				embed.AddField(CreateField(name, value, true))
+			}
+			if block.Accessory != nil {
+				switch block.Accessory.Type {
+				case "image":
+					embed.SetThumbnail(Thumbnail{URL: block.Accessory.ImageURL})
+				case "button":
+					if link := slackButtonLink(*block.Accessory); link != "" {
+						description = append(description, link)
+					}
+				}
+			}
+		case "divider":
+			description = append(description, "")
+		case "context":
+			for _, element := range block.Elements {
+				if element.Type == "image" {
+					if embed.Footer.IconURL == "" {
+						embed.Footer.IconURL = element.ImageURL
+					}
+					continue
+				}
+				if element.Text != nil {
+					footer = append(footer, stripSlackMarkup(element.Text.Text))
+				}
+			}
+		case "image":
+			if embed.Image.URL == "" {
+				embed.SetImage(Image{URL: block.ImageURL})
+			} else {
+				description = append(description, fmt.Sprintf("[%s](%s)", slackImageLabel(block), block.ImageURL))
+			}
+		case "actions":
+			var links []string
+			for _, element := range block.Elements {
+				if link := slackButtonLink(element); link != "" {
+					links = append(links, link)
+				}
+			}
+			if len(links) > 0 {
+				description = append(description, strings.Join(links, " • "))
+			}
+		case "rich_text":
+			var b strings.Builder
+			writeSlackRichText(&b, block.Elements)
+			description = append(description, strings.TrimRight(b.String(), "\n"))
+		}
+	}
+
+	embed.Description = strings.TrimSpace(strings.Join(description, "\n"))
+	embed.Footer.Text = strings.Join(footer, " | ")
+
+	ok := embed.Title != "" || embed.Description != "" || len(embed.Fields) > 0 ||
+		embed.Image.URL != "" || embed.Thumbnail.URL != "" || embed.Footer.Text != ""
+	return embed, ok
+}
+
+// writeSlackRichText flattens rich text elements into Discord markdown
+func writeSlackRichText(b *strings.Builder, elements []SlackElement) {
+	for _, element := range elements {
+		switch element.Type {
+		case "rich_text_section":
+			writeSlackRichText(b, element.Elements)
+			b.WriteString("\n")
+		case "rich_text_list":
+			for _, item := range element.Elements {
+				b.WriteString("- ")
+				writeSlackRichText(b, item.Elements)
+				b.WriteString("\n")
+			}
+		case "rich_text_quote":
+			var quote strings.Builder
+			writeSlackRichText(&quote, element.Elements)
+			for _, line := range strings.Split(strings.TrimRight(quote.String(), "\n"), "\n") {
+				b.WriteString("> " + line + "\n")
+			}
+		case "rich_text_preformatted":
+			b.WriteString("```\n")
+			writeSlackRichText(b, element.Elements)
+			b.WriteString("\n```\n")
+		case "text":
+			if element.Text != nil {
+				b.WriteString(styleSlackText(element.Text.Text, element.Style))
+			}
+		case "link":
+			label := element.URL
+			if element.Text != nil && element.Text.Text != "" {
+				label = element.Text.Text
+			}
+			b.WriteString(fmt.Sprintf("[%s](%s)", label, element.URL))
+		case "emoji":
+			b.WriteString(":" + element.Name + ":")
+		case "user":
+			b.WriteString("@" + element.UserID)
+		}
+	}
+}
+
+// styleSlackText applies rich text styles as Discord markdown
+func styleSlackText(text string, style map[string]any) string {
+	if len(style) == 0 || strings.TrimSpace(text) == "" {
+		return text
+	}
+	if style["code"] == true {
+		return "`" + text + "`"
+	}
+	if style["bold"] == true {
+		text = "**" + text + "**"
+	}
+	if style["italic"] == true {
+		text = "*" + text + "*"
+	}
+	if style["strike"] == true {
+		text = "~~" + text + "~~"
+	}
+	return text
+}
+
+// slackButtonLink renders a URL button as a markdown link, or returns an empty string
+// for buttons that rely on Slack interactivity
+func slackButtonLink(element SlackElement) string {
+	if element.Type != "button" || element.URL == "" {
+		return ""
+	}
+	label := element.URL
+	if element.Text != nil && element.Text.Text != "" {
+		label = element.Text.Text
+	}
+	return fmt.Sprintf("[%s](%s)", label, element.URL)
+}
+
+// slackImageLabel returns the best available label for an image block
+func slackImageLabel(block SlackBlock) string {
+	if block.Title != nil && block.Title.Text != "" {
+		return block.Title.Text
+	}
+	if block.AltText != "" {
+		return block.AltText
+	}
+	return "image"
+}
+
+// splitSlackField splits a "*Name*\nValue" section field into an embed field name and value
+func splitSlackField(text string) (string, string) {
+	name, value, found := strings.Cut(text, "\n")
+	if !found {
+		return zeroWidthSpace, text
+	}
+	return strings.Trim(name, "*_ "), value
+}
+
+// convertSlackText converts a text object according to its type
+func convertSlackText(text SlackText) string {
+	if text.Type == "plain_text" {
+		return text.Text
+	}
+	return convertSlackMrkdwn(text.Text)
+}
+
+// convertSlackMrkdwn translates Slack mrkdwn into Discord markdown.
+// Code spans and code blocks are left untouched.
+func convertSlackMrkdwn(text string) string {
+	if text == "" {
+		return ""
+	}
+
+	segments := strings.Split(text, "`")
+	for i := range segments {
+		// Odd segments are inside code spans or blocks
+		if i%2 == 1 {
+			continue
+		}
+		s := slackLinkPattern.ReplaceAllStringFunc(segments[i], convertSlackLink)
+		s = slackBoldPattern.ReplaceAllString(s, "**$1**")
+		s = slackStrikePattern.ReplaceAllString(s, "~~$1~~")
+		segments[i] = s
+	}
+
+	return html.UnescapeString(strings.Join(segments, "`"))
+}
+
+// stripSlackMarkup converts links and entities but drops formatting characters,
+// for places such as footers where Discord does not render markdown
+func stripSlackMarkup(text string) string {
+	text = slackLinkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		parts := slackLinkPattern.FindStringSubmatch(match)
+		if parts[2] != "" {
+			return parts[2]
+		}
+		return strings.TrimLeft(parts[1], "@#!")
+	})
+	text = strings.NewReplacer("*", "", "~", "", "`", "").Replace(text)
+	return html.UnescapeString(text)
+}
+
+// convertSlackLink converts a single <target|label> sequence
+func convertSlackLink(match string) string {
+	parts := slackLinkPattern.FindStringSubmatch(match)
+	target, label := parts[1], parts[2]
+
+	switch {
+	case strings.HasPrefix(target, "@"):
+		if label != "" {
+			return "@" + label
+		}
+		return target
+	case strings.HasPrefix(target, "#"):
+		if label != "" {
+			return "#" + label
+		}
+		return target
+	case strings.HasPrefix(target, "!"):
+		switch command := strings.TrimPrefix(target, "!"); {
+		case command == "here", command == "channel", command == "everyone":
+			return "@" + command
+		case label != "":
+			return label
+		default:
+			return command
+		}
+	case label != "":
+		return fmt.Sprintf("[%s](%s)", label, target)
+	default:
+		return target
+	}
+}
+
+// slackColor converts a Slack attachment color (named or hex) into an embed color.
+// Unknown colors are ignored.
+func slackColor(color string) int {
+	switch color {
+	case "good":
+		return slackColorGood
+	case "warning":
+		return slackColorWarning
+	case "danger":
+		return slackColorDanger
+	}
+	return lenientHexColor(color)
+}
+
+// joinNonEmpty joins the non-empty strings with the separator
+func joinNonEmpty(sep string, values ...string) string {
+	var parts []string
+	for _, v := range values {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, sep)
+}
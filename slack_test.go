@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConvertSlackMrkdwn(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"*deploy* finished", "**deploy** finished"},
+		{"~cancelled~", "~~cancelled~~"},
+		{"_italic_ stays", "_italic_ stays"},
+		{"see <https://example.com|the logs>", "see [the logs](https://example.com)"},
+		{"<https://example.com>", "https://example.com"},
+		{"ping <@U123|alice> in <#C456|ops>", "ping @alice in #ops"},
+		{"<!here> <!channel> <!subteam^S1|@oncall>", "@here @channel @oncall"},
+		{"`*not bold*` but *bold*", "`*not bold*` but **bold**"},
+		{"a &lt; b &amp;&amp; c &gt; d", "a < b && c > d"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := convertSlackMrkdwn(tt.in); got != tt.want {
+			t.Errorf("convertSlackMrkdwn(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseSlackMessageBlocks(t *testing.T) {
+	got, err := ParseSlackMessage([]byte(`{
+		"text": "fallback only",
+		"username": "ci",
+		"icon_url": "https://example.com/ci.png",
+		"blocks": [
+			{"type": "header", "text": {"type": "plain_text", "text": "Deploy finished"}},
+			{"type": "section", "text": {"type": "mrkdwn", "text": "*api* is live"},
+				"fields": [{"type": "mrkdwn", "text": "*Version*\nv1.2.3"}, {"type": "mrkdwn", "text": "*Region*\neu-west-1"}],
+				"accessory": {"type": "image", "image_url": "https://example.com/thumb.png", "alt_text": "logo"}},
+			{"type": "divider"},
+			{"type": "actions", "elements": [
+				{"type": "button", "text": {"type": "plain_text", "text": "Open"}, "url": "https://example.com/run/1"},
+				{"type": "button", "text": {"type": "plain_text", "text": "Approve"}, "action_id": "approve"}
+			]},
+			{"type": "image", "image_url": "https://example.com/chart.png", "alt_text": "chart"},
+			{"type": "context", "elements": [
+				{"type": "image", "image_url": "https://example.com/icon.png", "alt_text": "icon"},
+				{"type": "mrkdwn", "text": "by *alice*"}
+			]}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Webhook{
+		Username:  "ci",
+		AvatarURL: "https://example.com/ci.png",
+		Embeds: []Embed{{
+			Title:       "Deploy finished",
+			Description: "**api** is live\n\n[Open](https://example.com/run/1)",
+			Fields: []Field{
+				{Name: "Version", Value: "v1.2.3", Inline: true},
+				{Name: "Region", Value: "eu-west-1", Inline: true},
+			},
+			Thumbnail: Thumbnail{URL: "https://example.com/thumb.png"},
+			Image:     Image{URL: "https://example.com/chart.png"},
+			Footer:    Footer{Text: "by alice", IconURL: "https://example.com/icon.png"},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSlackMessage =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestConvertSlackMessageAttachments(t *testing.T) {
+	got := ConvertSlackMessage(SlackMessage{
+		Text: "Build *failed*",
+		Attachments: []SlackAttachment{
+			{
+				Color:      "danger",
+				Pretext:    "see <https://ci.example.com|CI>",
+				AuthorName: "ci",
+				Title:      "build #42",
+				TitleLink:  "https://ci.example.com/42",
+				Text:       "step `test` failed",
+				Fields:     []SlackField{{Title: "Branch", Value: "main", Short: true}},
+				Footer:     "ci",
+				Timestamp:  "1700000000",
+			},
+			{Color: "#36a64f", Fallback: "all green"},
+		},
+	})
+
+	if want := "Build **failed**\nsee [CI](https://ci.example.com)"; got.Content != want {
+		t.Errorf("content = %q, want %q", got.Content, want)
+	}
+	if len(got.Embeds) != 2 {
+		t.Fatalf("got %d embeds, want 2", len(got.Embeds))
+	}
+	want := Embed{
+		Title:       "build #42",
+		URL:         "https://ci.example.com/42",
+		Description: "step `test` failed",
+		Color:       slackColorDanger,
+		Fields:      []Field{{Name: "Branch", Value: "main", Inline: true}},
+		Author:      Author{Name: "ci"},
+		Footer:      Footer{Text: "ci"},
+		Timestamp:   "2023-11-14T22:13:20Z",
+	}
+	if !reflect.DeepEqual(got.Embeds[0], want) {
+		t.Errorf("first embed =\n%+v\nwant\n%+v", got.Embeds[0], want)
+	}
+	if embed := got.Embeds[1]; embed.Description != "all green" || embed.Color != 0x36A64F {
+		t.Errorf("second embed = %+v, want the fallback text in green", embed)
+	}
+}
+
+func TestParseSlackMessageRejectsInvalidJSON(t *testing.T) {
+	if _, err := ParseSlackMessage([]byte(`{"text": `)); err == nil {
+		t.Error("ParseSlackMessage succeeded, want an error")
+	}
+}
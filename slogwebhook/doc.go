@@ -0,0 +1,16 @@
+// Package slogwebhook is a log/slog handler posting log records to Discord webhooks,
+// routed by level, such as info records to #activity and errors to #alerts:
+//
+//	handler, err := slogwebhook.New([]slogwebhook.Route{
+//		{Level: slog.LevelInfo, URL: activityURL},
+//		{Level: slog.LevelError, URL: alertsURL},
+//	})
+//	logger := slog.New(handler)
+//	defer handler.Close(ctx)
+//
+// All routes share one queue and one rate limiter, so a burst of records cannot
+// exceed Discord's limits however the records are spread over the webhooks, and
+// logging never blocks on Discord. For the log package, use slog.NewLogLogger.
+//
+// The package requires Go 1.21 or later.
+package slogwebhook
@@ -0,0 +1,348 @@
+//go:build go1.21
+
+package slogwebhook
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+const (
+	defaultQueueSize = 256
+	// defaultInterval keeps the sends of all routes within Discord's limit of 5 requests
+	// per 2 seconds for a single webhook
+	defaultInterval = 500 * time.Millisecond
+
+	maxEmbedsPerMessage = 10
+	maxFieldsPerEmbed   = 25
+	maxTitleLength      = 256
+	maxFieldNameLength  = 256
+	maxFieldValueLength = 1024
+)
+
+// Route sends the records at or above Level to the webhook at URL. A record goes to the
+// route with the highest Level it reaches only, so errors do not also show up in the
+// channel for info records.
+type Route struct {
+	Level slog.Level
+	URL   string
+}
+
+// Option configures a Handler
+type Option func(*core)
+
+// WithClientOptions sets the options of the webhook clients, such as webhook.WithHTTPClient
+func WithClientOptions(options ...webhook.ClientOption) Option {
+	return func(c *core) {
+		c.clientOptions = append(c.clientOptions, options...)
+	}
+}
+
+// WithQueueSize sets how many records wait for delivery before new ones are dropped.
+// It defaults to 256.
+func WithQueueSize(size int) Option {
+	return func(c *core) {
+		c.queueSize = size
+	}
+}
+
+// WithInterval sets the shortest time between two messages, across all routes. It
+// defaults to 500ms.
+func WithInterval(interval time.Duration) Option {
+	return func(c *core) {
+		c.interval = interval
+	}
+}
+
+// WithErrorHandler sets a function called when records could not be delivered or were
+// dropped because the queue was full
+func WithErrorHandler(handler func(err error)) Option {
+	return func(c *core) {
+		c.onError = handler
+	}
+}
+
+// Handler is a slog.Handler posting records as embeds to the webhooks of their routes
+type Handler struct {
+	core *core
+
+	// attrs are the attributes added with WithAttrs, with their group prefixes applied
+	attrs []slog.Attr
+	// group is the prefix of the keys of attributes added from now on
+	group string
+}
+
+// core is the state shared by a handler and those derived from it with WithAttrs and WithGroup
+type core struct {
+	routes        []Route
+	clients       map[string]*webhook.Client
+	clientOptions []webhook.ClientOption
+	queueSize     int
+	interval      time.Duration
+	onError       func(err error)
+
+	queue   chan entry
+	done    chan struct{}
+	closeMu sync.RWMutex
+	closed  bool
+	dropped int64
+}
+
+// entry is a record waiting for delivery
+type entry struct {
+	webhookURL string
+	embed      webhook.Embed
+}
+
+// New creates a handler for the routes and starts delivering in the background. Close
+// it to flush the queue before exiting.
+func New(routes []Route, options ...Option) (*Handler, error) {
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("slogwebhook: no routes")
+	}
+	c := &core{
+		routes:    append([]Route(nil), routes...),
+		clients:   make(map[string]*webhook.Client),
+		queueSize: defaultQueueSize,
+		interval:  defaultInterval,
+		done:      make(chan struct{}),
+	}
+	for _, option := range options {
+		option(c)
+	}
+	// Routes are kept from the highest level down, so the first match is the one to use
+	sort.SliceStable(c.routes, func(i, j int) bool { return c.routes[i].Level > c.routes[j].Level })
+	for _, route := range c.routes {
+		if route.URL == "" {
+			return nil, fmt.Errorf("slogwebhook: route for level %s has no webhook URL", route.Level)
+		}
+		if _, ok := c.clients[route.URL]; !ok {
+			c.clients[route.URL] = webhook.NewClient(route.URL, c.clientOptions...)
+		}
+	}
+	c.queue = make(chan entry, c.queueSize)
+	go c.work()
+	return &Handler{core: c}, nil
+}
+
+// Enabled reports whether records at the level are sent anywhere
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.core.route(level) != nil
+}
+
+// Handle queues the record for its route. It never blocks: when the queue is full,
+// the record is dropped and counted.
+func (h *Handler) Handle(_ context.Context, record slog.Record) error {
+	route := h.core.route(record.Level)
+	if route == nil {
+		return nil
+	}
+	return h.core.enqueue(entry{webhookURL: route.URL, embed: h.embed(record)})
+}
+
+// WithAttrs returns a handler adding the attributes to every record
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	derived := *h
+	derived.attrs = append(append([]slog.Attr(nil), h.attrs...), prefixAttrs(h.group, attrs)...)
+	return &derived
+}
+
+// WithGroup returns a handler qualifying the keys of attributes added later with the group name
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	derived := *h
+	derived.group = h.group + name + "."
+	return &derived
+}
+
+// Dropped returns how many records were dropped because the queue was full
+func (h *Handler) Dropped() int64 {
+	return atomic.LoadInt64(&h.core.dropped)
+}
+
+// Close stops accepting records and waits until the queued ones are delivered, or
+// until ctx ends. It closes the handlers derived from h as well.
+func (h *Handler) Close(ctx context.Context) error {
+	c := h.core
+	c.closeMu.Lock()
+	if !c.closed {
+		c.closed = true
+		close(c.queue)
+	}
+	c.closeMu.Unlock()
+
+	select {
+	case <-c.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// route returns the route of records at the level, or nil when there is none
+func (c *core) route(level slog.Level) *Route {
+	for i := range c.routes {
+		if level >= c.routes[i].Level {
+			return &c.routes[i]
+		}
+	}
+	return nil
+}
+
+// enqueue queues an entry, dropping it when the queue is full
+func (c *core) enqueue(e entry) error {
+	c.closeMu.RLock()
+	defer c.closeMu.RUnlock()
+	if c.closed {
+		return fmt.Errorf("slogwebhook: handler is closed")
+	}
+	select {
+	case c.queue <- e:
+	default:
+		if atomic.AddInt64(&c.dropped, 1) == 1 && c.onError != nil {
+			c.onError(fmt.Errorf("slogwebhook: queue is full, dropping records"))
+		}
+	}
+	return nil
+}
+
+// work delivers queued records until the queue is closed. Records waiting together are
+// batched into messages of up to 10 embeds per webhook, and messages are spaced by
+// the interval.
+func (c *core) work() {
+	defer close(c.done)
+	var last time.Time
+	for first := range c.queue {
+		batch := []entry{first}
+	drain:
+		for len(batch) < c.queueSize {
+			select {
+			case e, ok := <-c.queue:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, e)
+			default:
+				break drain
+			}
+		}
+
+		for _, message := range batchMessages(batch) {
+			if wait := c.interval - time.Since(last); wait > 0 {
+				time.Sleep(wait)
+			}
+			last = time.Now()
+			if err := c.clients[message.webhookURL].Send(context.Background(), message.payload); err != nil && c.onError != nil {
+				c.onError(fmt.Errorf("slogwebhook: failed to send %d records: %w", len(message.payload.Embeds), err))
+			}
+		}
+	}
+}
+
+// message is a payload for one webhook
+type message struct {
+	webhookURL string
+	payload    webhook.Webhook
+}
+
+// batchMessages groups the entries by webhook, in the order each webhook first
+// appears, into messages of up to 10 embeds
+func batchMessages(batch []entry) []message {
+	var messages []message
+	open := make(map[string]int)
+	for _, e := range batch {
+		i, ok := open[e.webhookURL]
+		if !ok || len(messages[i].payload.Embeds) == maxEmbedsPerMessage {
+			messages = append(messages, message{webhookURL: e.webhookURL})
+			i = len(messages) - 1
+			open[e.webhookURL] = i
+		}
+		messages[i].payload.AddEmbed(e.embed)
+	}
+	return messages
+}
+
+// embed renders a record with the handler's attributes as fields. Terminal escape
+// sequences, such as the colors of a console logger, are stripped.
+func (h *Handler) embed(record slog.Record) webhook.Embed {
+	embed := webhook.Embed{
+		Title:     truncate(webhook.StripANSI(record.Message), maxTitleLength),
+		Color:     levelColor(record.Level),
+		Timestamp: record.Time.UTC().Format(time.RFC3339),
+	}
+	embed.SetFooter(webhook.CreateFooter(record.Level.String(), "", ""))
+	if record.Time.IsZero() {
+		embed.Timestamp = ""
+	}
+
+	addField := func(attr slog.Attr) {
+		value := webhook.StripANSI(attr.Value.String())
+		if len(embed.Fields) < maxFieldsPerEmbed && attr.Key != "" && value != "" {
+			embed.AddField(webhook.CreateField(truncate(attr.Key, maxFieldNameLength), truncate(value, maxFieldValueLength), true))
+		}
+	}
+	for _, attr := range h.attrs {
+		addField(attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		for _, flattened := range prefixAttrs(h.group, []slog.Attr{attr}) {
+			addField(flattened)
+		}
+		return true
+	})
+	return embed
+}
+
+// prefixAttrs flattens groups into dotted keys, prefixed with prefix
+func prefixAttrs(prefix string, attrs []slog.Attr) []slog.Attr {
+	var flat []slog.Attr
+	for _, attr := range attrs {
+		attr.Value = attr.Value.Resolve()
+		switch {
+		case attr.Equal(slog.Attr{}):
+			continue
+		case attr.Value.Kind() == slog.KindGroup:
+			groupPrefix := prefix
+			if attr.Key != "" {
+				groupPrefix += attr.Key + "."
+			}
+			flat = append(flat, prefixAttrs(groupPrefix, attr.Value.Group())...)
+		default:
+			attr.Key = prefix + attr.Key
+			flat = append(flat, attr)
+		}
+	}
+	return flat
+}
+
+// levelColor returns the embed color of a level, matching the colors of the otellog package
+func levelColor(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 0xE74C3C
+	case level >= slog.LevelWarn:
+		return 0xF1C40F
+	case level >= slog.LevelInfo:
+		return 0x3498DB
+	default:
+		return 0x95A5A6
+	}
+}
+
+// truncate shortens text to at most limit characters, marking the cut with an ellipsis
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,28 @@
+//go:build go1.21
+
+package slogwebhook
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestEmbedStripsANSI(t *testing.T) {
+	h := &Handler{attrs: []slog.Attr{slog.String("service", "\x1b[36mapi\x1b[0m")}}
+	record := slog.NewRecord(time.Now(), slog.LevelError, "\x1b[31mdeploy failed\x1b[0m", 0)
+	record.AddAttrs(slog.String("step", "\x1b[1mtest\x1b[0m"), slog.String("empty", "\x1b[0m"))
+
+	embed := h.embed(record)
+	if embed.Title != "deploy failed" {
+		t.Errorf("title = %q, want %q", embed.Title, "deploy failed")
+	}
+	if len(embed.Fields) != 2 {
+		t.Fatalf("got %d fields, want 2 without the empty one: %+v", len(embed.Fields), embed.Fields)
+	}
+	for i, want := range []string{"api", "test"} {
+		if embed.Fields[i].Value != want {
+			t.Errorf("field %s = %q, want %q", embed.Fields[i].Name, embed.Fields[i].Value, want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+)
+
+// SplitEmbeds partitions the embeds of the payload across as many messages as the 10 embed
+// and 6000 character limits require, keeping their order. The content goes with the first
+// message and the components and poll with the last; the username, avatar and allowed mentions
+// are repeated on every message.
+func SplitEmbeds(webhookPayload Webhook) []Webhook {
+	base := webhookPayload
+	base.Content, base.Embeds, base.Components, base.Poll = "", nil, nil, nil
+
+	var messages []Webhook
+	message, length := base, 0
+	for _, embed := range webhookPayload.Embeds {
+		embedLength := embedTextLength(embed)
+		if len(message.Embeds) > 0 && (len(message.Embeds) == maxEmbeds || length+embedLength > maxEmbedTotalLength) {
+			messages = append(messages, message)
+			message, length = base, 0
+		}
+		message.Embeds = append(message.Embeds, embed)
+		length += embedLength
+	}
+	messages = append(messages, message)
+
+	messages[0].Content = webhookPayload.Content
+	messages[len(messages)-1].Components = webhookPayload.Components
+	messages[len(messages)-1].Poll = webhookPayload.Poll
+	// Only the first message may create the thread
+	for i := 1; i < len(messages); i++ {
+		messages[i].ThreadName, messages[i].AppliedTags = "", nil
+	}
+	return messages
+}
+
+// SplitMessage partitions the payload across as many messages as Discord's limits
+// require. Content beyond 2000 characters is split at line breaks or spaces into
+// messages of its own, followed by the embeds partitioned like SplitEmbeds does.
+func SplitMessage(webhookPayload Webhook) []Webhook {
+	chunks := splitContent(webhookPayload.Content, maxContentLength)
+	if len(chunks) == 1 {
+		return SplitEmbeds(webhookPayload)
+	}
+
+	messages := make([]Webhook, 0, len(chunks))
+	for i, chunk := range chunks[:len(chunks)-1] {
+		message := Webhook{
+			Content:         chunk,
+			Username:        webhookPayload.Username,
+			AvatarURL:       webhookPayload.AvatarURL,
+			AllowedMentions: webhookPayload.AllowedMentions,
+			Flags:           webhookPayload.Flags,
+		}
+		if i == 0 {
+			message.ThreadName, message.AppliedTags = webhookPayload.ThreadName, webhookPayload.AppliedTags
+		}
+		messages = append(messages, message)
+	}
+	webhookPayload.Content = chunks[len(chunks)-1]
+	webhookPayload.ThreadName, webhookPayload.AppliedTags = "", nil
+	return append(messages, SplitEmbeds(webhookPayload)...)
+}
+
+// SendSplit sends the payload like Send, but partitions content and embeds beyond
+// Discord's limits across sequential messages instead of failing; see SplitMessage.
+// When the payload creates a thread, the following messages are posted into it.
+func (c *Client) SendSplit(ctx context.Context, webhookPayload Webhook) error {
+	messages := SplitMessage(webhookPayload)
+	if len(messages) == 1 {
+		return c.Send(ctx, messages[0])
+	}
+
+	client := c
+	for i, message := range messages {
+		var err error
+		if i == 0 && message.ThreadName != "" {
+			client, err = c.StartThread(ctx, message.ThreadName, message)
+		} else {
+			err = client.Send(ctx, message)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to send message %d of %d: %w", i+1, len(messages), err)
+		}
+	}
+	return nil
+}
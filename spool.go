@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// spoolSequence orders payloads spooled within the same nanosecond
+var spoolSequence int64
+
+// spooledMessage is the content of a spool file
+type spooledMessage struct {
+	URL       string    `json:"url"`
+	SpooledAt time.Time `json:"spooled_at"`
+	Payload   Webhook   `json:"payload"`
+}
+
+// WithSpool writes payloads to dir, one JSON file per message, when Discord cannot
+// be reached, and reports the send as successful. Rejected payloads are not spooled.
+// Deliver the spooled messages later with Replay. The files contain the webhook URL
+// including its token, so they are only readable by the current user.
+func WithSpool(dir string) ClientOption {
+	return func(c *Client) {
+		c.spoolDir = dir
+	}
+}
+
+// WithOfflineSpool writes every payload to dir instead of sending it, for
+// air-gapped environments where messages are carried out and replayed elsewhere
+func WithOfflineSpool(dir string) ClientOption {
+	return func(c *Client) {
+		c.spoolDir = dir
+		c.spoolAlways = true
+	}
+}
+
+// spoolPayload writes a payload to the spool directory. The file is renamed into
+// place once complete, so Replay never picks up partially written messages.
+func spoolPayload(dir, webhookURL string, webhookPayload Webhook) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create spool directory: %v", err)
+	}
+
+	now := time.Now()
+	data, err := json.Marshal(spooledMessage{URL: webhookURL, SpooledAt: now, Payload: webhookPayload})
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON payload: %v", err)
+	}
+
+	name := fmt.Sprintf("%s-%06d.json", now.UTC().Format("20060102T150405.000000000"), atomic.AddInt64(&spoolSequence, 1))
+	tmp, err := os.CreateTemp(dir, ".spool-*")
+	if err != nil {
+		return fmt.Errorf("failed to spool payload: %v", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to spool payload: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to spool payload: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to spool payload: %v", err)
+	}
+	return nil
+}
+
+// Replay sends the messages spooled in dir in the order they were spooled, removing
+// each file once it was delivered. Messages to the same webhook are sent by one client
+// configured with the options. It stops at the first failure so the order is kept,
+// and returns the number of messages delivered.
+func Replay(ctx context.Context, dir string, options ...ClientOption) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read spool directory: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") && !strings.HasPrefix(entry.Name(), ".") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	// Messages to the same webhook share a client, and with it its rate limit state
+	clients := make(map[string]*Client)
+	sent := 0
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return sent, fmt.Errorf("failed to read spooled message: %v", err)
+		}
+		var message spooledMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			return sent, fmt.Errorf("invalid spooled message %s: %v", name, err)
+		}
+
+		client, ok := clients[message.URL]
+		if !ok {
+			// Replayed messages must not be spooled again
+			client = newClient(message.URL, options)
+			client.spoolDir, client.spoolAlways = "", false
+			clients[message.URL] = client
+		}
+		if err := client.Send(ctx, message.Payload); err != nil {
+			return sent, fmt.Errorf("failed to replay %s: %v", name, err)
+		}
+		if err := os.Remove(path); err != nil {
+			return sent, fmt.Errorf("failed to remove replayed message: %v", err)
+		}
+		sent++
+	}
+	return sent, nil
+}
@@ -0,0 +1,54 @@
+package webhook_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/webhooktest"
+)
+
+func TestReplaySharesClientsPerWebhook(t *testing.T) {
+	first := webhooktest.NewServer()
+	defer first.Close()
+	second := webhooktest.NewServer()
+	defer second.Close()
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	for _, target := range []struct {
+		server  *webhooktest.Server
+		content string
+	}{
+		{first, "one"},
+		{second, "two"},
+		{first, "three"},
+		{first, "four"},
+	} {
+		client := webhook.NewClient(target.server.URL, webhook.WithOfflineSpool(dir))
+		if err := client.Send(ctx, webhook.Webhook{Content: target.content}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	created := 0
+	countClients := webhook.ClientOption(func(*webhook.Client) { created++ })
+	sent, err := webhook.Replay(ctx, dir, countClients)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 4 {
+		t.Errorf("replayed %d messages, want 4", sent)
+	}
+	if created != 2 {
+		t.Errorf("created %d clients, want one per webhook", created)
+	}
+	if got := contents(first); len(got) != 3 || got[0] != "one" || got[1] != "three" || got[2] != "four" {
+		t.Errorf("first webhook got %q, want the messages in order", got)
+	}
+	second.AssertRequestCount(t, 1)
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 0 {
+		t.Errorf("spool directory holds %d files, %v; want it empty", len(entries), err)
+	}
+}
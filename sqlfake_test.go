@@ -0,0 +1,315 @@
+package webhook_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// fakeDriver is an in-memory database understanding just the SQL the package's
+// outbox and audit sink issue, since the standard library has no driver
+type fakeDriver struct {
+	mu  sync.Mutex
+	dbs map[string]*fakeDB
+}
+
+// fakeDB holds the tables of one data source name
+type fakeDB struct {
+	mu     sync.Mutex
+	tables map[string]*fakeTable
+}
+
+type fakeTable struct {
+	columns  []string
+	defaults map[string]driver.Value
+	rows     []map[string]driver.Value
+	nextID   int64
+}
+
+func init() {
+	sql.Register("webhookfake", &fakeDriver{dbs: make(map[string]*fakeDB)})
+}
+
+// fakeDatabases counts the databases opened, keeping each one apart even when a test runs again
+var fakeDatabases int64
+
+// openFakeDB opens a fresh in-memory database named after the test
+func openFakeDB(name string) (*sql.DB, error) {
+	return sql.Open("webhookfake", fmt.Sprintf("%s-%d", name, atomic.AddInt64(&fakeDatabases, 1)))
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	db, ok := d.dbs[name]
+	if !ok {
+		db = &fakeDB{tables: make(map[string]*fakeTable)}
+		d.dbs[name] = db
+	}
+	return &fakeConn{db: db}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: strings.Join(strings.Fields(query), " ")}, nil
+}
+
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+var (
+	fakeCreate = regexp.MustCompile(`^CREATE TABLE IF NOT EXISTS (\w+) \((.*)\)$`)
+	fakeAlter  = regexp.MustCompile(`^ALTER TABLE (\w+) ADD COLUMN (\w+)`)
+	fakeInsert = regexp.MustCompile(`^INSERT INTO (\w+) \(([^)]*)\) VALUES`)
+	fakeUpdate = regexp.MustCompile(`^UPDATE (\w+) SET (.+?) WHERE (.+)$`)
+	fakeDelete = regexp.MustCompile(`^DELETE FROM (\w+) WHERE (.+)$`)
+	fakeSelect = regexp.MustCompile(`^SELECT (.+?) FROM (\w+)(?: WHERE (.+?))?(?: ORDER BY (\w+))?( LIMIT \?)?$`)
+	fakeCond   = regexp.MustCompile(`^(\w+) (IS NULL|=|<=) ?\??$`)
+)
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if m := fakeCreate.FindStringSubmatch(s.query); m != nil {
+		if _, ok := s.db.tables[m[1]]; !ok {
+			table := &fakeTable{defaults: make(map[string]driver.Value)}
+			for _, definition := range strings.Split(m[2], ",") {
+				fields := strings.Fields(definition)
+				table.columns = append(table.columns, fields[0])
+				if len(fields) > 2 && fields[len(fields)-2] == "DEFAULT" {
+					n, err := strconv.ParseInt(fields[len(fields)-1], 10, 64)
+					if err != nil {
+						return nil, fmt.Errorf("fake: unsupported default in %q", definition)
+					}
+					table.defaults[fields[0]] = n
+				}
+			}
+			s.db.tables[m[1]] = table
+		}
+		return driver.RowsAffected(0), nil
+	}
+	if m := fakeAlter.FindStringSubmatch(s.query); m != nil {
+		table, err := s.table(m[1])
+		if err != nil {
+			return nil, err
+		}
+		table.columns = append(table.columns, m[2])
+		return driver.RowsAffected(0), nil
+	}
+	if m := fakeInsert.FindStringSubmatch(s.query); m != nil {
+		table, err := s.table(m[1])
+		if err != nil {
+			return nil, err
+		}
+		columns := strings.Split(m[2], ",")
+		if len(columns) != len(args) {
+			return nil, fmt.Errorf("fake: %d columns but %d values", len(columns), len(args))
+		}
+		row := make(map[string]driver.Value)
+		for column, value := range table.defaults {
+			row[column] = value
+		}
+		for i, column := range columns {
+			column = strings.TrimSpace(column)
+			if !table.has(column) {
+				return nil, fmt.Errorf("fake: table %s has no column %s", m[1], column)
+			}
+			row[column] = args[i]
+		}
+		if table.has("id") && row["id"] == nil {
+			table.nextID++
+			row["id"] = table.nextID
+		}
+		table.rows = append(table.rows, row)
+		return driver.RowsAffected(1), nil
+	}
+	if m := fakeUpdate.FindStringSubmatch(s.query); m != nil {
+		table, err := s.table(m[1])
+		if err != nil {
+			return nil, err
+		}
+		assignments := strings.Split(m[2], ",")
+		if len(args) < len(assignments) {
+			return nil, fmt.Errorf("fake: missing values")
+		}
+		matches, err := table.filter(m[3], args[len(assignments):])
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range matches {
+			for i, assignment := range assignments {
+				row[strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(assignment), "= ?"))] = args[i]
+			}
+		}
+		return driver.RowsAffected(len(matches)), nil
+	}
+	if m := fakeDelete.FindStringSubmatch(s.query); m != nil {
+		table, err := s.table(m[1])
+		if err != nil {
+			return nil, err
+		}
+		matches, err := table.filter(m[2], args)
+		if err != nil {
+			return nil, err
+		}
+		kept := table.rows[:0]
+		for _, row := range table.rows {
+			if !containsRow(matches, row) {
+				kept = append(kept, row)
+			}
+		}
+		table.rows = kept
+		return driver.RowsAffected(len(matches)), nil
+	}
+	return nil, fmt.Errorf("fake: unsupported statement %q", s.query)
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	m := fakeSelect.FindStringSubmatch(s.query)
+	if m == nil {
+		return nil, fmt.Errorf("fake: unsupported query %q", s.query)
+	}
+	table, err := s.table(m[2])
+	if err != nil {
+		return nil, err
+	}
+	limit := -1
+	if m[5] != "" {
+		if len(args) == 0 {
+			return nil, fmt.Errorf("fake: missing limit")
+		}
+		limit = int(args[len(args)-1].(int64))
+		args = args[:len(args)-1]
+	}
+	matches, err := table.filter(m[3], args)
+	if err != nil {
+		return nil, err
+	}
+	// Rows are kept in insertion order, which is the order of their IDs
+	if limit >= 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	if m[1] == "COUNT(*)" {
+		return &fakeRows{columns: []string{"count"}, values: [][]driver.Value{{int64(len(matches))}}}, nil
+	}
+	columns := strings.Split(m[1], ",")
+	rows := &fakeRows{}
+	for _, column := range columns {
+		column = strings.TrimSpace(column)
+		if !table.has(column) {
+			return nil, fmt.Errorf("fake: table %s has no column %s", m[2], column)
+		}
+		rows.columns = append(rows.columns, column)
+	}
+	for _, row := range matches {
+		values := make([]driver.Value, len(rows.columns))
+		for i, column := range rows.columns {
+			values[i] = row[column]
+		}
+		rows.values = append(rows.values, values)
+	}
+	return rows, nil
+}
+
+func (s *fakeStmt) table(name string) (*fakeTable, error) {
+	table, ok := s.db.tables[name]
+	if !ok {
+		return nil, fmt.Errorf("fake: no such table %s", name)
+	}
+	return table, nil
+}
+
+func (t *fakeTable) has(column string) bool {
+	for _, c := range t.columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// filter returns the rows matching the conditions, joined by AND
+func (t *fakeTable) filter(where string, args []driver.Value) ([]map[string]driver.Value, error) {
+	if where == "" {
+		return append([]map[string]driver.Value(nil), t.rows...), nil
+	}
+	var matches []map[string]driver.Value
+	for _, row := range t.rows {
+		arg, ok := 0, true
+		for _, condition := range strings.Split(where, " AND ") {
+			m := fakeCond.FindStringSubmatch(condition)
+			if m == nil {
+				return nil, fmt.Errorf("fake: unsupported condition %q", condition)
+			}
+			value := row[m[1]]
+			switch m[2] {
+			case "IS NULL":
+				ok = ok && value == nil
+			case "=":
+				ok = ok && fmt.Sprint(value) == fmt.Sprint(args[arg])
+				arg++
+			case "<=":
+				n, isInt := value.(int64)
+				ok = ok && isInt && n <= args[arg].(int64)
+				arg++
+			}
+		}
+		if ok {
+			matches = append(matches, row)
+		}
+	}
+	return matches, nil
+}
+
+func containsRow(rows []map[string]driver.Value, row map[string]driver.Value) bool {
+	for _, r := range rows {
+		if fmt.Sprintf("%p", r) == fmt.Sprintf("%p", row) {
+			return true
+		}
+	}
+	return false
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
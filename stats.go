@@ -0,0 +1,179 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RateLimit is the last known state of a Discord rate limit bucket
+type RateLimit struct {
+	Bucket    string
+	Limit     int
+	Remaining int
+	// Reset is when the bucket refills
+	Reset time.Time
+	// Updated is when the state was reported
+	Updated time.Time
+}
+
+// Stats is a snapshot of a client's activity, such as for health endpoints
+type Stats struct {
+	// Sent and Failed count the requests Discord accepted and the ones that failed,
+	// including edits and deletes
+	Sent   int64
+	Failed int64
+	// Retried counts the retries of queued messages
+	Retried int64
+	// RetriesRefused counts the retries refused because the retry budget was exhausted
+	RetriesRefused int64
+	// Queued is the number of messages waiting in the queue
+	Queued int
+	// RateLimits holds the last known state of the rate limit buckets, by bucket ID
+	RateLimits map[string]RateLimit
+
+	// Latency summarizes the durations of recent requests
+	Latency LatencySummary
+	// Outcomes counts recent requests by outcome: "ok", "rate_limited",
+	// "client_error", "server_error" and "network_error"
+	Outcomes map[string]int
+}
+
+// LatencySummary gives percentiles of the durations of the most recent requests,
+// up to the last 1024
+type LatencySummary struct {
+	Count int
+	P50   time.Duration
+	P90   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+}
+
+// latencySamples is the number of recent requests kept for the latency summary
+const latencySamples = 1024
+
+// requestSample is the duration and outcome of a single request
+type requestSample struct {
+	latency time.Duration
+	outcome string
+}
+
+// clientStats collects the statistics of a client. The counters come first so
+// that they are 64-bit aligned for atomic access.
+type clientStats struct {
+	sent, failed, retried, refusedRetries int64
+
+	mu      sync.Mutex
+	buckets map[string]RateLimit
+
+	// samples is a ring buffer of the most recent requests; next is where the following one goes
+	samples []requestSample
+	next    int
+}
+
+// Stats returns a snapshot of the client's counters, queue depth and rate limits
+func (c *Client) Stats() Stats {
+	stats := Stats{
+		Sent:           atomic.LoadInt64(&c.stats.sent),
+		Failed:         atomic.LoadInt64(&c.stats.failed),
+		Retried:        atomic.LoadInt64(&c.stats.retried),
+		RetriesRefused: atomic.LoadInt64(&c.stats.refusedRetries),
+		RateLimits:     make(map[string]RateLimit),
+	}
+
+	c.queue.mu.Lock()
+	stats.Queued = len(c.queue.pending)
+	c.queue.mu.Unlock()
+
+	c.stats.mu.Lock()
+	for bucket, limit := range c.stats.buckets {
+		stats.RateLimits[bucket] = limit
+	}
+	samples := append([]requestSample(nil), c.stats.samples...)
+	c.stats.mu.Unlock()
+
+	stats.Latency, stats.Outcomes = summarize(samples)
+	return stats
+}
+
+// summarize computes the latency percentiles and outcome counts of the samples
+func summarize(samples []requestSample) (LatencySummary, map[string]int) {
+	outcomes := make(map[string]int)
+	latencies := make([]time.Duration, len(samples))
+	for i, sample := range samples {
+		latencies[i] = sample.latency
+		outcomes[sample.outcome]++
+	}
+	if len(latencies) == 0 {
+		return LatencySummary{}, outcomes
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	// Nearest rank: the smallest sample at or above p percent of the samples
+	percentile := func(p int) time.Duration {
+		return latencies[(len(latencies)*p+99)/100-1]
+	}
+	return LatencySummary{
+		Count: len(latencies),
+		P50:   percentile(50),
+		P90:   percentile(90),
+		P99:   percentile(99),
+		Max:   latencies[len(latencies)-1],
+	}, outcomes
+}
+
+// outcome classifies the result of a request
+func outcome(status int, err error) string {
+	var netErr *NetworkError
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.As(err, &netErr):
+		return "network_error"
+	case status == http.StatusTooManyRequests:
+		return "rate_limited"
+	case status >= 500:
+		return "server_error"
+	}
+	return "client_error"
+}
+
+// record counts a finished request, samples its latency and keeps the rate limit
+// state of its response
+func (s *clientStats) record(header http.Header, latency time.Duration, status int, err error) {
+	if err != nil {
+		atomic.AddInt64(&s.failed, 1)
+	} else {
+		atomic.AddInt64(&s.sent, 1)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sample := requestSample{latency: latency, outcome: outcome(status, err)}
+	if len(s.samples) < latencySamples {
+		s.samples = append(s.samples, sample)
+	} else {
+		s.samples[s.next] = sample
+	}
+	s.next = (s.next + 1) % latencySamples
+
+	bucket := header.Get("X-RateLimit-Bucket")
+	if bucket == "" {
+		return
+	}
+	now := time.Now()
+	limit := RateLimit{Bucket: bucket, Updated: now}
+	limit.Limit, _ = strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	limit.Remaining, _ = strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if seconds, err := strconv.ParseFloat(header.Get("X-RateLimit-Reset-After"), 64); err == nil {
+		limit.Reset = now.Add(time.Duration(seconds * float64(time.Second)))
+	}
+	if s.buckets == nil {
+		s.buckets = make(map[string]RateLimit)
+	}
+	s.buckets[bucket] = limit
+}
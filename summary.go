@@ -0,0 +1,88 @@
+package webhook
+
+import "strings"
+
+const (
+	// maxSummaryTextLength is how many characters of a text a summary quotes
+	maxSummaryTextLength = 40
+	// maxSummaryTitles is how many embed titles a summary lists
+	maxSummaryTitles = 3
+)
+
+// Summary describes the message in a short single line for logs, such as
+// `embed 'Deploy failed' + 3 fields + 1 attachment in new thread 'Deploys'`, so
+// operators can tell what was sent without dumping the whole payload. Quoted texts
+// are shortened to 40 characters on a single line.
+func (w Webhook) Summary() string {
+	var parts []string
+	if w.Content != "" {
+		parts = append(parts, "content "+summaryQuote(w.Content))
+	}
+
+	var titles []string
+	fields := 0
+	for _, embed := range w.Embeds {
+		fields += len(embed.Fields)
+		if embed.Title != "" {
+			titles = append(titles, summaryQuote(embed.Title))
+		}
+	}
+	if len(titles) > maxSummaryTitles {
+		titles = append(titles[:maxSummaryTitles], "…")
+	}
+	if n := len(w.Embeds); n > 0 {
+		embeds := pluralize(n, "embed")
+		if n == 1 {
+			embeds = "embed"
+		}
+		if len(titles) > 0 {
+			embeds += " " + strings.Join(titles, ", ")
+		}
+		parts = append(parts, embeds)
+	}
+	if fields > 0 {
+		parts = append(parts, pluralize(fields, "field"))
+	}
+
+	buttons, menus := 0, 0
+	for _, component := range flattenComponents(w.Components) {
+		if isSelectMenu(component.Type) {
+			menus++
+		} else {
+			buttons++
+		}
+	}
+	if buttons > 0 {
+		parts = append(parts, pluralize(buttons, "button"))
+	}
+	if menus > 0 {
+		parts = append(parts, pluralize(menus, "select menu"))
+	}
+	if w.Poll != nil {
+		parts = append(parts, "poll "+summaryQuote(w.Poll.Question.Text))
+	}
+	if n := len(w.Attachments); n > 0 {
+		parts = append(parts, pluralize(n, "attachment"))
+	}
+
+	summary := strings.Join(parts, " + ")
+	if summary == "" {
+		summary = "empty message"
+	}
+	if w.ThreadName != "" {
+		summary += " in new thread " + summaryQuote(w.ThreadName)
+	}
+	if w.Username != "" {
+		summary += " as " + summaryQuote(w.Username)
+	}
+	return summary
+}
+
+// summaryQuote quotes the text on a single line, shortened with an ellipsis
+func summaryQuote(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if shortened := truncateRunes(text, maxSummaryTextLength); shortened != text {
+		text = strings.TrimSpace(shortened) + "…"
+	}
+	return "'" + text + "'"
+}
@@ -0,0 +1,353 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+
+// Colors used for Adaptive Card container styles
+const (
+	adaptiveColorGood      = 0x2EB886
+	adaptiveColorWarning   = 0xDAA038
+	adaptiveColorAttention = 0xD13438
+	adaptiveColorAccent    = 0x0078D4
+)
+
+// TeamsMessageCard represents a legacy Microsoft Teams connector MessageCard
+type TeamsMessageCard struct {
+	Type            string         `json:"@type,omitempty"`
+	Summary         string         `json:"summary,omitempty"`
+	Title           string         `json:"title,omitempty"`
+	Text            string         `json:"text,omitempty"`
+	ThemeColor      string         `json:"themeColor,omitempty"`
+	Sections        []TeamsSection `json:"sections,omitempty"`
+	PotentialAction []TeamsAction  `json:"potentialAction,omitempty"`
+}
+
+// TeamsSection represents a section of a MessageCard
+type TeamsSection struct {
+	Title            string        `json:"title,omitempty"`
+	ActivityTitle    string        `json:"activityTitle,omitempty"`
+	ActivitySubtitle string        `json:"activitySubtitle,omitempty"`
+	ActivityText     string        `json:"activityText,omitempty"`
+	ActivityImage    string        `json:"activityImage,omitempty"`
+	Text             string        `json:"text,omitempty"`
+	Facts            []TeamsFact   `json:"facts,omitempty"`
+	Images           []TeamsImage  `json:"images,omitempty"`
+	PotentialAction  []TeamsAction `json:"potentialAction,omitempty"`
+}
+
+// TeamsFact represents a name/value pair shown in a MessageCard section
+type TeamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// TeamsImage represents an image shown in a MessageCard section
+type TeamsImage struct {
+	Image string `json:"image"`
+	Title string `json:"title,omitempty"`
+}
+
+// TeamsAction represents a MessageCard potential action. Only OpenUri actions can be converted.
+type TeamsAction struct {
+	Type    string              `json:"@type"`
+	Name    string              `json:"name"`
+	Targets []TeamsActionTarget `json:"targets,omitempty"`
+}
+
+// TeamsActionTarget represents a platform-specific target of an OpenUri action
+type TeamsActionTarget struct {
+	OS  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+// AdaptiveCard represents a Microsoft Adaptive Card
+type AdaptiveCard struct {
+	Type    string            `json:"type,omitempty"`
+	Body    []AdaptiveElement `json:"body,omitempty"`
+	Actions []AdaptiveAction  `json:"actions,omitempty"`
+}
+
+// AdaptiveElement represents an element of an Adaptive Card body.
+// Container elements (Container, ColumnSet, Column) are walked recursively.
+type AdaptiveElement struct {
+	Type    string            `json:"type"`
+	Text    string            `json:"text,omitempty"`
+	Size    string            `json:"size,omitempty"`
+	Weight  string            `json:"weight,omitempty"`
+	Style   string            `json:"style,omitempty"`
+	URL     string            `json:"url,omitempty"`
+	AltText string            `json:"altText,omitempty"`
+	Facts   []AdaptiveFact    `json:"facts,omitempty"`
+	Items   []AdaptiveElement `json:"items,omitempty"`
+	Columns []AdaptiveElement `json:"columns,omitempty"`
+	Images  []AdaptiveElement `json:"images,omitempty"`
+	Actions []AdaptiveAction  `json:"actions,omitempty"`
+}
+
+// AdaptiveFact represents a title/value pair of a FactSet
+type AdaptiveFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// AdaptiveAction represents an Adaptive Card action. Only Action.OpenUrl can be converted.
+type AdaptiveAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url,omitempty"`
+}
+
+// teamsEnvelope is used to detect which card format a Teams payload uses
+type teamsEnvelope struct {
+	Type        string `json:"type"`
+	LegacyType  string `json:"@type"`
+	Attachments []struct {
+		ContentType string          `json:"contentType"`
+		Content     json.RawMessage `json:"content"`
+	} `json:"attachments"`
+}
+
+// ParseTeamsMessage decodes a Teams incoming webhook payload and converts it into a Webhook.
+// It accepts a MessageCard, a bare Adaptive Card, or a "message" wrapping Adaptive Card attachments.
+func ParseTeamsMessage(data []byte) (Webhook, error) {
+	var envelope teamsEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return Webhook{}, fmt.Errorf("failed to parse Teams payload: %v", err)
+	}
+
+	switch {
+	case envelope.Type == "message":
+		var webhook Webhook
+		for _, attachment := range envelope.Attachments {
+			if attachment.ContentType != adaptiveCardContentType {
+				continue
+			}
+			var card AdaptiveCard
+			if err := json.Unmarshal(attachment.Content, &card); err != nil {
+				return Webhook{}, fmt.Errorf("failed to parse Adaptive Card: %v", err)
+			}
+			converted := ConvertAdaptiveCard(card)
+			webhook.Embeds = append(webhook.Embeds, converted.Embeds...)
+			webhook.Components = append(webhook.Components, converted.Components...)
+		}
+		if len(webhook.Components) > maxActionRows {
+			webhook.Components = webhook.Components[:maxActionRows]
+		}
+		return webhook, nil
+	case envelope.Type == "AdaptiveCard":
+		var card AdaptiveCard
+		if err := json.Unmarshal(data, &card); err != nil {
+			return Webhook{}, fmt.Errorf("failed to parse Adaptive Card: %v", err)
+		}
+		return ConvertAdaptiveCard(card), nil
+	case envelope.LegacyType == "MessageCard" || envelope.LegacyType == "":
+		var card TeamsMessageCard
+		if err := json.Unmarshal(data, &card); err != nil {
+			return Webhook{}, fmt.Errorf("failed to parse MessageCard: %v", err)
+		}
+		return ConvertMessageCard(card), nil
+	default:
+		return Webhook{}, fmt.Errorf("unsupported Teams card type %q", envelope.LegacyType)
+	}
+}
+
+// ConvertMessageCard converts a MessageCard into a Webhook with a single embed.
+// Section facts become inline fields, section text becomes regular fields and
+// OpenUri actions become link buttons.
+func ConvertMessageCard(card TeamsMessageCard) Webhook {
+	embed := Embed{
+		Title:       card.Title,
+		Description: card.Text,
+		Color:       lenientHexColor(card.ThemeColor),
+	}
+	if embed.Title == "" && embed.Description == "" {
+		embed.Description = card.Summary
+	}
+
+	actions := card.PotentialAction
+	for i, section := range card.Sections {
+		if i == 0 && section.ActivityTitle != "" {
+			embed.SetAuthor(CreateAuthor(section.ActivityTitle, "", section.ActivityImage, ""))
+		} else if i == 0 && section.ActivityImage != "" {
+			embed.SetThumbnail(Thumbnail{URL: section.ActivityImage})
+		}
+
+		name := section.Title
+		if name == "" && i > 0 {
+			name = section.ActivityTitle
+		}
+		value := joinNonEmpty("\n", section.ActivitySubtitle, section.ActivityText, section.Text)
+		if value != "" {
+			if name == "" && i == 0 {
+				embed.Description = joinNonEmpty("\n\n", embed.Description, value)
+			} else {
+				embed.AddField(CreateField(fieldName(name), value, false))
+			}
+		} else if name != "" && len(section.Facts) > 0 {
+			embed.AddField(CreateField(fieldName(name), zeroWidthSpace, false))
+		}
+
+		for _, fact := range section.Facts {
+			embed.AddField(CreateField(fieldName(fact.Name), fieldValue(fact.Value), true))
+		}
+		for _, image := range section.Images {
+			if embed.Image.URL == "" {
+				embed.SetImage(Image{URL: image.Image})
+			}
+		}
+		actions = append(actions, section.PotentialAction...)
+	}
+
+	webhook := Webhook{}
+	webhook.AddEmbed(embed)
+
+	var buttons []Component
+	for _, action := range actions {
+		if action.Type != "OpenUri" {
+			continue
+		}
+		if uri := teamsActionURI(action); uri != "" {
+			buttons = append(buttons, CreateLinkButton(truncateRunes(action.Name, maxButtonLabelRunes), uri))
+		}
+	}
+	webhook.Components = linkButtonRows(buttons)
+
+	return webhook
+}
+
+// ConvertAdaptiveCard converts an Adaptive Card into a Webhook with a single embed.
+// A leading large or bold TextBlock becomes the title, FactSets become inline fields
+// and Action.OpenUrl actions become link buttons.
+func ConvertAdaptiveCard(card AdaptiveCard) Webhook {
+	var embed Embed
+	var description []string
+	var buttons []Component
+
+	var walk func(elements []AdaptiveElement)
+	walk = func(elements []AdaptiveElement) {
+		for _, element := range elements {
+			switch element.Type {
+			case "TextBlock", "RichTextBlock":
+				if embed.Title == "" && len(description) == 0 && len(embed.Fields) == 0 && isAdaptiveHeading(element) {
+					embed.Title = element.Text
+				} else if element.Text != "" {
+					if isAdaptiveHeading(element) {
+						description = append(description, "**"+element.Text+"**")
+					} else {
+						description = append(description, element.Text)
+					}
+				}
+			case "FactSet":
+				for _, fact := range element.Facts {
+					embed.AddField(CreateField(fieldName(fact.Title), fieldValue(fact.Value), true))
+				}
+			case "Image":
+				if embed.Image.URL == "" {
+					embed.SetImage(Image{URL: element.URL})
+				}
+			case "ImageSet":
+				walk(element.Images)
+			case "ActionSet":
+				buttons = append(buttons, adaptiveButtons(element.Actions)...)
+			}
+
+			if embed.Color == 0 {
+				embed.Color = adaptiveStyleColor(element.Style)
+			}
+			walk(element.Items)
+			walk(element.Columns)
+		}
+	}
+	walk(card.Body)
+
+	embed.Description = strings.Join(description, "\n\n")
+	buttons = append(buttons, adaptiveButtons(card.Actions)...)
+
+	webhook := Webhook{}
+	webhook.AddEmbed(embed)
+	webhook.Components = linkButtonRows(buttons)
+	return webhook
+}
+
+// adaptiveButtons converts OpenUrl actions into link buttons
+func adaptiveButtons(actions []AdaptiveAction) []Component {
+	var buttons []Component
+	for _, action := range actions {
+		if action.Type == "Action.OpenUrl" && action.URL != "" {
+			buttons = append(buttons, CreateLinkButton(truncateRunes(action.Title, maxButtonLabelRunes), action.URL))
+		}
+	}
+	return buttons
+}
+
+// isAdaptiveHeading reports whether a text block is styled as a heading
+func isAdaptiveHeading(element AdaptiveElement) bool {
+	size := strings.ToLower(element.Size)
+	return strings.EqualFold(element.Weight, "bolder") || size == "large" || size == "extralarge"
+}
+
+// adaptiveStyleColor maps container styles onto embed colors
+func adaptiveStyleColor(style string) int {
+	switch strings.ToLower(style) {
+	case "good":
+		return adaptiveColorGood
+	case "warning":
+		return adaptiveColorWarning
+	case "attention":
+		return adaptiveColorAttention
+	case "accent":
+		return adaptiveColorAccent
+	default:
+		return 0
+	}
+}
+
+// teamsActionURI picks the default target of an OpenUri action
+func teamsActionURI(action TeamsAction) string {
+	for _, target := range action.Targets {
+		if target.OS == "" || target.OS == "default" {
+			return target.URI
+		}
+	}
+	if len(action.Targets) > 0 {
+		return action.Targets[0].URI
+	}
+	return ""
+}
+
+// fieldName returns a placeholder for empty field names, which Discord rejects
+func fieldName(name string) string {
+	if name == "" {
+		return zeroWidthSpace
+	}
+	return name
+}
+
+// fieldValue returns a placeholder for empty field values, which Discord rejects
+func fieldValue(value string) string {
+	if value == "" {
+		return zeroWidthSpace
+	}
+	return value
+}
+
+// lenientHexColor parses a hex color with or without the leading '#'.
+// Invalid colors are ignored.
+func lenientHexColor(color string) int {
+	if color == "" {
+		return 0
+	}
+	if !strings.HasPrefix(color, "#") {
+		color = "#" + color
+	}
+	value, err := hexToColorInt(color)
+	if err != nil {
+		return 0
+	}
+	return value
+}
@@ -0,0 +1,256 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// colorNames maps the color names accepted by the color helper to embed colors
+var colorNames = map[string]int{
+	"default": 0x000000,
+	"white":   0xFFFFFF,
+	"black":   0x23272A,
+	"blurple": 0x5865F2,
+	"red":     0xED4245,
+	"green":   0x57F287,
+	"yellow":  0xFEE75C,
+	"fuchsia": 0xEB459E,
+	"aqua":    0x1ABC9C,
+	"blue":    0x3498DB,
+	"purple":  0x9B59B6,
+	"gold":    0xF1C40F,
+	"orange":  0xE67E22,
+	"grey":    0x95A5A6,
+	"gray":    0x95A5A6,
+	"navy":    0x34495E,
+}
+
+// color returns the embed color for a color name or a #rrggbb hex string
+func color(name string) (int, error) {
+	if value, ok := colorNames[strings.ToLower(name)]; ok {
+		return value, nil
+	}
+	if len(name) == 7 && name[0] == '#' {
+		value, err := strconv.ParseInt(name[1:], 16, 32)
+		if err == nil {
+			return int(value), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown color %q", name)
+}
+
+// toTime converts a time.Time, Unix seconds or an RFC3339 string into a time
+func toTime(v any) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case *time.Time:
+		return *t, nil
+	case string:
+		parsed, err := time.Parse(time.RFC3339, t)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid time %q: %v", t, err)
+		}
+		return parsed, nil
+	}
+	seconds, err := toFloat(v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	whole, frac := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(frac*1e9)), nil
+}
+
+// toFloat converts the numeric types produced by Go code and JSON decoding into a float
+func toFloat(v any) (float64, error) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case int32:
+		return float64(n), nil
+	case uint:
+		return float64(n), nil
+	case uint64:
+		return float64(n), nil
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case json.Number:
+		return n.Float64()
+	case string:
+		return strconv.ParseFloat(n, 64)
+	case time.Duration:
+		return n.Seconds(), nil
+	default:
+		return 0, fmt.Errorf("%v (%T) is not a number", v, v)
+	}
+}
+
+// timestamp formats a time as the ISO8601 timestamp used by embeds
+func timestamp(v any) (string, error) {
+	t, err := toTime(v)
+	if err != nil {
+		return "", err
+	}
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+// discordTime formats a time as a Discord timestamp tag such as <t:1700000000:R>,
+// which every client renders in its own timezone. The style defaults to "f".
+func discordTime(v any, style ...string) (string, error) {
+	t, err := toTime(v)
+	if err != nil {
+		return "", err
+	}
+	if len(style) > 0 && style[0] != "" {
+		return fmt.Sprintf("<t:%d:%s>", t.Unix(), style[0]), nil
+	}
+	return fmt.Sprintf("<t:%d>", t.Unix()), nil
+}
+
+// duration renders a time.Duration, a duration string or a number of seconds
+// in a compact human form such as "2h 5m" or "42s"
+func duration(v any) (string, error) {
+	var d time.Duration
+	switch value := v.(type) {
+	case time.Duration:
+		d = value
+	case string:
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			seconds, convErr := strconv.ParseFloat(value, 64)
+			if convErr != nil {
+				return "", fmt.Errorf("invalid duration %q", value)
+			}
+			parsed = time.Duration(seconds * float64(time.Second))
+		}
+		d = parsed
+	default:
+		seconds, err := toFloat(v)
+		if err != nil {
+			return "", err
+		}
+		d = time.Duration(seconds * float64(time.Second))
+	}
+	return humanizeDuration(d), nil
+}
+
+// humanizeDuration renders the two most significant units of a duration
+func humanizeDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + humanizeDuration(-d)
+	}
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+
+	units := []struct {
+		suffix string
+		size   time.Duration
+	}{
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	}
+	var parts []string
+	for _, unit := range units {
+		if d >= unit.size {
+			parts = append(parts, fmt.Sprintf("%d%s", d/unit.size, unit.suffix))
+			d %= unit.size
+		}
+		if len(parts) == 2 {
+			break
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// byteSize renders a byte count with binary units, such as "1.5 MiB"
+func byteSize(v any) (string, error) {
+	n, err := toFloat(v)
+	if err != nil {
+		return "", err
+	}
+	const unit = 1024
+	if math.Abs(n) < unit {
+		return fmt.Sprintf("%d B", int64(n)), nil
+	}
+	exp := 0
+	for math.Abs(n) >= unit && exp < 6 {
+		n /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", n, "KMGTPE"[exp-1]), nil
+}
+
+// comma renders a number with thousands separators, such as "1,234,567"
+func comma(v any) (string, error) {
+	n, err := toFloat(v)
+	if err != nil {
+		return "", err
+	}
+	sign := ""
+	if n < 0 {
+		sign, n = "-", -n
+	}
+	whole, frac := math.Modf(n)
+	digits := strconv.FormatFloat(whole, 'f', 0, 64)
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	if frac != 0 {
+		b.WriteString(strings.TrimPrefix(strconv.FormatFloat(frac, 'f', -1, 64), "0"))
+	}
+	return sign + b.String(), nil
+}
+
+// ago renders how long ago a time was, such as "5 minutes ago"
+func ago(v any) (string, error) {
+	t, err := toTime(v)
+	if err != nil {
+		return "", err
+	}
+	d := time.Since(t)
+	if d < 0 {
+		return "in " + relative(-d), nil
+	}
+	if d < time.Minute {
+		return "just now", nil
+	}
+	return relative(d) + " ago", nil
+}
+
+// relative renders the largest whole unit of a duration
+func relative(d time.Duration) string {
+	units := []struct {
+		name string
+		size time.Duration
+	}{
+		{"year", 365 * 24 * time.Hour},
+		{"month", 30 * 24 * time.Hour},
+		{"day", 24 * time.Hour},
+		{"hour", time.Hour},
+		{"minute", time.Minute},
+	}
+	for _, unit := range units {
+		if n := int(d / unit.size); n >= 1 {
+			if n == 1 {
+				return "1 " + unit.name
+			}
+			return fmt.Sprintf("%d %ss", n, unit.name)
+		}
+	}
+	return "less than a minute"
+}
@@ -0,0 +1,120 @@
+package templates
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+// Localized holds a registry of payload templates per language, so one code path
+// can send the same notification in the language of each server:
+//
+//	// notifications/en/deploy.tmpl, notifications/de/deploy.tmpl, ...
+//	localized, err := templates.ParseLocalizedFS(notifications, "notifications", "en")
+//	payload, err := localized.Execute("de-AT", "deploy", data)
+//
+// A template missing in the requested language falls back to its base language,
+// such as "de" for "de-AT", and then to the fallback language.
+type Localized struct {
+	fallback   string
+	registries map[string]*Registry
+}
+
+// NewLocalized creates an empty set of localized templates that falls back to the language
+func NewLocalized(fallback string) *Localized {
+	return &Localized{
+		fallback:   normalizeLanguage(fallback),
+		registries: make(map[string]*Registry),
+	}
+}
+
+// ParseLocalizedFS creates localized templates from the subdirectories of root in
+// fsys, each named after a language such as "en" or "pt-BR" and holding files
+// loaded like ParseFS does
+func ParseLocalizedFS(fsys fs.FS, root, fallback string) (*Localized, error) {
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read localized templates: %v", err)
+	}
+	l := NewLocalized(fallback)
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		registry, err := ParseFS(fsys, path.Join(root, entry.Name(), "*"))
+		if err != nil {
+			return nil, fmt.Errorf("language %s: %v", entry.Name(), err)
+		}
+		l.registries[normalizeLanguage(entry.Name())] = registry
+	}
+	if _, ok := l.registries[l.fallback]; !ok {
+		return nil, fmt.Errorf("no templates for the fallback language %s", fallback)
+	}
+	return l, nil
+}
+
+// Add registers a template for the language. Names must be unique within a language.
+func (l *Localized) Add(language string, t *Template) error {
+	language = normalizeLanguage(language)
+	registry, ok := l.registries[language]
+	if !ok {
+		registry = NewRegistry()
+		l.registries[language] = registry
+	}
+	return registry.Add(t)
+}
+
+// Languages returns the languages with templates in sorted order
+func (l *Localized) Languages() []string {
+	languages := make([]string, 0, len(l.registries))
+	for language := range l.registries {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Lookup returns the named template in the first language of the fallback chain that has it
+func (l *Localized) Lookup(language, name string) (*Template, bool) {
+	for _, candidate := range l.chain(language) {
+		if registry, ok := l.registries[candidate]; ok {
+			if t, ok := registry.Lookup(name); ok {
+				return t, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Execute renders the named template of the language, or of the first language
+// of its fallback chain that has it, against data into a validated webhook
+func (l *Localized) Execute(language, name string, data any) (webhook.Webhook, error) {
+	t, ok := l.Lookup(language, name)
+	if !ok {
+		return webhook.Webhook{}, fmt.Errorf("template %s is not registered for %s or the fallback language %s", name, language, l.fallback)
+	}
+	return t.Execute(data)
+}
+
+// chain returns the languages to try for a language: itself, its base languages and the fallback
+func (l *Localized) chain(language string) []string {
+	var chain []string
+	for language = normalizeLanguage(language); language != ""; {
+		chain = append(chain, language)
+		i := strings.LastIndex(language, "-")
+		if i < 0 {
+			break
+		}
+		language = language[:i]
+	}
+	return append(chain, l.fallback)
+}
+
+// normalizeLanguage lowercases a language tag and uses dashes, so "pt_BR" matches "pt-br"
+func normalizeLanguage(language string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(language), "_", "-"))
+}
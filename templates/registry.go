@@ -0,0 +1,127 @@
+package templates
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+// Registry holds named payload templates, typically loaded from an embed.FS so
+// binaries ship their notification templates compiled in:
+//
+//	//go:embed notifications
+//	var notifications embed.FS
+//
+//	registry, err := templates.ParseFS(notifications, "notifications/*")
+//	payload, err := registry.Execute("deploy", data)
+type Registry struct {
+	templates map[string]*Template
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{templates: make(map[string]*Template)}
+}
+
+// ParseFS creates a registry from the files in fsys matching the patterns, using
+// the syntax of fs.Glob. Each file is registered under its base name without the
+// extension, so "notifications/deploy.tmpl" becomes "deploy". Files ending in .json
+// are static payload definitions and are validated when loaded, as are files in a
+// format registered with webhook.RegisterFileFormat; any other file is parsed as a
+// payload template. Import the yamlwebhook module to load YAML definitions (.yaml
+// and .yml); without it, they are rejected.
+func ParseFS(fsys fs.FS, patterns ...string) (*Registry, error) {
+	r := NewRegistry()
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("pattern %q matches no files", pattern)
+		}
+		for _, match := range matches {
+			if err := r.parseFile(fsys, match); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return r, nil
+}
+
+// parseFile registers a single file of the file system
+func (r *Registry) parseFile(fsys fs.FS, file string) error {
+	info, err := fs.Stat(fsys, file)
+	if err != nil {
+		return fmt.Errorf("failed to read template: %v", err)
+	}
+	if info.IsDir() {
+		return nil
+	}
+
+	data, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return fmt.Errorf("failed to read template: %v", err)
+	}
+
+	ext := path.Ext(file)
+	name := strings.TrimSuffix(path.Base(file), ext)
+	var t *Template
+	converted, registered, err := webhook.FileToJSON(file, data)
+	switch ext := strings.ToLower(ext); {
+	case ext == ".json":
+		t, err = staticTemplate(name, data)
+	case registered:
+		if err != nil {
+			return fmt.Errorf("failed to parse payload %s: %v", file, err)
+		}
+		t, err = staticTemplate(name, converted)
+	case ext == ".yaml" || ext == ".yml":
+		// Rejected instead of parsed as a template, which would render YAML that is not a payload
+		return fmt.Errorf("payload %s: YAML payload definitions need the yamlwebhook module", file)
+	default:
+		t, err = Parse(name, string(data))
+	}
+	if err != nil {
+		return err
+	}
+	return r.Add(t)
+}
+
+// Add registers a template under its name. Names must be unique.
+func (r *Registry) Add(t *Template) error {
+	if _, exists := r.templates[t.Name()]; exists {
+		return fmt.Errorf("template %s is already registered", t.Name())
+	}
+	r.templates[t.Name()] = t
+	return nil
+}
+
+// Lookup returns the template registered under the name
+func (r *Registry) Lookup(name string) (*Template, bool) {
+	t, ok := r.templates[name]
+	return t, ok
+}
+
+// Names returns the names of all registered templates in sorted order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.templates))
+	for name := range r.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Execute renders the named template against data into a validated webhook
+func (r *Registry) Execute(name string, data any) (webhook.Webhook, error) {
+	t, ok := r.Lookup(name)
+	if !ok {
+		return webhook.Webhook{}, fmt.Errorf("template %s is not registered", name)
+	}
+	return t.Execute(data)
+}
@@ -0,0 +1,61 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+func TestParseFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"notifications/deploy.tmpl": {Data: []byte(`{"content": {{json .version}}}`)},
+		"notifications/static.json": {Data: []byte(`{"content": "maintenance tonight"}`)},
+	}
+	r, err := ParseFS(fsys, "notifications/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := r.Execute("deploy", map[string]any{"version": "1.2.3"})
+	if err != nil || payload.Content != "1.2.3" {
+		t.Errorf("deploy = %+v, %v", payload, err)
+	}
+	payload, err = r.Execute("static", nil)
+	if err != nil || payload.Content != "maintenance tonight" {
+		t.Errorf("static = %+v, %v", payload, err)
+	}
+}
+
+func TestParseFSRejectsYAML(t *testing.T) {
+	fsys := fstest.MapFS{"notifications/deploy.yaml": {Data: []byte("content: hello\n")}}
+	_, err := ParseFS(fsys, "notifications/*")
+	if err == nil || !strings.Contains(err.Error(), "YAML") {
+		t.Errorf("error = %v, want YAML to be rejected", err)
+	}
+}
+
+func TestParseFSRegisteredFormat(t *testing.T) {
+	// A format holding just the content, standing in for YAML
+	webhook.RegisterFileFormat(".txt", func(data []byte) ([]byte, error) {
+		if len(data) == 0 {
+			return nil, fmt.Errorf("empty file")
+		}
+		return []byte(fmt.Sprintf(`{"content": %q}`, bytes.TrimSpace(data))), nil
+	})
+
+	r, err := ParseFS(fstest.MapFS{"notifications/note.txt": {Data: []byte("maintenance tonight\n")}}, "notifications/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload, err := r.Execute("note", nil); err != nil || payload.Content != "maintenance tonight" {
+		t.Errorf("note = %+v, %v", payload, err)
+	}
+
+	_, err = ParseFS(fstest.MapFS{"notifications/blank.txt": {Data: nil}}, "notifications/*")
+	if err == nil || !strings.Contains(err.Error(), "blank.txt") {
+		t.Errorf("error = %v, want the conversion error of blank.txt", err)
+	}
+}
@@ -0,0 +1,80 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+// Template is a parsed payload template. Executing it renders the JSON form of a
+// webhook, decodes it and validates the result against Discord's limits.
+//
+// Besides the standard text/template functions, templates can use the helpers
+// returned by Funcs: json, truncate, default, join, upper, lower, color, now,
+// timestamp, discordTime, duration, bytes, comma, ago and emoji.
+type Template struct {
+	tmpl *template.Template
+
+	// static holds the payload of templates defined as plain JSON
+	static []byte
+}
+
+// Parse parses a payload template
+func Parse(name, text string) (*Template, error) {
+	tmpl, err := template.New(name).Funcs(Funcs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %v", name, err)
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// ParseFile parses the payload template stored in a file, named after the file
+func ParseFile(path string) (*Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %v", err)
+	}
+	return Parse(filepath.Base(path), string(data))
+}
+
+// Must panics if err is not nil. It is intended for templates parsed during initialization.
+func Must(t *Template, err error) *Template {
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Name returns the name of the template
+func (t *Template) Name() string {
+	return t.tmpl.Name()
+}
+
+// staticTemplate wraps a JSON payload definition, which renders the same payload for any data
+func staticTemplate(name string, data []byte) (*Template, error) {
+	if _, err := webhook.LoadWebhookJSON(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("payload %s: %v", name, err)
+	}
+	return &Template{tmpl: template.New(name), static: data}, nil
+}
+
+// Execute renders the template against data into a validated webhook.
+// Validation failures are returned as a *webhook.ValidationError.
+func (t *Template) Execute(data any) (webhook.Webhook, error) {
+	if t.static != nil {
+		return webhook.LoadWebhookJSON(bytes.NewReader(t.static))
+	}
+
+	payload, err := Render(t.tmpl, data)
+	if err != nil {
+		return webhook.Webhook{}, err
+	}
+	if err := webhook.ValidateWebhook(payload); err != nil {
+		return webhook.Webhook{}, err
+	}
+	return payload, nil
+}
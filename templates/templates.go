@@ -0,0 +1,103 @@
+// Package templates renders Go text/template templates into Discord webhook payloads.
+// A payload template renders the JSON form of a webhook; the json helper embeds
+// values as JSON literals so strings are escaped correctly:
+//
+//	{
+//	  "content": {{json (printf "%s deployed %s" .user .version)}},
+//	  "embeds": [{
+//	    "title": "Deploy finished",
+//	    "color": {{color "green"}},
+//	    "timestamp": {{json (timestamp now)}},
+//	    "fields": [{"name": "Duration", "value": {{json (duration .seconds)}}, "inline": true}]
+//	  }]
+//	}
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/emoji"
+)
+
+// Funcs returns the helper functions available to payload templates
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"json":     toJSON,
+		"truncate": truncate,
+		"default":  defaultValue,
+		"join":     join,
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+
+		"color":       color,
+		"now":         time.Now,
+		"timestamp":   timestamp,
+		"discordTime": discordTime,
+		"duration":    duration,
+		"bytes":       byteSize,
+		"comma":       comma,
+		"ago":         ago,
+		"emoji":       emojiByName,
+	}
+}
+
+// Render executes the template against data and decodes the output as a webhook payload
+func Render(tmpl *template.Template, data any) (webhook.Webhook, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return webhook.Webhook{}, fmt.Errorf("failed to render template: %v", err)
+	}
+
+	var payload webhook.Webhook
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		return webhook.Webhook{}, fmt.Errorf("template did not render a valid payload: %v", err)
+	}
+	return payload, nil
+}
+
+// toJSON renders a value as a JSON literal
+func toJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+// emojiByName returns the emoji named like its constant in the emoji package, such as "warning"
+func emojiByName(name string) (string, error) {
+	e, ok := emoji.Lookup(name)
+	if !ok {
+		return "", fmt.Errorf("unknown emoji %q", name)
+	}
+	return e, nil
+}
+
+// truncate shortens s to at most n runes
+func truncate(n int, s string) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// defaultValue returns fallback when v is nil or an empty string
+func defaultValue(fallback, v any) any {
+	if v == nil || v == "" {
+		return fallback
+	}
+	return v
+}
+
+// join joins the string forms of the values
+func join(sep string, values []any) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = fmt.Sprint(v)
+	}
+	return strings.Join(parts, sep)
+}
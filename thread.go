@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// StartThread creates a thread named name in the webhook's forum or media channel,
+// with the payload as its first post, and returns a client sending into the thread.
+// The thread client has the same options; close it separately when queueing messages.
+func (c *Client) StartThread(ctx context.Context, name string, webhookPayload Webhook) (*Client, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("thread name cannot be empty")
+	}
+	webhookPayload.ThreadName = name
+	message, err := c.execute(ctx, webhookPayload, true)
+	if err != nil {
+		return nil, err
+	}
+	// The first post of a thread lives in the thread, so its channel is the thread
+	if message.ChannelID == "" {
+		return nil, fmt.Errorf("the thread was not created: the message was spooled")
+	}
+	return c.InThread(message.ChannelID)
+}
+
+// InThread returns a client sending into an existing thread of the webhook's channel.
+// Messages it sends can be edited and deleted through it as well.
+func (c *Client) InThread(threadID string) (*Client, error) {
+	if threadID == "" {
+		return nil, fmt.Errorf("thread ID cannot be empty")
+	}
+	if c.provider != nil {
+		thread := newClient("", c.options)
+		thread.provider = threadProvider{parent: c.provider, threadID: threadID}
+		return thread, nil
+	}
+	threadURL, err := withQueryParam(c.webhookURL, "thread_id", threadID)
+	if err != nil {
+		return nil, err
+	}
+	return c.withURL(threadURL), nil
+}
+
+// ThreadID returns the thread the client sends into, or an empty string for the channel itself
+func (c *Client) ThreadID() string {
+	if provider, ok := c.provider.(threadProvider); ok {
+		return provider.threadID
+	}
+	return parseThreadID(c.URL())
+}
@@ -0,0 +1,49 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"testing"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/webhooktest"
+)
+
+func TestInThreadWithProviderIsNotPublished(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	provider := webhook.URLProviderFunc(func(context.Context) (string, error) { return server.URL, nil })
+	parent := webhook.NewClientFromProvider(provider, webhook.WithExpvar("thread-test"))
+	ctx := context.Background()
+
+	if err := parent.Send(ctx, webhook.Webhook{Content: "in the channel"}); err != nil {
+		t.Fatal(err)
+	}
+	thread, err := parent.InThread("42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := thread.Send(ctx, webhook.Webhook{Content: "in the thread"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	requests := server.Requests()
+	if got := requests[len(requests)-1].Query.Get("thread_id"); got != "42" {
+		t.Errorf("thread_id = %q, want 42", got)
+	}
+	if thread.ThreadID() != "42" {
+		t.Errorf("ThreadID = %q, want 42", thread.ThreadID())
+	}
+
+	published := expvar.Get(webhook.ExpvarMapName).(*expvar.Map).Get("thread-test")
+	var stats webhook.Stats
+	if err := json.Unmarshal([]byte(published.String()), &stats); err != nil {
+		t.Fatal(err)
+	}
+	if stats.Sent != 1 {
+		t.Errorf("published client sent %d messages, want the parent's 1", stats.Sent)
+	}
+}
@@ -0,0 +1,149 @@
+package webhook
+
+import (
+	"fmt"
+	"path"
+	"sync"
+	"time"
+)
+
+// ThrottleMode selects what happens to messages arriving too soon after the previous one with the same key
+type ThrottleMode int
+
+const (
+	// ThrottleDrop discards the extra messages
+	ThrottleDrop ThrottleMode = iota
+
+	// ThrottleDigest holds back the extra messages and, once the interval has passed,
+	// sends the latest one with a note saying how many were suppressed
+	ThrottleDigest
+)
+
+// throttleRule is a minimum interval for keys matching a pattern
+type throttleRule struct {
+	pattern  string
+	interval time.Duration
+	mode     ThrottleMode
+}
+
+// throttleState tracks a single key
+type throttleState struct {
+	rule       *throttleRule
+	last       time.Time
+	suppressed int
+	latest     *queuedMessage
+	timer      *time.Timer
+}
+
+// throttler enforces minimum intervals between queued messages sharing a key
+type throttler struct {
+	mu    sync.Mutex
+	rules []*throttleRule
+	keys  map[string]*throttleState
+}
+
+// WithThrottle enforces a minimum interval between queued messages whose key, set
+// with WithKey, matches the pattern. Patterns use path.Match syntax, such as
+// "disk-space:*". When several rules match a key, the first one added applies.
+// Messages without a key are never throttled.
+func WithThrottle(pattern string, interval time.Duration, mode ThrottleMode) ClientOption {
+	return func(c *Client) {
+		c.throttle.rules = append(c.throttle.rules, &throttleRule{pattern: pattern, interval: interval, mode: mode})
+	}
+}
+
+// rule returns the first rule matching the key
+func (t *throttler) rule(key string) *throttleRule {
+	for _, rule := range t.rules {
+		if ok, _ := path.Match(rule.pattern, key); ok {
+			return rule
+		}
+	}
+	return nil
+}
+
+// allow reports whether the message may be queued now. Messages held back for a
+// digest are queued by a timer once the interval has passed.
+func (t *throttler) allow(c *Client, message *queuedMessage) bool {
+	key := message.options.key
+	if key == "" || len(t.rules) == 0 {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, ok := t.keys[key]
+	if !ok {
+		rule := t.rule(key)
+		if rule == nil {
+			return true
+		}
+		if t.keys == nil {
+			t.keys = make(map[string]*throttleState)
+		}
+		state = &throttleState{rule: rule}
+		t.keys[key] = state
+	}
+
+	now := time.Now()
+	if now.Sub(state.last) >= state.rule.interval && state.timer == nil {
+		state.last = now
+		return true
+	}
+
+	state.suppressed++
+	if state.rule.mode != ThrottleDigest {
+		message.finish(SendResult{Suppressed: true})
+		return false
+	}
+	// The latest message is sent for the held back ones
+	if state.latest != nil {
+		message.absorb(state.latest)
+	}
+	state.latest = message
+	if state.timer == nil {
+		state.timer = time.AfterFunc(time.Until(state.last.Add(state.rule.interval)), func() {
+			t.flush(c, key)
+		})
+	}
+	return false
+}
+
+// flush queues the latest held back message of a key with a note about the suppressed ones
+func (t *throttler) flush(c *Client, key string) {
+	t.mu.Lock()
+	state := t.keys[key]
+	message, suppressed := state.latest, state.suppressed
+	state.latest, state.suppressed, state.timer = nil, 0, nil
+	state.last = time.Now()
+	t.mu.Unlock()
+
+	if message == nil {
+		return
+	}
+	switch suppressed {
+	case 0, 1:
+	case 2:
+		message.payload = withNote(message.payload, "-# 1 earlier similar message was suppressed")
+	default:
+		message.payload = withNote(message.payload, fmt.Sprintf("-# %d earlier similar messages were suppressed", suppressed-1))
+	}
+	// Only fails when the client was closed in the meantime
+	_ = c.enqueue(message)
+}
+
+// flushAll queues every held back message immediately, so Close delivers them
+func (t *throttler) flushAll(c *Client) {
+	t.mu.Lock()
+	var keys []string
+	for key, state := range t.keys {
+		if state.timer != nil && state.timer.Stop() {
+			keys = append(keys, key)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, key := range keys {
+		t.flush(c, key)
+	}
+}
@@ -0,0 +1,99 @@
+package webhook_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/webhooktest"
+)
+
+func TestThrottleDrop(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	client := webhook.NewClient(server.URL, webhook.WithThrottle("disk-space:*", time.Hour, webhook.ThrottleDrop))
+
+	first, err := client.Enqueue(webhook.Webhook{Content: "disk 91% full"}, webhook.WithKey("disk-space:host1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := client.Enqueue(webhook.Webhook{Content: "disk 92% full"}, webhook.WithKey("disk-space:host1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := client.Enqueue(webhook.Webhook{Content: "disk 95% full"}, webhook.WithKey("disk-space:host2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unkeyed, err := client.Enqueue(webhook.Webhook{Content: "deploy finished"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result := result(t, second); !result.Suppressed {
+		t.Errorf("second message result = %+v, want it suppressed", result)
+	}
+	for _, delivery := range []*webhook.Delivery{first, other, unkeyed} {
+		if result := result(t, delivery); result.Err != nil || result.Suppressed {
+			t.Errorf("result = %+v, want it delivered", result)
+		}
+	}
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	server.AssertRequestCount(t, 3)
+}
+
+func TestThrottleDigest(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	client := webhook.NewClient(server.URL, webhook.WithThrottle("cpu", 100*time.Millisecond, webhook.ThrottleDigest))
+	defer client.Close(context.Background())
+
+	var deliveries []*webhook.Delivery
+	for _, content := range []string{"cpu 90%", "cpu 93%", "cpu 95%", "cpu 97%"} {
+		delivery, err := client.Enqueue(webhook.Webhook{Content: content}, webhook.WithKey("cpu"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	for _, delivery := range deliveries {
+		if result := result(t, delivery); result.Err != nil || result.Suppressed {
+			t.Errorf("result = %+v, want it delivered with the digest", result)
+		}
+	}
+
+	got := contents(server)
+	if len(got) != 2 || got[0] != "cpu 90%" {
+		t.Fatalf("sent %q, want the first message and a digest", got)
+	}
+	if want := "cpu 97%\n-# 2 earlier similar messages were suppressed"; got[1] != want {
+		t.Errorf("digest = %q, want %q", got[1], want)
+	}
+}
+
+func TestThrottleDigestFlushedOnClose(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	client := webhook.NewClient(server.URL, webhook.WithThrottle("cpu", time.Hour, webhook.ThrottleDigest))
+
+	for _, content := range []string{"cpu 90%", "cpu 93%"} {
+		if _, err := client.Enqueue(webhook.Webhook{Content: content}, webhook.WithKey("cpu")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	start := time.Now()
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Close waited %v for the throttle interval", elapsed)
+	}
+	got := contents(server)
+	if len(got) != 2 || strings.Contains(got[1], "suppressed") {
+		t.Errorf("sent %q, want both messages without a note", got)
+	}
+}
@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimestampStyle selects how Discord renders a timestamp tag; see DiscordTimestamp
+type TimestampStyle string
+
+const (
+	TimestampShortTime     TimestampStyle = "t" // 16:20
+	TimestampLongTime      TimestampStyle = "T" // 16:20:30
+	TimestampShortDate     TimestampStyle = "d" // 20/04/2021
+	TimestampLongDate      TimestampStyle = "D" // 20 April 2021
+	TimestampShortDateTime TimestampStyle = "f" // 20 April 2021 16:20
+	TimestampLongDateTime  TimestampStyle = "F" // Tuesday, 20 April 2021 16:20
+	TimestampRelative      TimestampStyle = "R" // 2 months ago
+)
+
+// DiscordTimestamp returns a timestamp tag such as <t:1618932000:R>, which every reader
+// sees in their own locale and timezone. Tags render in content, descriptions and field
+// values, but not in titles, footers or author names; use a TimeFormat there.
+func DiscordTimestamp(t time.Time, style TimestampStyle) string {
+	return fmt.Sprintf("<t:%d:%s>", t.Unix(), style)
+}
+
+// AbsoluteAndRelative returns timestamp tags showing both the date and time and how
+// long ago it was, such as "20 April 2021 16:20 (2 months ago)"
+func AbsoluteAndRelative(t time.Time) string {
+	return DiscordTimestamp(t, TimestampShortDateTime) + " (" + DiscordTimestamp(t, TimestampRelative) + ")"
+}
+
+// localeLayouts are the date and time layouts of the supported locales
+var localeLayouts = map[string]string{
+	"en-us": "Jan 2, 2006 3:04 PM MST",
+	"en-gb": "2 Jan 2006 15:04 MST",
+	"en-au": "2 Jan 2006 15:04 MST",
+	"en-in": "2 Jan 2006 15:04 MST",
+	"en":    "Jan 2, 2006 3:04 PM MST",
+	"de":    "02.01.2006 15:04 MST",
+	"fr":    "02/01/2006 15:04 MST",
+	"es":    "02/01/2006 15:04 MST",
+	"it":    "02/01/2006 15:04 MST",
+	"pt-br": "02/01/2006 15:04 MST",
+	"pt":    "02/01/2006 15:04 MST",
+	"nl":    "02-01-2006 15:04 MST",
+	"pl":    "02.01.2006 15:04 MST",
+	"ru":    "02.01.2006 15:04 MST",
+	"sv":    "2006-01-02 15:04 MST",
+	"ja":    "2006/01/02 15:04 MST",
+	"zh":    "2006/01/02 15:04 MST",
+	"ko":    "2006. 01. 02. 15:04 MST",
+	"iso":   "2006-01-02 15:04 MST",
+}
+
+// TimeFormat formats absolute times for a locale and timezone, for the parts of a
+// message where Discord does not render timestamp tags, such as footers
+type TimeFormat struct {
+	// Layout is a time layout such as "02.01.2006 15:04 MST"
+	Layout string
+	// Location is the timezone times are shown in. It defaults to UTC.
+	Location *time.Location
+}
+
+// LocaleTimeFormat returns the date and time format of a locale such as "de-DE",
+// "en-GB" or "ja" in the timezone. Regions without a format of their own fall back
+// to their language, and "iso" selects "2006-01-02 15:04 MST".
+func LocaleTimeFormat(locale string, location *time.Location) (TimeFormat, error) {
+	key := strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	layout, ok := localeLayouts[key]
+	if !ok {
+		language, _, _ := strings.Cut(key, "-")
+		if layout, ok = localeLayouts[language]; !ok {
+			return TimeFormat{}, fmt.Errorf("unsupported locale %q", locale)
+		}
+	}
+	return TimeFormat{Layout: layout, Location: location}, nil
+}
+
+// Format formats the time in the format's timezone
+func (f TimeFormat) Format(t time.Time) string {
+	location := f.Location
+	if location == nil {
+		location = time.UTC
+	}
+	return t.In(location).Format(f.Layout)
+}
+
+// FormatWithRelative formats the time followed by how long before or after now it is,
+// such as "14.10.2026 09:30 CEST (3 hours ago)". The relative part is in English.
+func (f TimeFormat) FormatWithRelative(t, now time.Time) string {
+	return f.Format(t) + " (" + relativeTime(t, now) + ")"
+}
+
+// relativeTime describes in English how long before or after now the time is, in its largest unit
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	if d < time.Minute {
+		return "just now"
+	}
+
+	var text string
+	switch {
+	case d < time.Hour:
+		text = pluralize(int(d/time.Minute), "minute")
+	case d < 24*time.Hour:
+		text = pluralize(int(d/time.Hour), "hour")
+	case d < 30*24*time.Hour:
+		text = pluralize(int(d/(24*time.Hour)), "day")
+	case d < 365*24*time.Hour:
+		text = pluralize(int(d/(30*24*time.Hour)), "month")
+	default:
+		text = pluralize(int(d/(365*24*time.Hour)), "year")
+	}
+	if future {
+		return "in " + text
+	}
+	return text + " ago"
+}
@@ -0,0 +1,79 @@
+package webhook
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+)
+
+// maxRedirects is how many redirects SameHostRedirects follows, like the http package does
+const maxRedirects = 10
+
+// WithTLSConfig sets the TLS configuration of requests, such as a root pool with the
+// private CA of a TLS-intercepting proxy, or a client certificate for mutual TLS with
+// an internal relay. The transport of the HTTP client, http.DefaultTransport unless
+// set with WithHTTPClient, is cloned for it and must be an *http.Transport.
+func WithTLSConfig(config *tls.Config) ClientOption {
+	return func(c *Client) {
+		c.tlsConfig = config
+	}
+}
+
+// WithRedirectPolicy sets how redirects are followed, as the CheckRedirect function
+// of the HTTP client. Discord does not redirect webhook requests, so a redirect points
+// at a proxy or a misconfigured URL; NoRedirects and SameHostRedirects are stricter
+// than Go's default of following up to 10 redirects to any host, which can leak the
+// payload.
+func WithRedirectPolicy(policy func(req *http.Request, via []*http.Request) error) ClientOption {
+	return func(c *Client) {
+		c.redirectPolicy = policy
+	}
+}
+
+// NoRedirects is a redirect policy that follows no redirects. Requests that are
+// redirected fail with an *APIError carrying the redirect's status.
+func NoRedirects(req *http.Request, via []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// SameHostRedirects is a redirect policy that follows up to 10 redirects on the host
+// of the original request, refusing those to other hosts or from HTTPS to HTTP
+func SameHostRedirects(req *http.Request, via []*http.Request) error {
+	original := via[0].URL
+	switch {
+	case len(via) >= maxRedirects:
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	case req.URL.Host != original.Host:
+		return fmt.Errorf("refusing to follow a redirect from %s to %s", original.Host, req.URL.Host)
+	case original.Scheme == "https" && req.URL.Scheme != "https":
+		return fmt.Errorf("refusing to follow a redirect from HTTPS to %s", req.URL.Scheme)
+	}
+	return nil
+}
+
+// configureHTTPClient applies the TLS configuration and redirect policy to a copy of
+// the client's HTTP client, leaving the one passed to WithHTTPClient unchanged
+func (c *Client) configureHTTPClient() error {
+	if c.tlsConfig == nil && c.redirectPolicy == nil {
+		return nil
+	}
+	httpClient := *c.httpClient
+	if c.redirectPolicy != nil {
+		httpClient.CheckRedirect = c.redirectPolicy
+	}
+	if c.tlsConfig != nil {
+		transport := httpClient.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		httpTransport, ok := transport.(*http.Transport)
+		if !ok {
+			return fmt.Errorf("WithTLSConfig needs an *http.Transport, but the HTTP client uses a %T", transport)
+		}
+		httpTransport = httpTransport.Clone()
+		httpTransport.TLSClientConfig = c.tlsConfig.Clone()
+		httpClient.Transport = httpTransport
+	}
+	c.httpClient = &httpClient
+	return nil
+}
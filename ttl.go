@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"context"
+	"time"
+)
+
+// ttlDeleteTimeout bounds the request deleting an expired message
+const ttlDeleteTimeout = 10 * time.Second
+
+// WithTTL deletes the queued message once ttl has passed after it was delivered,
+// such as for a "deploy in progress" notice. Failed deletes are reported to the
+// error handler. Messages with a TTL are never collapsed.
+func WithTTL(ttl time.Duration) SendOption {
+	return func(o *sendOptions) {
+		o.ttl = ttl
+	}
+}
+
+// SendWithTTL sends the payload and deletes the message once ttl has passed. The
+// delete runs in the background for as long as the process does; a message that
+// was spooled is not deleted.
+func (c *Client) SendWithTTL(ctx context.Context, webhookPayload Webhook, ttl time.Duration) (Message, error) {
+	message, err := c.execute(ctx, webhookPayload, true)
+	if err != nil || message.ID == "" {
+		return message, err
+	}
+	time.AfterFunc(ttl, func() {
+		ctx, cancel := context.WithTimeout(context.Background(), ttlDeleteTimeout)
+		defer cancel()
+		err := c.Delete(ctx, message.ID)
+		// Someone deleted the message already
+		if err != nil && !isNotFound(err) && c.queue.onError != nil {
+			c.queue.onError(webhookPayload, err)
+		}
+	})
+	return message, nil
+}
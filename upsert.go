@@ -0,0 +1,299 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// MessageStore remembers the message posted for each key by Upsert. Implementations
+// must be safe for concurrent use.
+type MessageStore interface {
+	// Load returns the message ID stored for the key, or an empty string if there is none
+	Load(key string) (string, error)
+	Store(key, messageID string) error
+	Delete(key string) error
+}
+
+// PayloadStore is a MessageStore that also keeps the payload Upsert last sent for
+// each key, so edits that would not change the message are skipped after a restart
+// too. FileStore implements it; with other stores, Upsert remembers the payloads in
+// memory.
+type PayloadStore interface {
+	MessageStore
+	// LoadPayload returns the payload stored for the key and message, and false if there is none
+	LoadPayload(key, messageID string) (Webhook, bool, error)
+	StorePayload(key, messageID string, webhookPayload Webhook) error
+}
+
+// WithMessageStore sets the store Upsert keeps message IDs in. It defaults to an
+// in-memory store, which forgets the messages when the process exits.
+func WithMessageStore(store MessageStore) ClientOption {
+	return func(c *Client) {
+		c.messages = store
+	}
+}
+
+// Upsert edits the message previously posted for the key, or posts a new one if
+// there is none or it was deleted, so a single message per key stays current,
+// such as the build status of each branch. Edits of payloads Equal to the one last
+// sent for the key are skipped; see PayloadStore.
+func (c *Client) Upsert(ctx context.Context, key string, webhookPayload Webhook) error {
+	c.upsertMu.Lock()
+	defer c.upsertMu.Unlock()
+
+	messageID, err := c.messages.Load(key)
+	if err != nil {
+		return fmt.Errorf("failed to load message for key %q: %v", key, err)
+	}
+	if messageID != "" {
+		// Skip edits that would not change the message
+		last, ok, err := c.lastUpserted(key, messageID)
+		if err != nil {
+			return fmt.Errorf("failed to load payload for key %q: %v", key, err)
+		}
+		if ok && last.Equal(webhookPayload) {
+			return nil
+		}
+		err = c.Edit(ctx, messageID, webhookPayload)
+		if err == nil {
+			return c.rememberUpserted(key, messageID, webhookPayload)
+		}
+		if !isNotFound(err) {
+			return err
+		}
+		delete(c.upserted, key)
+		if err := c.messages.Delete(key); err != nil {
+			return fmt.Errorf("failed to forget message for key %q: %v", key, err)
+		}
+	}
+
+	message, err := c.execute(ctx, webhookPayload, true)
+	if err != nil {
+		return err
+	}
+	// Spooled messages have no ID yet
+	if message.ID == "" {
+		return nil
+	}
+	if err := c.messages.Store(key, message.ID); err != nil {
+		return fmt.Errorf("failed to store message for key %q: %v", key, err)
+	}
+	return c.rememberUpserted(key, message.ID, webhookPayload)
+}
+
+// upserted is the payload Upsert last sent for a key
+type upserted struct {
+	messageID string
+	payload   Webhook
+}
+
+// lastUpserted returns the payload last sent for the key, if it went to the message
+func (c *Client) lastUpserted(key, messageID string) (Webhook, bool, error) {
+	if store, ok := c.messages.(PayloadStore); ok {
+		return store.LoadPayload(key, messageID)
+	}
+	last, ok := c.upserted[key]
+	if !ok || last.messageID != messageID {
+		return Webhook{}, false, nil
+	}
+	return last.payload, true, nil
+}
+
+// rememberUpserted keeps a copy of the payload sent for the key, which the caller
+// may change once Upsert returned
+func (c *Client) rememberUpserted(key, messageID string, webhookPayload Webhook) error {
+	if store, ok := c.messages.(PayloadStore); ok {
+		if err := store.StorePayload(key, messageID, webhookPayload); err != nil {
+			return fmt.Errorf("failed to store payload for key %q: %v", key, err)
+		}
+		return nil
+	}
+	data, err := json.Marshal(webhookPayload)
+	var payload Webhook
+	if err == nil {
+		err = json.Unmarshal(data, &payload)
+	}
+	if err != nil {
+		// Without a copy, the next upsert edits the message again
+		delete(c.upserted, key)
+		return nil
+	}
+	c.upserted[key] = upserted{messageID: messageID, payload: payload}
+	return nil
+}
+
+// MemoryStore is a MessageStore keeping message IDs in memory
+type MemoryStore struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{ids: make(map[string]string)}
+}
+
+// Load returns the message ID stored for the key
+func (s *MemoryStore) Load(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ids[key], nil
+}
+
+// Store stores the message ID for the key
+func (s *MemoryStore) Store(key, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ids[key] = messageID
+	return nil
+}
+
+// Delete forgets the key
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.ids, key)
+	return nil
+}
+
+// FileStore is a MessageStore keeping message IDs in a JSON file, so status messages
+// survive restarts and can be shared by runs of a cron job. It also keeps the payloads
+// last sent, so Upsert skips edits that would not change a message across restarts.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// fileStoreEntry is the message of a key in a FileStore
+type fileStoreEntry struct {
+	MessageID string   `json:"message_id"`
+	Payload   *Webhook `json:"payload,omitempty"`
+}
+
+// UnmarshalJSON also accepts the bare message IDs stores used to keep
+func (e *fileStoreEntry) UnmarshalJSON(data []byte) error {
+	var messageID string
+	if json.Unmarshal(data, &messageID) == nil {
+		*e = fileStoreEntry{MessageID: messageID}
+		return nil
+	}
+	type plain fileStoreEntry
+	return json.Unmarshal(data, (*plain)(e))
+}
+
+// NewFileStore creates a store backed by the JSON file at path, which is created on the first Store
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load returns the message ID stored for the key
+func (s *FileStore) Load(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	return entries[key].MessageID, nil
+}
+
+// Store stores the message ID for the key
+func (s *FileStore) Store(key, messageID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	entries[key] = fileStoreEntry{MessageID: messageID}
+	return s.write(entries)
+}
+
+// LoadPayload returns the payload stored for the key, if it was sent to the message
+func (s *FileStore) LoadPayload(key, messageID string) (Webhook, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.read()
+	if err != nil {
+		return Webhook{}, false, err
+	}
+	entry := entries[key]
+	if entry.MessageID != messageID || entry.Payload == nil {
+		return Webhook{}, false, nil
+	}
+	return *entry.Payload, true, nil
+}
+
+// StorePayload stores the message ID and the payload sent to it for the key
+func (s *FileStore) StorePayload(key, messageID string, webhookPayload Webhook) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	entries[key] = fileStoreEntry{MessageID: messageID, Payload: &webhookPayload}
+	return s.write(entries)
+}
+
+// Delete forgets the key
+func (s *FileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := entries[key]; !ok {
+		return nil
+	}
+	delete(entries, key)
+	return s.write(entries)
+}
+
+// read loads the stored entries; a missing file is an empty store
+func (s *FileStore) read() (map[string]fileStoreEntry, error) {
+	entries := make(map[string]fileStoreEntry)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message store: %v", err)
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse message store %s: %v", s.path, err)
+	}
+	return entries, nil
+}
+
+// write replaces the file atomically, so a crash never leaves it half written
+func (s *FileStore) write(entries map[string]fileStoreEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal message store: %v", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".messages-*")
+	if err != nil {
+		return fmt.Errorf("failed to write message store: %v", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write message store: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write message store: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write message store: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package webhook_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/webhooktest"
+)
+
+// methods lists the method and path of the requests, relative to the webhook
+func methods(server *webhooktest.Server) []string {
+	var methods []string
+	for _, request := range server.Requests() {
+		path := request.Path[strings.Index(request.Path, webhooktest.WebhookToken)+len(webhooktest.WebhookToken):]
+		methods = append(methods, request.Method+" "+path)
+	}
+	return methods
+}
+
+func assertRequests(t *testing.T, server *webhooktest.Server, want ...string) {
+	t.Helper()
+	if got := methods(server); strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+}
+
+func TestUpsertSkipsEqualPayloads(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	client := webhook.NewClient(server.URL)
+	ctx := context.Background()
+
+	embed, _ := webhook.CreateEmbed("Build", "passing", "", 0)
+	status := webhook.Webhook{Embeds: []webhook.Embed{embed}}
+	if err := client.Upsert(ctx, "build:main", status); err != nil {
+		t.Fatal(err)
+	}
+	// Equal, as empty and nil lists are
+	same := webhook.Webhook{Embeds: []webhook.Embed{embed}, Attachments: []webhook.Attachment{}}
+	if err := client.Upsert(ctx, "build:main", same); err != nil {
+		t.Fatal(err)
+	}
+	assertRequests(t, server, "POST ")
+
+	// Changing the payload in place must not make Upsert think it was sent
+	status.Embeds[0].Description = "failing"
+	if err := client.Upsert(ctx, "build:main", status); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Upsert(ctx, "build:main", status); err != nil {
+		t.Fatal(err)
+	}
+	assertRequests(t, server, "POST ", "PATCH /messages/1")
+}
+
+func TestUpsertRepostsDeletedMessage(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	store := webhook.NewMemoryStore()
+	client := webhook.NewClient(server.URL, webhook.WithMessageStore(store))
+	ctx := context.Background()
+
+	if err := client.Upsert(ctx, "build:main", webhook.Webhook{Content: "passing"}); err != nil {
+		t.Fatal(err)
+	}
+	server.Respond(webhooktest.Error(http.StatusNotFound, webhook.ErrorCodeUnknownMessage, "Unknown Message"))
+	if err := client.Upsert(ctx, "build:main", webhook.Webhook{Content: "failing"}); err != nil {
+		t.Fatal(err)
+	}
+	assertRequests(t, server, "POST ", "PATCH /messages/1", "POST ")
+	if id, _ := store.Load("build:main"); id != "2" {
+		t.Errorf("stored message ID = %q, want the reposted message 2", id)
+	}
+
+	// The reposted message is the one to skip and edit from now on
+	if err := client.Upsert(ctx, "build:main", webhook.Webhook{Content: "failing"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Upsert(ctx, "build:main", webhook.Webhook{Content: "fixed"}); err != nil {
+		t.Fatal(err)
+	}
+	assertRequests(t, server, "POST ", "PATCH /messages/1", "POST ", "PATCH /messages/2")
+}
+
+func TestUpsertSkipsEqualPayloadsAfterRestart(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "messages.json")
+	ctx := context.Background()
+	status := webhook.Webhook{Content: "passing", AllowedMentions: &webhook.AllowedMentions{Users: []string{"1", "2"}}}
+
+	if err := webhook.NewClient(server.URL, webhook.WithMessageStore(webhook.NewFileStore(path))).Upsert(ctx, "build:main", status); err != nil {
+		t.Fatal(err)
+	}
+	restarted := webhook.NewClient(server.URL, webhook.WithMessageStore(webhook.NewFileStore(path)))
+	status.AllowedMentions = &webhook.AllowedMentions{Users: []string{"2", "1"}}
+	if err := restarted.Upsert(ctx, "build:main", status); err != nil {
+		t.Fatal(err)
+	}
+	assertRequests(t, server, "POST ")
+
+	if err := restarted.Upsert(ctx, "build:main", webhook.Webhook{Content: "failing"}); err != nil {
+		t.Fatal(err)
+	}
+	assertRequests(t, server, "POST ", "PATCH /messages/1")
+}
+
+func TestFileStoreReadsMessageIDs(t *testing.T) {
+	server := webhooktest.NewServer()
+	defer server.Close()
+	// Stores written before payloads were kept hold bare message IDs
+	path := filepath.Join(t.TempDir(), "messages.json")
+	if err := os.WriteFile(path, []byte(`{"build:main": "7"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store := webhook.NewFileStore(path)
+	if id, err := store.Load("build:main"); err != nil || id != "7" {
+		t.Fatalf("Load = %q, %v; want 7", id, err)
+	}
+
+	client := webhook.NewClient(server.URL, webhook.WithMessageStore(store))
+	if err := client.Upsert(context.Background(), "build:main", webhook.Webhook{Content: "passing"}); err != nil {
+		t.Fatal(err)
+	}
+	assertRequests(t, server, "PATCH /messages/7")
+}
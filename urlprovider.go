@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// WebhookURLProvider resolves the webhook URL of a client, such as from a secret
+// manager, so tokens can rotate without restarting the service
+type WebhookURLProvider interface {
+	WebhookURL(ctx context.Context) (string, error)
+}
+
+// URLProviderFunc adapts a function, such as one reading a Vault or AWS Secrets
+// Manager secret, to a WebhookURLProvider
+type URLProviderFunc func(ctx context.Context) (string, error)
+
+// WebhookURL calls the function
+func (f URLProviderFunc) WebhookURL(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// EnvURL reads the webhook URL from an environment variable
+func EnvURL(name string) WebhookURLProvider {
+	return URLProviderFunc(func(ctx context.Context) (string, error) {
+		webhookURL := strings.TrimSpace(os.Getenv(name))
+		if webhookURL == "" {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return webhookURL, nil
+	})
+}
+
+// FileURL reads the webhook URL from a file, such as a mounted Kubernetes secret,
+// ignoring surrounding whitespace
+func FileURL(path string) WebhookURLProvider {
+	return URLProviderFunc(func(ctx context.Context) (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read webhook URL: %v", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	})
+}
+
+// NewClientFromProvider creates a client whose webhook URL is resolved through the
+// provider on the first request. When Discord reports the webhook revoked or
+// deleted, the URL is resolved again and the request repeated once if it changed.
+func NewClientFromProvider(provider WebhookURLProvider, options ...ClientOption) *Client {
+	c := NewClient("", options...)
+	c.provider = provider
+	return c
+}
+
+// baseURL returns the webhook URL, resolving it through the provider when it is not known yet
+func (c *Client) baseURL(ctx context.Context) (string, error) {
+	c.urlMu.Lock()
+	defer c.urlMu.Unlock()
+	if c.webhookURL != "" || c.provider == nil {
+		return c.webhookURL, nil
+	}
+
+	webhookURL, err := c.provider.WebhookURL(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve webhook URL: %v", err)
+	}
+	if webhookURL == "" {
+		return "", fmt.Errorf("failed to resolve webhook URL: the provider returned an empty URL")
+	}
+	c.webhookURL = webhookURL
+	return webhookURL, nil
+}
+
+// withBaseURL runs a request against the webhook URL. When the request shows the
+// webhook is invalid, a provided URL is resolved again and the request repeated
+// once with the new URL; if it still fails, the webhook is marked invalid.
+func (c *Client) withBaseURL(ctx context.Context, request func(webhookURL string) error) error {
+	webhookURL, err := c.baseURL(ctx)
+	if err != nil {
+		return err
+	}
+	err = request(webhookURL)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !apiErr.webhookInvalid {
+		return err
+	}
+
+	if c.provider != nil {
+		c.urlMu.Lock()
+		if c.webhookURL == webhookURL {
+			c.webhookURL = ""
+		}
+		c.urlMu.Unlock()
+		if fresh, resolveErr := c.baseURL(ctx); resolveErr == nil && fresh != webhookURL {
+			webhookURL = fresh
+			err = request(fresh)
+			if !errors.As(err, &apiErr) || !apiErr.webhookInvalid {
+				return err
+			}
+		}
+	}
+	c.markInvalid(webhookURL, err)
+	return err
+}
+
+// threadProvider resolves the URL of a thread of a provided webhook
+type threadProvider struct {
+	parent   WebhookURLProvider
+	threadID string
+}
+
+// WebhookURL resolves the parent webhook and adds the thread
+func (p threadProvider) WebhookURL(ctx context.Context) (string, error) {
+	webhookURL, err := p.parent.WebhookURL(ctx)
+	if err != nil {
+		return "", err
+	}
+	return withQueryParam(webhookURL, "thread_id", p.threadID)
+}
+
+// parseThreadID returns the thread_id query parameter of a webhook URL
+func parseThreadID(webhookURL string) string {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("thread_id")
+}
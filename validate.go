@@ -0,0 +1,310 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// Discord limits for webhook messages
+const (
+	maxContentLength          = 2000
+	maxUsernameLength         = 80
+	maxEmbeds                 = 10
+	maxEmbedTitleLength       = 256
+	maxEmbedDescriptionLength = 4096
+	maxEmbedFields            = 25
+	maxFieldNameLength        = 256
+	maxFieldValueLength       = 1024
+	maxFooterTextLength       = 2048
+	maxAuthorNameLength       = 256
+	maxEmbedTotalLength       = 6000
+	maxThreadNameLength       = 100
+	maxAppliedTags            = 5
+)
+
+// Violation describes a single Discord limit broken by a payload
+type Violation struct {
+	// Path locates the offending value, e.g. "embeds[0].fields[3].value"
+	Path    string
+	Message string
+}
+
+// String returns the violation as "path: message"
+func (v Violation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// ValidateWebhookFor validates the payload like ValidateWebhook, and also checks that
+// the webhook described by info can send it: interactive components, such as buttons
+// with a custom ID and select menus, require a webhook owned by an application, while
+// webhooks created in a channel's settings can only send link buttons.
+func ValidateWebhookFor(webhookPayload Webhook, info WebhookInfo) error {
+	err := ValidateWebhook(webhookPayload)
+	if info.ApplicationID != "" || !webhookPayload.HasInteractiveComponents() {
+		return err
+	}
+	violation := Violation{Path: "components", Message: errInteractiveComponents}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		validationErr.Violations = append(validationErr.Violations, violation)
+		return validationErr
+	}
+	return &ValidationError{Violations: []Violation{violation}}
+}
+
+// errInteractiveComponents explains why Discord drops or rejects interactive components
+const errInteractiveComponents = "interactive components require a webhook owned by an application; " +
+	"webhooks created in a channel's settings can only send link buttons"
+
+// ValidationError lists every Discord limit a payload violates
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error joins all violations into a single message
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+	return "invalid webhook payload: " + strings.Join(parts, "; ")
+}
+
+// Is makes validation errors match ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// validator collects violations while walking a payload
+type validator struct {
+	violations []Violation
+}
+
+// addf records a violation at the path
+func (v *validator) addf(path, format string, args ...any) {
+	v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// maxLength records a violation when the value is longer than limit characters
+func (v *validator) maxLength(path, value string, limit int) {
+	if n := utf8.RuneCountInString(value); n > limit {
+		v.addf(path, "length %d exceeds the limit of %d characters", n, limit)
+	}
+}
+
+// url records a violation when the value is set but is not an absolute URL with an allowed scheme
+func (v *validator) url(path, value string, schemes ...string) {
+	if value == "" {
+		return
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" {
+		v.addf(path, "%q is not an absolute URL", value)
+		return
+	}
+	for _, scheme := range schemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return
+		}
+	}
+	v.addf(path, "URL scheme %q is not allowed (use %s)", u.Scheme, strings.Join(schemes, ", "))
+}
+
+// ValidateWebhook checks the payload against Discord's limits without sending it.
+// It reports every violation at once as a *ValidationError, or returns nil when the payload is valid.
+func ValidateWebhook(webhookPayload Webhook) error {
+	v := &validator{}
+
+	if webhookPayload.Content == "" && len(webhookPayload.Embeds) == 0 && len(webhookPayload.Components) == 0 && webhookPayload.Poll == nil {
+		v.addf("", "cannot send an empty message: set content, embeds, components or a poll")
+	}
+	v.maxLength("content", webhookPayload.Content, maxContentLength)
+
+	v.maxLength("username", webhookPayload.Username, maxUsernameLength)
+	lowerUsername := strings.ToLower(webhookPayload.Username)
+	for _, reserved := range []string{"discord", "clyde"} {
+		if strings.Contains(lowerUsername, reserved) {
+			v.addf("username", "cannot contain %q", reserved)
+		}
+	}
+	v.url("avatar_url", webhookPayload.AvatarURL, "http", "https")
+	v.maxLength("thread_name", webhookPayload.ThreadName, maxThreadNameLength)
+	if n := len(webhookPayload.AppliedTags); n > maxAppliedTags {
+		v.addf("applied_tags", "%d tags exceed the limit of %d", n, maxAppliedTags)
+	}
+
+	if n := len(webhookPayload.Embeds); n > maxEmbeds {
+		v.addf("embeds", "%d embeds exceed the limit of %d", n, maxEmbeds)
+	}
+	total := 0
+	for i, embed := range webhookPayload.Embeds {
+		total += v.embed(fmt.Sprintf("embeds[%d]", i), embed)
+	}
+	if total > maxEmbedTotalLength {
+		v.addf("embeds", "combined length %d exceeds the limit of %d characters", total, maxEmbedTotalLength)
+	}
+
+	v.components(webhookPayload.Components)
+	if webhookPayload.Poll != nil {
+		v.poll(webhookPayload.Poll)
+	}
+	if mentions := webhookPayload.AllowedMentions; mentions != nil {
+		for _, parse := range mentions.Parse {
+			switch {
+			case parse == MentionRoles && len(mentions.Roles) > 0:
+				v.addf("allowed_mentions.roles", "cannot be combined with parsing roles")
+			case parse == MentionUsers && len(mentions.Users) > 0:
+				v.addf("allowed_mentions.users", "cannot be combined with parsing users")
+			}
+		}
+	}
+
+	if len(v.violations) > 0 {
+		return &ValidationError{Violations: v.violations}
+	}
+	return nil
+}
+
+// embed validates a single embed and returns the number of characters counting towards the combined limit
+func (v *validator) embed(path string, embed Embed) int {
+	v.maxLength(path+".title", embed.Title, maxEmbedTitleLength)
+	v.maxLength(path+".description", embed.Description, maxEmbedDescriptionLength)
+	v.url(path+".url", embed.URL, "http", "https")
+
+	if embed.Color < minDecimalRGBValue || embed.Color > maxDecimalRGBValue {
+		v.addf(path+".color", "%d is outside the range 0 to 16777215", embed.Color)
+	}
+	if embed.Timestamp != "" && !isValidISO8601(embed.Timestamp) {
+		v.addf(path+".timestamp", "%q is not an ISO8601 timestamp", embed.Timestamp)
+	}
+
+	v.maxLength(path+".footer.text", embed.Footer.Text, maxFooterTextLength)
+	v.url(path+".footer.icon_url", embed.Footer.IconURL, "http", "https", "attachment")
+	v.maxLength(path+".author.name", embed.Author.Name, maxAuthorNameLength)
+	v.url(path+".author.url", embed.Author.URL, "http", "https")
+	v.url(path+".author.icon_url", embed.Author.IconURL, "http", "https", "attachment")
+	v.url(path+".image.url", embed.Image.URL, "http", "https", "attachment")
+	v.url(path+".thumbnail.url", embed.Thumbnail.URL, "http", "https", "attachment")
+
+	if n := len(embed.Fields); n > maxEmbedFields {
+		v.addf(path+".fields", "%d fields exceed the limit of %d", n, maxEmbedFields)
+	}
+	total := utf8.RuneCountInString(embed.Title) + utf8.RuneCountInString(embed.Description) +
+		utf8.RuneCountInString(embed.Footer.Text) + utf8.RuneCountInString(embed.Author.Name)
+	for i, field := range embed.Fields {
+		fieldPath := fmt.Sprintf("%s.fields[%d]", path, i)
+		if strings.TrimSpace(field.Name) == "" {
+			v.addf(fieldPath+".name", "cannot be empty")
+		}
+		if strings.TrimSpace(field.Value) == "" {
+			v.addf(fieldPath+".value", "cannot be empty")
+		}
+		v.maxLength(fieldPath+".name", field.Name, maxFieldNameLength)
+		v.maxLength(fieldPath+".value", field.Value, maxFieldValueLength)
+		total += utf8.RuneCountInString(field.Name) + utf8.RuneCountInString(field.Value)
+	}
+	return total
+}
+
+// components validates the action rows of a message
+func (v *validator) components(components []Component) {
+	if n := len(components); n > maxActionRows {
+		v.addf("components", "%d action rows exceed the limit of %d", n, maxActionRows)
+	}
+	customIDs := make(map[string]bool)
+	for i, row := range components {
+		rowPath := fmt.Sprintf("components[%d]", i)
+		if row.Type != ComponentTypeActionRow {
+			v.addf(rowPath+".type", "top-level components must be action rows")
+			continue
+		}
+		if n := len(row.Components); n == 0 || n > maxButtonsPerRow {
+			v.addf(rowPath+".components", "action rows must hold 1 to %d components (got %d)", maxButtonsPerRow, n)
+		}
+		for j, component := range row.Components {
+			componentPath := fmt.Sprintf("%s.components[%d]", rowPath, j)
+			switch {
+			case isSelectMenu(component.Type):
+				if len(row.Components) > 1 {
+					v.addf(componentPath, "a select menu must be the only component of its action row")
+				}
+				v.selectMenu(componentPath, component)
+			case component.Type == ComponentTypeButton:
+				v.button(componentPath, component)
+			default:
+				v.addf(componentPath+".type", "action rows can only hold buttons and select menus")
+				continue
+			}
+			if component.CustomID != "" {
+				if customIDs[component.CustomID] {
+					v.addf(componentPath+".custom_id", "custom ID %q is used by another component", component.CustomID)
+				}
+				customIDs[component.CustomID] = true
+			}
+		}
+	}
+}
+
+// button validates a button of an action row
+func (v *validator) button(path string, button Component) {
+	v.maxLength(path+".label", button.Label, maxButtonLabelRunes)
+	if button.Style == ButtonStyleLink {
+		if button.URL == "" {
+			v.addf(path+".url", "link buttons require a URL")
+		}
+		v.url(path+".url", button.URL, "http", "https", "discord")
+		if button.CustomID != "" {
+			v.addf(path+".custom_id", "link buttons cannot have a custom ID")
+		}
+		return
+	}
+	if button.URL != "" {
+		v.addf(path+".url", "only link buttons can have a URL")
+	}
+	if button.CustomID == "" {
+		v.addf(path+".custom_id", "interactive buttons require a custom ID")
+	}
+	v.maxLength(path+".custom_id", button.CustomID, maxCustomIDRunes)
+}
+
+// selectMenu validates a select menu of an action row
+func (v *validator) selectMenu(path string, menu Component) {
+	if menu.CustomID == "" {
+		v.addf(path+".custom_id", "select menus require a custom ID")
+	}
+	v.maxLength(path+".custom_id", menu.CustomID, maxCustomIDRunes)
+	v.maxLength(path+".placeholder", menu.Placeholder, maxPlaceholderRunes)
+
+	if menu.Type == ComponentTypeStringSelect {
+		if n := len(menu.Options); n == 0 || n > maxSelectOptions {
+			v.addf(path+".options", "string select menus must offer 1 to %d options (got %d)", maxSelectOptions, n)
+		}
+		for k, option := range menu.Options {
+			optionPath := fmt.Sprintf("%s.options[%d]", path, k)
+			if option.Label == "" || option.Value == "" {
+				v.addf(optionPath, "options require a label and a value")
+			}
+			v.maxLength(optionPath+".label", option.Label, maxSelectOptionRunes)
+			v.maxLength(optionPath+".value", option.Value, maxSelectOptionRunes)
+			v.maxLength(optionPath+".description", option.Description, maxSelectOptionRunes)
+		}
+	} else if len(menu.Options) > 0 {
+		v.addf(path+".options", "only string select menus have options")
+	}
+
+	if menu.MinValues < 0 || menu.MinValues > maxSelectValuesLimit || menu.MaxValues < 0 || menu.MaxValues > maxSelectValuesLimit {
+		v.addf(path, "min_values and max_values must be between 0 and %d", maxSelectValuesLimit)
+	}
+	if menu.MaxValues > 0 && menu.MinValues > menu.MaxValues {
+		v.addf(path+".min_values", "cannot exceed max_values")
+	}
+	if menu.Type == ComponentTypeStringSelect && menu.MaxValues > len(menu.Options) {
+		v.addf(path+".max_values", "cannot exceed the number of options")
+	}
+}
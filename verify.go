@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// WithStrictVerification makes the client wait for Discord to return every message it
+// posts and compare it with the payload, catching content, embeds and attachments that
+// were trimmed or dropped silently; see VerifyMessage. Discrepancies are passed to report.
+// Without a report function, sends with discrepancies fail with a *VerificationError,
+// even though the message was posted.
+func WithStrictVerification(report func(webhookPayload Webhook, message Message, discrepancies []Violation)) ClientOption {
+	return func(c *Client) {
+		c.verify = true
+		c.onDiscrepancy = report
+	}
+}
+
+// VerificationError is returned when the message Discord created differs from the payload
+// and no report function was given to WithStrictVerification
+type VerificationError struct {
+	// Message is the message as Discord created it
+	Message       Message
+	Discrepancies []Violation
+}
+
+// Error lists the discrepancies
+func (e *VerificationError) Error() string {
+	parts := make([]string, len(e.Discrepancies))
+	for i, d := range e.Discrepancies {
+		parts[i] = d.String()
+	}
+	return "message differs from the payload: " + strings.Join(parts, "; ")
+}
+
+// VerifyMessage compares the message Discord returned with the payload it was created
+// from and lists what got lost: truncated or changed content, embed titles, descriptions,
+// footers, authors and fields, and dropped embeds and attachments. Surrounding whitespace,
+// which Discord trims, is ignored, as are embeds Discord added, such as link previews.
+func VerifyMessage(webhookPayload Webhook, message Message) []Violation {
+	var discrepancies []Violation
+	compare := func(path, sent, got string) {
+		if d := compareText(sent, got); d != "" {
+			discrepancies = append(discrepancies, Violation{Path: path, Message: d})
+		}
+	}
+
+	compare("content", webhookPayload.Content, message.Content)
+	for i, embed := range webhookPayload.Embeds {
+		path := fmt.Sprintf("embeds[%d]", i)
+		if i >= len(message.Embeds) {
+			discrepancies = append(discrepancies, Violation{Path: path, Message: "dropped"})
+			continue
+		}
+		got := message.Embeds[i]
+		compare(path+".title", embed.Title, got.Title)
+		compare(path+".description", embed.Description, got.Description)
+		compare(path+".footer.text", embed.Footer.Text, got.Footer.Text)
+		compare(path+".author.name", embed.Author.Name, got.Author.Name)
+		for j, field := range embed.Fields {
+			fieldPath := fmt.Sprintf("%s.fields[%d]", path, j)
+			if j >= len(got.Fields) {
+				discrepancies = append(discrepancies, Violation{Path: fieldPath, Message: "dropped"})
+				continue
+			}
+			compare(fieldPath+".name", field.Name, got.Fields[j].Name)
+			compare(fieldPath+".value", field.Value, got.Fields[j].Value)
+		}
+	}
+	if sent, got := len(webhookPayload.Attachments), len(message.Attachments); got < sent {
+		discrepancies = append(discrepancies, Violation{
+			Path:    "attachments",
+			Message: fmt.Sprintf("%s of %d dropped", pluralize(sent-got, "attachment"), sent),
+		})
+	}
+	return discrepancies
+}
+
+// compareText describes how the text Discord returned differs from the text sent,
+// or returns an empty string when it does not
+func compareText(sent, got string) string {
+	sent, got = strings.TrimSpace(sent), strings.TrimSpace(got)
+	switch {
+	case sent == got:
+		return ""
+	case got == "":
+		return "dropped"
+	case strings.HasPrefix(sent, got):
+		return fmt.Sprintf("truncated from %d to %d characters", utf8.RuneCountInString(sent), utf8.RuneCountInString(got))
+	default:
+		return "changed"
+	}
+}
+
+// verifyMessage compares a posted message with its payload when strict verification is on
+func (c *Client) verifyMessage(webhookPayload Webhook, message Message) error {
+	if !c.verify {
+		return nil
+	}
+	discrepancies := VerifyMessage(webhookPayload, message)
+	if len(discrepancies) == 0 {
+		return nil
+	}
+	if c.onDiscrepancy != nil {
+		c.onDiscrepancy(webhookPayload, message, discrepancies)
+		return nil
+	}
+	return &VerificationError{Message: message, Discrepancies: discrepancies}
+}
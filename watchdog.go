@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	watchdogAlertColor     = 0xED4245
+	watchdogRecoveredColor = 0x57F287
+)
+
+// Watchdog is the inverse of a heartbeat: application code calls Ping while it is
+// healthy, and the watchdog posts an alert when no ping arrives within the window,
+// such as when a job loop hangs. Once pings resume, it posts a recovery message.
+type Watchdog struct {
+	client *Client
+	name   string
+	window time.Duration
+
+	// RoleID, when set, mentions the role in alerts so that someone gets notified
+	RoleID string
+
+	// OnError is called when an alert could not be delivered
+	OnError func(err error)
+
+	mu       sync.Mutex
+	lastPing time.Time
+	pinged   chan struct{}
+}
+
+// NewWatchdog creates a watchdog for the named component, alerting after window without a ping
+func NewWatchdog(client *Client, name string, window time.Duration) *Watchdog {
+	return &Watchdog{
+		client:   client,
+		name:     name,
+		window:   window,
+		lastPing: time.Now(),
+		pinged:   make(chan struct{}, 1),
+	}
+}
+
+// Ping tells the watchdog the component is alive. It never blocks.
+func (w *Watchdog) Ping() {
+	w.mu.Lock()
+	w.lastPing = time.Now()
+	w.mu.Unlock()
+
+	select {
+	case w.pinged <- struct{}{}:
+	default:
+	}
+}
+
+// LastPing returns the time of the last ping, or the creation time before the first one
+func (w *Watchdog) LastPing() time.Time {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.lastPing
+}
+
+// Run watches for missing pings until ctx is cancelled. The window starts when the
+// watchdog is created, so a component that never pings is reported as well.
+func (w *Watchdog) Run(ctx context.Context) error {
+	alerted := false
+	timer := time.NewTimer(w.window)
+	defer timer.Stop()
+
+	for {
+		lastPing := w.LastPing()
+		if !alerted {
+			if wait := time.Until(lastPing.Add(w.window)); wait > 0 {
+				resetTimer(timer, wait)
+			} else {
+				w.send(ctx, w.alert(lastPing))
+				alerted = true
+			}
+		}
+
+		// While alerting, only a ping can change anything
+		var expired <-chan time.Time
+		if !alerted {
+			expired = timer.C
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.pinged:
+			if alerted {
+				w.send(ctx, w.recovered(lastPing))
+				alerted = false
+			}
+		case <-expired:
+		}
+	}
+}
+
+// alert builds the message posted when pings stop
+func (w *Watchdog) alert(lastPing time.Time) Webhook {
+	payload := Webhook{Embeds: []Embed{{
+		Title:       w.name + " stopped responding",
+		Description: fmt.Sprintf("No ping received for %s. Last ping <t:%d:R>.", formatDuration(w.window), lastPing.Unix()),
+		Color:       watchdogAlertColor,
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+	}}}
+	if w.RoleID != "" {
+		payload.Content = "<@&" + w.RoleID + ">"
+	}
+	return payload
+}
+
+// recovered builds the message posted when pings resume
+func (w *Watchdog) recovered(lastPing time.Time) Webhook {
+	return Webhook{Embeds: []Embed{{
+		Title:       w.name + " recovered",
+		Description: fmt.Sprintf("Pings resumed after %s of silence.", formatDuration(time.Since(lastPing))),
+		Color:       watchdogRecoveredColor,
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+	}}}
+}
+
+// send delivers a watchdog message, reporting failures to the error handler
+func (w *Watchdog) send(ctx context.Context, payload Webhook) {
+	if err := w.client.Send(ctx, payload); err != nil && w.OnError != nil {
+		w.OnError(err)
+	}
+}
+
+// resetTimer stops the timer, drains it if it already fired, and restarts it
+func resetTimer(timer *time.Timer, d time.Duration) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+	timer.Reset(d)
+}
@@ -7,10 +7,9 @@
 package webhook
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"strconv"
 	"time"
 )
@@ -20,15 +19,39 @@ const (
 	maxDecimalRGBValue = 16777215
 	minRGBValue        = 0
 	maxRGBValue        = 255
+
+	// zeroWidthSpace is used as a placeholder for field names and values that Discord requires to be non-empty
+	zeroWidthSpace = "\u200b"
 )
 
 // Webhook represents the structure for sending a message via Discord webhooks.
 // It can include optional content, username, avatar URL, and an array of rich embed objects.
 type Webhook struct {
-	Content   string  `json:"content,omitempty"`
-	Username  string  `json:"username,omitempty"`
-	AvatarURL string  `json:"avatar_url,omitempty"`
-	Embeds    []Embed `json:"embeds,omitempty"`
+	Content         string           `json:"content,omitempty"`
+	Username        string           `json:"username,omitempty"`
+	AvatarURL       string           `json:"avatar_url,omitempty"`
+	Embeds          []Embed          `json:"embeds,omitempty"`
+	Components      []Component      `json:"components,omitempty"`
+	AllowedMentions *AllowedMentions `json:"allowed_mentions,omitempty"`
+	Flags           MessageFlags     `json:"flags,omitempty"`
+
+	// Poll asks the channel a question; see CreatePoll
+	Poll *Poll `json:"poll,omitempty"`
+
+	// Attachments describe the files uploaded with the message; see Client.SendFiles
+	Attachments []Attachment `json:"attachments,omitempty"`
+
+	// ThreadName creates a thread with the message as its first post. Only forum and
+	// media channels support it; see Client.StartThread.
+	ThreadName string `json:"thread_name,omitempty"`
+	// AppliedTags are the IDs of the forum tags of the created thread, at most 5; see
+	// manage.Manager.ResolveTags for specifying them by name
+	AppliedTags []string `json:"applied_tags,omitempty"`
+
+	// Extra holds fields Discord supports but Webhook does not model yet, such as
+	// {"enforce_nonce": true}. They are merged into the JSON, replacing modeled fields
+	// of the same name, and unknown fields of decoded JSON end up here.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // Embed represents a rich embed object for Discord
@@ -43,6 +66,9 @@ type Embed struct {
 	Thumbnail   Thumbnail `json:"thumbnail,omitempty"`
 	Author      Author    `json:"author,omitempty"`
 	Fields      []Field   `json:"fields,omitempty"`
+
+	// Extra holds embed fields Webhook does not model yet; see Webhook.Extra
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // Footer represents the footer section of an embed
@@ -144,8 +170,8 @@ func (e *Embed) SetTimestamp() {
 
 // CreateWebhook creates a new Webhook with the specified content, username, and avatar URL
 func CreateWebhook(content, username, avatarURL string) (Webhook, error) {
-	if len(content) > 2000 {
-		return Webhook{}, fmt.Errorf("the length of the content cannot exceed 2000 characters (your length: %d)", len(content))
+	if len(content) > maxContentLength {
+		return Webhook{}, fmt.Errorf("the length of the content cannot exceed %d characters (your length: %d)", maxContentLength, len(content))
 	}
 	return Webhook{
 		Content:   content,
@@ -273,20 +299,5 @@ func isValidISO8601(timestamp string) bool {
 
 // SendWebhook sends the webhook payload to the specified Discord Webhook URL
 func SendWebhook(webhookUrl string, webhookPayload Webhook) error {
-
-	jsonData, err := json.Marshal(webhookPayload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON payload: %v", err)
-	}
-
-	resp, err := http.Post(webhookUrl, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to post to Discord: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("discord webhook returned status %d", resp.StatusCode)
-	}
-
-	return nil
+	return NewClient(webhookUrl).Send(context.Background(), webhookPayload)
 }
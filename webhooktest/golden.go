@@ -0,0 +1,141 @@
+// Package webhooktest provides helpers for testing code that sends Discord webhooks:
+// golden-file snapshots of payloads, so notification formatting changes show up as
+// reviewable diffs, and a mock webhook server, so integration tests never hit Discord.
+package webhooktest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+// UpdateEnvVar is the environment variable that makes AssertGolden rewrite golden
+// files instead of comparing against them, e.g. WEBHOOKTEST_UPDATE=1 go test ./...
+const UpdateEnvVar = "WEBHOOKTEST_UPDATE"
+
+// GoldenDir is the directory holding golden files, relative to the package under test
+var GoldenDir = filepath.Join("testdata", "golden")
+
+// Marshal encodes the payload deterministically: indented, with fields in declaration
+// order, no HTML escaping and a trailing newline
+func Marshal(payload webhook.Webhook) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := payload.WriteJSON(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// AssertGolden compares the payload against the golden file GoldenDir/name.json and
+// fails the test with a line diff when they differ. When the WEBHOOKTEST_UPDATE
+// environment variable is set, the golden file is written instead.
+func AssertGolden(t testing.TB, name string, payload webhook.Webhook) {
+	t.Helper()
+
+	got, err := Marshal(payload)
+	if err != nil {
+		t.Fatalf("webhooktest: %v", err)
+	}
+	path := filepath.Join(GoldenDir, name+".json")
+
+	if os.Getenv(UpdateEnvVar) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("webhooktest: failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("webhooktest: failed to write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("webhooktest: failed to read golden file (run with %s=1 to create it): %v", UpdateEnvVar, err)
+	}
+	if diff := Diff(string(want), string(got)); diff != "" {
+		t.Errorf("webhooktest: payload does not match %s (-want +got):\n%s", path, diff)
+	}
+}
+
+// Diff returns a line diff between want and got, prefixing removed lines with "-",
+// added lines with "+" and unchanged context lines with a space. It returns an
+// empty string when both are equal. Unchanged runs longer than a few lines are elided.
+func Diff(want, got string) string {
+	if want == got {
+		return ""
+	}
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			j++
+		}
+	}
+
+	// Keep up to three lines of context around changes
+	const context = 3
+	near := make([]bool, len(lines))
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for n := k - context; n <= k+context; n++ {
+			if n >= 0 && n < len(lines) {
+				near[n] = true
+			}
+		}
+	}
+
+	var out strings.Builder
+	elided := false
+	for k, l := range lines {
+		if !near[k] {
+			if !elided {
+				out.WriteString("  ...\n")
+				elided = true
+			}
+			continue
+		}
+		elided = false
+		fmt.Fprintf(&out, "%c %s\n", l.op, l.text)
+	}
+	return out.String()
+}
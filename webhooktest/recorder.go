@@ -0,0 +1,273 @@
+package webhooktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+// Interaction is a recorded request and its response, stored as one JSON file per request
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is an outgoing request. The URL is stored with the webhook token redacted.
+type RecordedRequest struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	Header http.Header     `json:"header,omitempty"`
+	JSON   json.RawMessage `json:"json,omitempty"`
+	Body   []byte          `json:"body,omitempty"`
+}
+
+// RecordedResponse is the response Discord sent
+type RecordedResponse struct {
+	Status int             `json:"status"`
+	Header http.Header     `json:"header,omitempty"`
+	JSON   json.RawMessage `json:"json,omitempty"`
+	Body   []byte          `json:"body,omitempty"`
+}
+
+// recordedHeaders are the headers kept in recordings; others, such as authorization, are dropped
+var recordedHeaders = []string{
+	"Content-Type",
+	"Retry-After",
+	"X-RateLimit-Limit",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Reset-After",
+	"X-RateLimit-Bucket",
+	"X-RateLimit-Global",
+	"X-RateLimit-Scope",
+}
+
+// Recorder is an http.RoundTripper that forwards requests and writes every
+// interaction to a directory, numbered in the order the requests were sent.
+// Use it once against Discord or a staging webhook, then replay the recordings
+// in tests with a Replayer:
+//
+//	recorder := webhooktest.NewRecorder("testdata/deploy-flow", nil)
+//	client := webhook.NewClient(url, webhook.WithHTTPClient(&http.Client{Transport: recorder}))
+type Recorder struct {
+	dir  string
+	next http.RoundTripper
+
+	mu sync.Mutex
+	n  int
+}
+
+// NewRecorder creates a recorder writing to dir. Requests are forwarded to next,
+// or to http.DefaultTransport when next is nil.
+func NewRecorder(dir string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{dir: dir, next: next}
+}
+
+// RoundTrip forwards the request and records it with its response
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("webhooktest: failed to read request body: %v", err)
+	}
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("webhooktest: failed to read response body: %v", err)
+	}
+
+	interaction := Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    webhook.RedactWebhookURL(req.URL.String()),
+			Header: filterHeader(req.Header),
+		},
+		Response: RecordedResponse{
+			Status: resp.StatusCode,
+			Header: filterHeader(resp.Header),
+		},
+	}
+	interaction.Request.JSON, interaction.Request.Body = splitBody(requestBody)
+	interaction.Response.JSON, interaction.Response.Body = splitBody(responseBody)
+
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("webhooktest: failed to encode interaction: %v", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := os.MkdirAll(r.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("webhooktest: failed to create recording directory: %v", err)
+	}
+	r.n++
+	path := filepath.Join(r.dir, fmt.Sprintf("%04d.json", r.n))
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("webhooktest: failed to write interaction: %v", err)
+	}
+	return resp, nil
+}
+
+// Replayer is an http.RoundTripper that serves the interactions written by a
+// Recorder, in order, without touching the network. Each request must match
+// the recorded method, URL and body, so changes to the notification flow show
+// up as errors with a diff.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	next         int
+}
+
+// NewReplayer loads the interactions recorded in dir
+func NewReplayer(dir string) (*Replayer, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("webhooktest: %v", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("webhooktest: no recordings in %s", dir)
+	}
+	sort.Strings(paths)
+
+	r := &Replayer{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("webhooktest: failed to read recording: %v", err)
+		}
+		var interaction Interaction
+		if err := json.Unmarshal(data, &interaction); err != nil {
+			return nil, fmt.Errorf("webhooktest: invalid recording %s: %v", path, err)
+		}
+		r.interactions = append(r.interactions, interaction)
+	}
+	return r, nil
+}
+
+// Remaining returns the number of recorded interactions that were not replayed yet
+func (r *Replayer) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.interactions) - r.next
+}
+
+// RoundTrip checks the request against the next recording and returns its response
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("webhooktest: failed to read request body: %v", err)
+	}
+
+	r.mu.Lock()
+	if r.next >= len(r.interactions) {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("webhooktest: unexpected request %s %s: all %d recordings were replayed",
+			req.Method, webhook.RedactWebhookURL(req.URL.String()), len(r.interactions))
+	}
+	interaction := r.interactions[r.next]
+	r.next++
+	r.mu.Unlock()
+
+	recorded := interaction.Request
+	url := webhook.RedactWebhookURL(req.URL.String())
+	if req.Method != recorded.Method || url != recorded.URL {
+		return nil, fmt.Errorf("webhooktest: request %s %s does not match recording %s %s",
+			req.Method, url, recorded.Method, recorded.URL)
+	}
+	gotJSON, gotBody := splitBody(body)
+	if diff := Diff(indentJSON(recorded.JSON), indentJSON(gotJSON)); diff != "" {
+		return nil, fmt.Errorf("webhooktest: request body does not match recording (-want +got):\n%s", diff)
+	}
+	if !bytes.Equal(gotBody, recorded.Body) {
+		return nil, fmt.Errorf("webhooktest: request body does not match recording")
+	}
+
+	response := interaction.Response
+	responseBody := response.Body
+	if len(response.JSON) > 0 {
+		responseBody = response.JSON
+	}
+	header := response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", response.Status, http.StatusText(response.Status)),
+		StatusCode:    response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(responseBody)),
+		ContentLength: int64(len(responseBody)),
+		Request:       req,
+	}, nil
+}
+
+// readBody reads a request or response body and replaces it with an in-memory copy
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// splitBody stores JSON bodies verbatim so recordings stay readable, and other bodies as bytes
+func splitBody(data []byte) (json.RawMessage, []byte) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if json.Valid(data) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, data); err == nil {
+			return buf.Bytes(), nil
+		}
+	}
+	return nil, data
+}
+
+// indentJSON formats JSON for diffing
+func indentJSON(data json.RawMessage) string {
+	if len(data) == 0 {
+		return ""
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return string(data)
+	}
+	return buf.String()
+}
+
+// filterHeader keeps the headers worth recording
+func filterHeader(header http.Header) http.Header {
+	filtered := http.Header{}
+	for _, key := range recordedHeaders {
+		if values := header.Values(key); len(values) > 0 {
+			filtered[key] = values
+		}
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+	return filtered
+}
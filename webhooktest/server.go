@@ -0,0 +1,361 @@
+package webhooktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+)
+
+// Identifiers used in the webhook URL and responses of the mock server
+const (
+	WebhookID    = "100000000000000001"
+	WebhookToken = "test-token"
+	ChannelID    = "100000000000000002"
+	GuildID      = "100000000000000003"
+)
+
+// File is a file attached to a multipart request
+type File struct {
+	// Field is the form field name, such as "files[0]"
+	Field       string
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Request is a request received by the mock server
+type Request struct {
+	Method string
+	// Path is the request path, e.g. /api/webhooks/ID/TOKEN/messages/MESSAGE_ID
+	Path   string
+	Query  url.Values
+	Header http.Header
+	// Body is the raw JSON payload, or the payload_json part of multipart requests
+	Body []byte
+	// Payload is Body decoded as a webhook payload
+	Payload webhook.Webhook
+	Files   []File
+}
+
+// Response is a scripted response of the mock server
+type Response struct {
+	Status int
+	Header http.Header
+	Body   string
+}
+
+// RateLimited returns a 429 response asking the client to retry after the delay,
+// with the headers and body Discord sends
+func RateLimited(retryAfter time.Duration, global bool) Response {
+	seconds := strconv.FormatFloat(retryAfter.Seconds(), 'f', -1, 64)
+	header := http.Header{}
+	header.Set("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+	header.Set("X-RateLimit-Limit", "5")
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset-After", seconds)
+	header.Set("X-RateLimit-Bucket", "webhooktest")
+	if global {
+		header.Set("X-RateLimit-Global", "true")
+	}
+	return Response{
+		Status: http.StatusTooManyRequests,
+		Header: header,
+		Body:   fmt.Sprintf(`{"message": "You are being rate limited.", "retry_after": %s, "global": %t}`, seconds, global),
+	}
+}
+
+// Error returns an error response with Discord's JSON error body, e.g.
+// Error(http.StatusBadRequest, webhook.ErrorCodeInvalidFormBody, "Invalid Form Body")
+func Error(status int, code webhook.ErrorCode, message string) Response {
+	body, _ := json.Marshal(map[string]any{"code": code, "message": message})
+	return Response{Status: status, Body: string(body)}
+}
+
+// Server is a mock Discord webhook endpoint built on httptest. It records every
+// request and answers like Discord does, unless responses have been scripted.
+type Server struct {
+	// URL is the webhook URL to send to
+	URL string
+
+	server    *httptest.Server
+	mu        sync.Mutex
+	requests  []Request
+	responses []Response
+	messages  int
+	received  chan struct{}
+}
+
+// NewServer starts a mock webhook server. Close it when the test is done.
+func NewServer() *Server {
+	s := &Server{received: make(chan struct{}, 1)}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = s.server.URL + "/api/webhooks/" + WebhookID + "/" + WebhookToken
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Client returns an HTTP client configured for the server
+func (s *Server) Client() *http.Client {
+	return s.server.Client()
+}
+
+// Respond queues responses for the next requests, in order. Once the queue is
+// drained the server answers successfully again.
+func (s *Server) Respond(responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses = append(s.responses, responses...)
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Payloads returns the payloads of the requests received so far
+func (s *Server) Payloads() []webhook.Webhook {
+	requests := s.Requests()
+	payloads := make([]webhook.Webhook, len(requests))
+	for i, r := range requests {
+		payloads[i] = r.Payload
+	}
+	return payloads
+}
+
+// Reset forgets the recorded requests and scripted responses
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.responses = nil
+}
+
+// WaitForRequests waits until at least n requests were received, for code that sends asynchronously.
+// It reports whether they arrived before the timeout.
+func (s *Server) WaitForRequests(n int, timeout time.Duration) bool {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		s.mu.Lock()
+		count := len(s.requests)
+		s.mu.Unlock()
+		if count >= n {
+			return true
+		}
+		select {
+		case <-s.received:
+		case <-deadline.C:
+			return false
+		}
+	}
+}
+
+// AssertRequestCount fails the test unless exactly n requests were received
+func (s *Server) AssertRequestCount(t testing.TB, n int) {
+	t.Helper()
+	if got := len(s.Requests()); got != n {
+		t.Errorf("webhooktest: received %d requests, want %d", got, n)
+	}
+}
+
+// AssertLastPayload fails the test with a diff unless the last received payload equals want
+func (s *Server) AssertLastPayload(t testing.TB, want webhook.Webhook) {
+	t.Helper()
+	requests := s.Requests()
+	if len(requests) == 0 {
+		t.Errorf("webhooktest: no requests received")
+		return
+	}
+	AssertPayload(t, requests[len(requests)-1].Payload, want)
+}
+
+// AssertPayload fails the test with a field-by-field diff unless the payloads are equal
+func AssertPayload(t testing.TB, got, want webhook.Webhook) {
+	t.Helper()
+	if diff := webhook.Diff(want, got); diff != "" {
+		t.Errorf("webhooktest: payload mismatch (want -> got):\n%s", diff)
+	}
+}
+
+// handle records the request and writes the next response
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	recorded, err := readRequest(r)
+	if err != nil {
+		writeResponse(w, Error(http.StatusBadRequest, webhook.ErrorCodeInvalidJSON, err.Error()))
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, recorded)
+	var response *Response
+	if len(s.responses) > 0 {
+		response = &s.responses[0]
+		s.responses = s.responses[1:]
+	}
+	if response == nil {
+		s.messages++
+		response = s.defaultResponse(r, recorded)
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.received <- struct{}{}:
+	default:
+	}
+	writeResponse(w, *response)
+}
+
+// defaultResponse answers like Discord does for a successful request
+func (s *Server) defaultResponse(r *http.Request, recorded Request) *Response {
+	prefix := "/api/webhooks/" + WebhookID + "/" + WebhookToken
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		response := Error(http.StatusNotFound, webhook.ErrorCodeUnknownWebhook, "Unknown Webhook")
+		return &response
+	}
+	rest := strings.TrimPrefix(r.URL.Path, prefix)
+
+	switch {
+	case r.Method == http.MethodGet && rest == "":
+		body, _ := json.Marshal(webhook.WebhookInfo{
+			ID: WebhookID, Type: 1, GuildID: GuildID, ChannelID: ChannelID, Name: "webhooktest",
+		})
+		return successResponse(http.StatusOK, string(body))
+	case r.Method == http.MethodDelete:
+		return successResponse(http.StatusNoContent, "")
+	case r.Method == http.MethodPost && r.URL.Query().Get("wait") != "true":
+		return successResponse(http.StatusNoContent, "")
+	}
+
+	messageID := strconv.Itoa(s.messages)
+	if strings.HasPrefix(rest, "/messages/") {
+		messageID = strings.TrimPrefix(rest, "/messages/")
+	}
+	channelID := ChannelID
+	if threadID := r.URL.Query().Get("thread_id"); threadID != "" {
+		channelID = threadID
+	} else if recorded.Payload.ThreadName != "" && r.Method == http.MethodPost {
+		// Like in forum channels, the new thread has the ID of its first message
+		channelID = messageID
+	}
+	attachments := make([]webhook.Attachment, len(recorded.Files))
+	for i, file := range recorded.Files {
+		id := strconv.Itoa(s.messages*100 + i)
+		attachments[i] = webhook.Attachment{
+			ID:          id,
+			Filename:    file.Filename,
+			ContentType: file.ContentType,
+			Size:        len(file.Data),
+			URL:         "https://cdn.discordapp.com/attachments/" + channelID + "/" + id + "/" + url.PathEscape(file.Filename),
+		}
+	}
+	embeds := recorded.Payload.Embeds
+	if embeds == nil {
+		embeds = []webhook.Embed{}
+	}
+	body, _ := json.Marshal(map[string]any{
+		"id":          messageID,
+		"type":        0,
+		"channel_id":  channelID,
+		"webhook_id":  WebhookID,
+		"content":     recorded.Payload.Content,
+		"embeds":      embeds,
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		"attachments": attachments,
+	})
+	return successResponse(http.StatusOK, string(body))
+}
+
+// successResponse returns a response carrying the rate limit headers of a fresh bucket
+func successResponse(status int, body string) *Response {
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit", "5")
+	header.Set("X-RateLimit-Remaining", "4")
+	header.Set("X-RateLimit-Reset-After", "2")
+	header.Set("X-RateLimit-Bucket", "webhooktest")
+	return &Response{Status: status, Header: header, Body: body}
+}
+
+// writeResponse writes a scripted or default response
+func writeResponse(w http.ResponseWriter, response Response) {
+	for key, values := range response.Header {
+		w.Header()[key] = values
+	}
+	if response.Body != "" && w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	status := response.Status
+	if status == 0 {
+		status = http.StatusNoContent
+	}
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, response.Body)
+}
+
+// readRequest records a request, splitting multipart bodies into the payload and files
+func readRequest(r *http.Request) (Request, error) {
+	recorded := Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+	}
+
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		reader := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return Request{}, fmt.Errorf("invalid multipart body: %v", err)
+			}
+			data, err := io.ReadAll(part)
+			if err != nil {
+				return Request{}, fmt.Errorf("invalid multipart body: %v", err)
+			}
+			if part.FormName() == "payload_json" {
+				recorded.Body = data
+				continue
+			}
+			recorded.Files = append(recorded.Files, File{
+				Field:       part.FormName(),
+				Filename:    part.FileName(),
+				ContentType: part.Header.Get("Content-Type"),
+				Data:        data,
+			})
+		}
+	} else {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			return Request{}, fmt.Errorf("failed to read body: %v", err)
+		}
+		recorded.Body = data
+	}
+
+	if len(recorded.Body) > 0 {
+		if err := json.Unmarshal(recorded.Body, &recorded.Payload); err != nil {
+			return Request{}, fmt.Errorf("invalid JSON payload: %v", err)
+		}
+	}
+	return recorded, nil
+}
@@ -0,0 +1,10 @@
+module github.com/dozerokz/discord-webhook-go/yamlwebhook
+
+go 1.18
+
+require github.com/dozerokz/discord-webhook-go v0.0.0
+
+require gopkg.in/yaml.v3 v3.0.1
+
+// Builds against the module in this repository
+replace github.com/dozerokz/discord-webhook-go => ../
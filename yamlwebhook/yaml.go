@@ -0,0 +1,59 @@
+// Package yamlwebhook adds YAML support to the webhook module, which itself depends
+// on the standard library only. Importing it registers the .yaml and .yml extensions
+// with webhook.RegisterFileFormat, so templates.ParseFS loads YAML payload definitions
+// and webhook.LoadProfiles loads YAML profiles:
+//
+//	import _ "github.com/dozerokz/discord-webhook-go/yamlwebhook"
+//
+// It is a separate module, so programs not using YAML do not depend on a YAML parser.
+package yamlwebhook
+
+import (
+	"encoding/json"
+	"fmt"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	webhook.RegisterFileFormat(".yaml", ToJSON)
+	webhook.RegisterFileFormat(".yml", ToJSON)
+}
+
+// ToJSON converts a YAML document into JSON. Anchors and aliases are resolved;
+// mapping keys are converted into strings, as JSON objects need them.
+func ToJSON(data []byte) ([]byte, error) {
+	var document any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %v", err)
+	}
+	converted, err := json.Marshal(jsonValue(document))
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert YAML to JSON: %v", err)
+	}
+	return converted, nil
+}
+
+// jsonValue replaces the maps with non-string keys the YAML decoder returns with maps JSON can encode
+func jsonValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, element := range v {
+			v[key] = jsonValue(element)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, element := range v {
+			m[fmt.Sprint(key)] = jsonValue(element)
+		}
+		return m
+	case []any:
+		for i, element := range v {
+			v[i] = jsonValue(element)
+		}
+		return v
+	}
+	return value
+}
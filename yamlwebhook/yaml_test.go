@@ -0,0 +1,88 @@
+package yamlwebhook
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	webhook "github.com/dozerokz/discord-webhook-go"
+	"github.com/dozerokz/discord-webhook-go/templates"
+)
+
+func TestToJSON(t *testing.T) {
+	got, err := ToJSON([]byte("content: hello\nembeds:\n  - title: Deploy\n    color: 5814783\n    fields:\n      - {name: Version, value: 1.2.3, inline: true}\n1: one\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"1":"one","content":"hello","embeds":[{"color":5814783,"fields":[{"inline":true,"name":"Version","value":"1.2.3"}],"title":"Deploy"}]}`
+	if string(got) != want {
+		t.Errorf("ToJSON = %s, want %s", got, want)
+	}
+
+	if _, err := ToJSON([]byte("content: [unclosed")); err == nil {
+		t.Error("ToJSON of invalid YAML succeeded, want an error")
+	}
+}
+
+func TestParseFSLoadsYAML(t *testing.T) {
+	fsys := fstest.MapFS{
+		"notifications/maintenance.yaml": {Data: []byte("content: maintenance tonight\nembeds:\n  - title: Downtime\n    description: 22:00 to 23:00 UTC\n")},
+		"notifications/outage.yml":       {Data: []byte("content: outage\n")},
+		"notifications/deploy.tmpl":      {Data: []byte(`{"content": {{json .version}}}`)},
+	}
+	r, err := templates.ParseFS(fsys, "notifications/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := r.Execute("maintenance", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload.Content != "maintenance tonight" || len(payload.Embeds) != 1 || payload.Embeds[0].Title != "Downtime" {
+		t.Errorf("maintenance = %+v", payload)
+	}
+	if payload, err := r.Execute("outage", nil); err != nil || payload.Content != "outage" {
+		t.Errorf("outage = %+v, %v", payload, err)
+	}
+}
+
+func TestParseFSValidatesYAML(t *testing.T) {
+	for name, data := range map[string]string{
+		"invalid.yaml": "content: [unclosed",
+		"empty.yaml":   "username: ci\n",
+	} {
+		fsys := fstest.MapFS{"notifications/" + name: {Data: []byte(data)}}
+		if _, err := templates.ParseFS(fsys, "notifications/*"); err == nil || !strings.Contains(err.Error(), strings.TrimSuffix(name, ".yaml")) {
+			t.Errorf("ParseFS(%s) error = %v, want an error naming the file", name, err)
+		}
+	}
+}
+
+func TestLoadProfilesLoadsYAML(t *testing.T) {
+	t.Setenv("TEST_ALERTS_URL", "https://discord.com/api/webhooks/1/token")
+	path := filepath.Join(t.TempDir(), "webhooks.yml")
+	data := "prod-alerts:\n  url: ${TEST_ALERTS_URL}\n  username: Alerts\n  allowed_mentions:\n    parse: [roles]\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	profiles, err := webhook.LoadProfiles(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	destination, ok := profiles.Destination("prod-alerts")
+	if !ok || destination.URL != "https://discord.com/api/webhooks/1/token" || destination.Username != "Alerts" {
+		t.Errorf("destination = %+v, %v", destination, ok)
+	}
+	if destination.AllowedMentions == nil || len(destination.AllowedMentions.Parse) != 1 {
+		t.Errorf("allowed mentions = %+v, want parse [roles]", destination.AllowedMentions)
+	}
+
+	if err := os.WriteFile(path, []byte("prod-alerts: [unclosed"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := webhook.LoadProfiles(path); err == nil {
+		t.Error("LoadProfiles of invalid YAML succeeded, want an error")
+	}
+}